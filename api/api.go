@@ -5,10 +5,16 @@ import (
 
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	dphttp "github.com/ONSdigital/dp-net/v3/http"
+	"github.com/ONSdigital/dp-table-renderer/cache"
 	"github.com/ONSdigital/dp-table-renderer/config"
+	"github.com/ONSdigital/dp-table-renderer/metrics"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"net/http"
 
@@ -19,13 +25,15 @@ var httpServer *dphttp.Server
 
 // RendererAPI manages rendering tables from json
 type RendererAPI struct {
-	router *mux.Router
+	router  *mux.Router
+	cache   cache.Cache      // nil if the render cache is disabled
+	metrics *metrics.Metrics // nil if metrics are disabled
+	version string           // the version and commit of the build, which identify the renderer in the keys of its output
 }
 
 // CreateRendererAPI manages all the routes configured to the renderer
 func CreateRendererAPI(ctx context.Context, bindAddr string, allowedOrigins string, errorChan chan error, hc *healthcheck.HealthCheck) {
-	router := mux.NewRouter()
-	routes(router, hc)
+	router := NewRouter(hc)
 
 	cfg, err := config.Get()
 	if err != nil {
@@ -57,13 +65,22 @@ func createCORSHandler(allowedOrigins string, router *mux.Router) http.Handler {
 	headersOk := handlers.AllowedHeaders([]string{"Accept", "Content-Type", "Access-Control-Allow-Origin", "Access-Control-Allow-Methods", "X-Requested-With"})
 	originsOk := handlers.AllowedOrigins([]string{allowedOrigins})
 	methodsOk := handlers.AllowedMethods([]string{"GET", "POST", "OPTIONS"})
+	exposedOk := handlers.ExposedHeaders([]string{altTextHeader})
+
+	return handlers.CORS(originsOk, headersOk, methodsOk, exposedOk)(router)
+}
 
-	return handlers.CORS(originsOk, headersOk, methodsOk)(router)
+// NewRouter returns a router serving all the endpoints of the renderer, for use by CreateRendererAPI or to run the
+// renderer in-process, e.g. in tests
+func NewRouter(hc *healthcheck.HealthCheck) *mux.Router {
+	router := mux.NewRouter()
+	routes(router, hc)
+	return router
 }
 
 // routes contain all endpoints for the renderer
 func routes(router *mux.Router, hc *healthcheck.HealthCheck) *RendererAPI {
-	api := RendererAPI{router: router}
+	api := RendererAPI{router: router, version: hc.Version.Version + " " + hc.Version.GitCommit}
 
 	cfg, err := config.Get()
 	if err != nil {
@@ -71,6 +88,18 @@ func routes(router *mux.Router, hc *healthcheck.HealthCheck) *RendererAPI {
 		return nil
 	}
 
+	if cfg.RenderCacheEnabled {
+		api.cache = cache.NewLRU(cfg.RenderCacheMaxBytes)
+	}
+
+	if cfg.MetricsEnabled {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+		api.metrics = metrics.New(registry)
+		api.metrics.RegisterCache(api.cache)
+		api.router.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	}
+
 	var handler http.Handler
 
 	handleFunc := func(pattern string, handlerFunc func(http.ResponseWriter, *http.Request)) {
@@ -83,8 +112,16 @@ func routes(router *mux.Router, hc *healthcheck.HealthCheck) *RendererAPI {
 		api.router.Handle(pattern, handler)
 	}
 
-	handleFunc("/render/{render_type}", api.renderTable)
-	handleFunc("/parse/html", api.parseHTML)
+	handleFunc("/render/{render_type}", api.metrics.Instrument(metrics.OperationRender, renderTypeLabel, api.renderTable))
+	handleFunc("/parse/html", api.metrics.Instrument(metrics.OperationParse, parseTypeLabel("html"), api.parseHTML))
+	handleFunc("/parse/html/tables", api.metrics.Instrument(metrics.OperationParse, parseTypeLabel("html-tables"), api.listTables))
+	handleFunc("/parse/handsontable", api.metrics.Instrument(metrics.OperationParse, parseTypeLabel("handsontable"), api.parseHandsontable))
+	handleFunc("/parse/markdown", api.metrics.Instrument(metrics.OperationParse, parseTypeLabel("markdown"), api.parseMarkdown))
+	handleFunc("/parse/text", api.metrics.Instrument(metrics.OperationParse, parseTypeLabel("text"), api.parseText))
+	handleFunc("/parse/docx", api.metrics.Instrument(metrics.OperationParse, parseTypeLabel("docx"), api.parseDocx))
+	handleFunc("/parse/docx/tables", api.metrics.Instrument(metrics.OperationParse, parseTypeLabel("docx-tables"), api.listDocxTables))
+	handleFunc("/parse/ods", api.metrics.Instrument(metrics.OperationParse, parseTypeLabel("ods"), api.parseOds))
+	handleFunc("/capabilities", api.getCapabilities)
 
 	api.router.StrictSlash(true).Path("/health").HandlerFunc(hc.Handler)
 
@@ -101,6 +138,22 @@ func Close(ctx context.Context) error {
 	return nil
 }
 
+// renderTypeLabel returns the render type of a request for use as a metric label, limited to the supported types
+func renderTypeLabel(r *http.Request) string {
+	renderType := mux.Vars(r)["render_type"]
+	if _, ok := renderer.FindFormat(renderType); !ok {
+		return "unknown"
+	}
+	return renderType
+}
+
+// parseTypeLabel returns a function that labels every request with the given parse type
+func parseTypeLabel(parseType string) func(*http.Request) string {
+	return func(*http.Request) string {
+		return parseType
+	}
+}
+
 func HttpHandlerTag(route string, h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		h.ServeHTTP(w, r)
@@ -1,16 +1,26 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 
-	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-table-renderer/cache"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/testdata"
 	"github.com/gorilla/mux"
 	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 var (
@@ -41,6 +51,19 @@ func TestSuccessfullyRenderTable(t *testing.T) {
 		So(w.Body.String(), ShouldContainSubstring, "table_title")
 	})
 
+	Convey("Successfully list the tables in a page", t, func() {
+		reader := strings.NewReader(`{"table_html":"<h1>Page</h1><table id=\"t1\"><tr><td>a</td><td>b</td></tr></table>"}`)
+		r, err := http.NewRequest("POST", parseURL+"/tables", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+		So(w.Body.String(), ShouldEqual, `{"tables":[{"index":0,"id":"t1","title":"Page","rows":1,"columns":2}]}`)
+	})
+
 }
 
 func TestSuccessfullyRenderSpreadsheet(t *testing.T) {
@@ -95,6 +118,166 @@ func TestSuccessfullyParseTable(t *testing.T) {
 
 }
 
+func TestSuccessfullyParseHandsontable(t *testing.T) {
+	t.Parallel()
+	Convey("Successfully parse the state of a Handsontable editor", t, func() {
+		reader := strings.NewReader(`{"filename":"table1","handsontable":{"data":[["Year",null],[2020,"a"]],"mergeCells":[{"row":0,"col":0,"rowspan":1,"colspan":2}]}}`)
+		r, err := http.NewRequest("POST", host+"/parse/handsontable", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Body.String(), ShouldContainSubstring, "<table")
+		So(w.Body.String(), ShouldContainSubstring, `"colspan":2`)
+	})
+
+	Convey("A request without data is rejected", t, func() {
+		reader := strings.NewReader(`{"filename":"table1","handsontable":{"data":[]}}`)
+		r, err := http.NewRequest("POST", host+"/parse/handsontable", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeMissingFields)
+		So(response.Details["missing_fields"], ShouldResemble, []interface{}{"handsontable.data"})
+	})
+}
+
+func TestSuccessfullyParseMarkdownAndText(t *testing.T) {
+	t.Parallel()
+	Convey("Successfully parse a markdown table", t, func() {
+		reader := strings.NewReader(`{"filename":"table1","table_markdown":"| Year | Count |\n|---|--:|\n| 2020 | 5 |"}`)
+		r, err := http.NewRequest("POST", host+"/parse/markdown", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Body.String(), ShouldContainSubstring, "<table")
+		So(w.Body.String(), ShouldContainSubstring, `"align":"Right"`)
+	})
+
+	Convey("Successfully parse a table of fixed-width text", t, func() {
+		reader := strings.NewReader(`{"filename":"table1","table_text":"Year  Count\n2020      5\n2021     10"}`)
+		r, err := http.NewRequest("POST", host+"/parse/text", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Body.String(), ShouldContainSubstring, `"data":[["Year","Count"],["2020","5"],["2021","10"]]`)
+	})
+
+	Convey("Markdown without a table is rejected", t, func() {
+		reader := strings.NewReader(`{"filename":"table1","table_markdown":"# Just a heading"}`)
+		r, err := http.NewRequest("POST", host+"/parse/markdown", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+		So(decodeErrorResponse(w).Code, ShouldEqual, models.CodeTableNotFound)
+	})
+}
+
+func TestSuccessfullyParseDocx(t *testing.T) {
+	t.Parallel()
+	body, err := json.Marshal(models.ParseRequest{Filename: "table1", Docx: testdata.LoadExampleDocx(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Successfully list the tables in a Word document", t, func() {
+		r, err := http.NewRequest("POST", host+"/parse/docx/tables", strings.NewReader(string(body)))
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Body.String(), ShouldEqual, `{"tables":[{"index":0,"rows":2,"columns":2}]}`)
+	})
+
+	Convey("Successfully parse a table in a Word document", t, func() {
+		r, err := http.NewRequest("POST", host+"/parse/docx", strings.NewReader(string(body)))
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Body.String(), ShouldContainSubstring, `"data":[["Year","Count"],["2020","5"]]`)
+	})
+
+	Convey("A docx that isn't a Word document is rejected", t, func() {
+		reader := strings.NewReader(`{"filename":"table1","docx":"bm90IGEgZG9jeA=="}`)
+		r, err := http.NewRequest("POST", host+"/parse/docx", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+		So(decodeErrorResponse(w).Code, ShouldEqual, models.CodeInvalidDocument)
+	})
+}
+
+func TestSuccessfullyParseOds(t *testing.T) {
+	t.Parallel()
+	body, err := json.Marshal(models.ParseRequest{Filename: "table1", Ods: testdata.LoadExampleOds(t), Range: "A1:B2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Successfully parse a range of an OpenDocument spreadsheet", t, func() {
+		r, err := http.NewRequest("POST", host+"/parse/ods", strings.NewReader(string(body)))
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Body.String(), ShouldContainSubstring, `"data":[["Year","Count"],["2020","5"]]`)
+	})
+
+	Convey("A sheet that isn't in the spreadsheet is rejected", t, func() {
+		body, err := json.Marshal(models.ParseRequest{Filename: "table1", Ods: testdata.LoadExampleOds(t), Sheet: "Sheet2"})
+		So(err, ShouldBeNil)
+		r, err := http.NewRequest("POST", host+"/parse/ods", strings.NewReader(string(body)))
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+		So(decodeErrorResponse(w).Code, ShouldEqual, models.CodeTableNotFound)
+	})
+}
+
+func TestRenderKey(t *testing.T) {
+	Convey("The key of a render depends on the version of the renderer, as well as the request", t, func() {
+		request := &models.RenderRequest{Filename: "file_name", Data: [][]string{{"a"}}}
+		key, err := renderKey("1.0.0 abc123", "html", request)
+		So(err, ShouldBeNil)
+
+		same, err := renderKey("1.0.0 abc123", "html", &models.RenderRequest{Filename: "file_name", Data: [][]string{{"a"}}})
+		So(err, ShouldBeNil)
+		So(same, ShouldEqual, key)
+
+		deployed, err := renderKey("1.0.1 def456", "html", request)
+		So(err, ShouldBeNil)
+		So(deployed, ShouldNotEqual, key)
+	})
+}
+
 func TestRejectInvalidRequest(t *testing.T) {
 	t.Parallel()
 	Convey("Reject invalid render type in url with StatusNotFound", t, func() {
@@ -106,7 +289,11 @@ func TestRejectInvalidRequest(t *testing.T) {
 		api := routes(mux.NewRouter(), &hcMock)
 		api.router.ServeHTTP(w, r)
 		So(w.Code, ShouldEqual, http.StatusNotFound)
-		So(w.Body.String(), ShouldResemble, "Unknown render type\n")
+
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeUnknownRenderType)
+		So(response.Message, ShouldEqual, "Unknown render type")
+		So(response.Details["render_type"], ShouldEqual, "foo")
 	})
 
 	Convey("When an invalid json message is sent, a bad request is returned", t, func() {
@@ -118,9 +305,438 @@ func TestRejectInvalidRequest(t *testing.T) {
 		api := routes(mux.NewRouter(), &hcMock)
 		api.router.ServeHTTP(w, r)
 		So(w.Code, ShouldEqual, http.StatusBadRequest)
+		So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeInvalidJSON)
+		So(response.Message, ShouldEqual, models.ErrorParsingBody.Message)
+	})
+
+	Convey("When a request has a Content-Type other than json, an unsupported media type error is returned", t, func() {
+		reader := strings.NewReader(requestBody)
+		r, err := http.NewRequest("POST", requestHTMLURL, reader)
+		So(err, ShouldBeNil)
+		r.Header.Set("Content-Type", "text/plain")
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnsupportedMediaType)
+		So(decodeErrorResponse(w).Code, ShouldEqual, models.CodeUnsupportedMediaType)
+	})
+
+	Convey("A json Content-Type with a charset is accepted", t, func() {
+		reader := strings.NewReader(requestBody)
+		r, err := http.NewRequest("POST", requestHTMLURL, reader)
+		So(err, ShouldBeNil)
+		r.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+	})
+
+	Convey("When a render request has an unknown theme, an unprocessable entity error is returned", t, func() {
+		reader := strings.NewReader(`{"filename":"myId","theme":"unknown","data":[["a"]]}`)
+		r, err := http.NewRequest("POST", requestHTMLURL, reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeUnknownTheme)
+		So(response.Details["theme"], ShouldEqual, "unknown")
+	})
+
+	Convey("When a table is rendered as a png, its alt text is returned in a header", t, func() {
+		reader := strings.NewReader(`{"filename":"myId","title":"Prices","image":{"width":300},"data":[["a","1"]]}`)
+		r, err := http.NewRequest("POST", host+"/render/png", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Header().Get("Content-Type"), ShouldEqual, "image/png")
+		So(w.Header().Get("Alt-Text"), ShouldEqual, "Table: Prices. 1 row and 2 columns.")
+	})
+
+	Convey("When a table can't be drawn as an image, an unprocessable entity error is returned without alt text", t, func() {
+		reader := strings.NewReader(`{"filename":"myId","image":{"width":50},"data":[["a","1"]]}`)
+		r, err := http.NewRequest("POST", host+"/render/png", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+		So(w.Header().Get("Alt-Text"), ShouldBeEmpty)
+		So(decodeErrorResponse(w).Code, ShouldEqual, models.CodeInvalidImage)
+	})
+
+	Convey("When a table can't be drawn as a chart, an unprocessable entity error is returned", t, func() {
+		reader := strings.NewReader(`{"filename":"myId","chart":{"type":"pie"},"data":[["a","1"]]}`)
+		r, err := http.NewRequest("POST", host+"/render/svg-chart", reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeInvalidChart)
+		So(response.Details["type"], ShouldEqual, "pie")
+	})
+
+	Convey("When a parse request selects a table that doesn't exist, an unprocessable entity error is returned", t, func() {
+		reader := strings.NewReader(`{"table_html":"<table><tr><td>a</td></tr></table>","table_selector":"#missing"}`)
+		r, err := http.NewRequest("POST", parseURL, reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeTableNotFound)
+		So(response.Details["table_selector"], ShouldEqual, "#missing")
+	})
+
+	Convey("When a parse request is missing mandatory fields, an unprocessable entity error lists them", t, func() {
+		reader := strings.NewReader(`{"title":"table_title"}`)
+		r, err := http.NewRequest("POST", parseURL, reader)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeMissingFields)
+		So(response.Details["missing_fields"], ShouldResemble, []interface{}{"table_html"})
+	})
+
+	Convey("When a parse request does not contain a table, an unprocessable entity error is returned", t, func() {
+		reader := strings.NewReader(`{"table_html":"<div></div>"}`)
+		r, err := http.NewRequest("POST", parseURL, reader)
+		So(err, ShouldBeNil)
+		r = r.WithContext(request.WithRequestId(r.Context(), "myRequestID"))
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeInvalidTableHTML)
+		So(response.RequestID, ShouldEqual, "myRequestID")
+	})
+}
+
+func TestSetErrorCode(t *testing.T) {
+	t.Parallel()
+	Convey("An unexpected error is reported as an internal error without exposing its message", t, func() {
+		w := httptest.NewRecorder()
+		setErrorCode(context.Background(), w, errors.New("something secret went wrong"))
+
+		So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeInternal)
+		So(response.Message, ShouldEqual, internalError)
+		So(w.Body.String(), ShouldNotContainSubstring, "secret")
+	})
+
+	Convey("A render failure is reported as an internal error with the render_failed code", t, func() {
+		w := httptest.NewRecorder()
+		setErrorCode(context.Background(), w, models.NewError(models.CodeRenderFailed, "Failed to render the table as csv", errors.New("disk full")))
+
+		So(w.Code, ShouldEqual, http.StatusInternalServerError)
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeRenderFailed)
+		So(response.Message, ShouldEqual, internalError)
+	})
+
+	Convey("A table that exceeds a limit is reported as unprocessable with the limit that was exceeded", t, func() {
+		_, limitErr := models.CreateRenderRequestWithLimits(context.Background(), strings.NewReader(`{"data":[["a"],["b"]]}`), models.Limits{Rows: 1})
+		w := httptest.NewRecorder()
+		setErrorCode(context.Background(), w, limitErr)
+
+		So(w.Code, ShouldEqual, http.StatusUnprocessableEntity)
+		response := decodeErrorResponse(w)
+		So(response.Code, ShouldEqual, models.CodeTableTooLarge)
+		So(response.Details["limit"], ShouldEqual, "max_rows")
+		So(response.Details["max"], ShouldEqual, 1)
+	})
+
+	Convey("A body that exceeds the size limit is reported as too large", t, func() {
+		_, limitErr := models.CreateRenderRequestWithLimits(context.Background(), strings.NewReader(requestBody), models.Limits{BodyBytes: 10})
+		w := httptest.NewRecorder()
+		setErrorCode(context.Background(), w, limitErr)
+
+		So(w.Code, ShouldEqual, http.StatusRequestEntityTooLarge)
+		So(decodeErrorResponse(w).Code, ShouldEqual, models.CodeRequestTooLarge)
+	})
+}
+
+// decodeErrorResponse unmarshals the body of the recorder into an errorResponse
+func decodeErrorResponse(w *httptest.ResponseRecorder) errorResponse {
+	var response errorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	So(err, ShouldBeNil)
+	return response
+}
+
+func TestGetCapabilities(t *testing.T) {
+	t.Parallel()
+	Convey("The capabilities endpoint reports the supported formats and the configured limits", t, func() {
+		r, err := http.NewRequest("GET", host+"/capabilities", nil)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+		var response capabilitiesResponse
+		So(json.Unmarshal(w.Body.Bytes(), &response), ShouldBeNil)
+		So(response.RenderTypes, ShouldResemble, []string{"html", "html-document", "xlsx", "csv", "svg-chart", "png"})
+		So(response.ParseTypes, ShouldResemble, []string{"html", "handsontable", "markdown", "text", "docx", "ods"})
+		So(response.Themes, ShouldResemble, []string{"bare", "govuk", "ons"})
+		So(response.ImageThemes, ShouldResemble, []string{"bare", "dark", "govuk", "ons"})
+		So(response.Limits.BodyBytes, ShouldEqual, 50*1024*1024)
+		So(response.Limits.Rows, ShouldEqual, 250000)
+	})
+}
+
+func TestRenderCache(t *testing.T) {
+	t.Parallel()
+	Convey("Given an api with the render cache enabled", t, func() {
+		api := routes(mux.NewRouter(), &hcMock)
+		api.cache = cache.NewLRU(1024 * 1024)
+
+		render := func(url string, body string, ifNoneMatch string) *httptest.ResponseRecorder {
+			r, err := http.NewRequest("POST", url, strings.NewReader(body))
+			So(err, ShouldBeNil)
+			if len(ifNoneMatch) > 0 {
+				r.Header.Set("If-None-Match", ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			api.router.ServeHTTP(w, r)
+			return w
+		}
+
+		first := render(requestHTMLURL, requestBody, "")
+		So(first.Code, ShouldEqual, http.StatusOK)
+		etag := first.Header().Get("ETag")
+		So(etag, ShouldNotBeEmpty)
+		So(api.cache.Stats().Misses, ShouldEqual, 1)
+		So(api.cache.Stats().Entries, ShouldEqual, 1)
+
+		Convey("An equivalent request is served from the cache with the same etag", func() {
+			second := render(requestHTMLURL, `{"filename":"file_name","type":"table_type","title":"table_title"}`, "")
+			So(second.Code, ShouldEqual, http.StatusOK)
+			So(second.Header().Get("ETag"), ShouldEqual, etag)
+			So(second.Header().Get("Content-Type"), ShouldEqual, "text/html")
+			So(second.Body.String(), ShouldEqual, first.Body.String())
+			So(api.cache.Stats().Hits, ShouldEqual, 1)
+		})
+
+		Convey("The same request in a different format has a different etag", func() {
+			csv := render(requestCSVURL, requestBody, "")
+			So(csv.Code, ShouldEqual, http.StatusOK)
+			So(csv.Header().Get("ETag"), ShouldNotEqual, etag)
+			So(api.cache.Stats().Misses, ShouldEqual, 2)
+		})
+
+		Convey("A request with a matching If-None-Match returns 304 with no body", func() {
+			for _, header := range []string{etag, `"abc", ` + etag, "W/" + etag, "*"} {
+				notModified := render(requestHTMLURL, requestBody, header)
+				So(notModified.Code, ShouldEqual, http.StatusNotModified)
+				So(notModified.Header().Get("ETag"), ShouldEqual, etag)
+				So(notModified.Body.Len(), ShouldEqual, 0)
+			}
+		})
+
+		Convey("A request with a different If-None-Match is rendered", func() {
+			modified := render(requestHTMLURL, `{"title":"another_title", "filename": "file_name"}`, etag)
+			So(modified.Code, ShouldEqual, http.StatusOK)
+			So(modified.Header().Get("ETag"), ShouldNotEqual, etag)
+		})
+
+		Convey("The cache statistics are reported by the capabilities endpoint", func() {
+			r, err := http.NewRequest("GET", host+"/capabilities", nil)
+			So(err, ShouldBeNil)
+			w := httptest.NewRecorder()
+			api.router.ServeHTTP(w, r)
+
+			var response capabilitiesResponse
+			So(json.Unmarshal(w.Body.Bytes(), &response), ShouldBeNil)
+			So(response.RenderCache, ShouldNotBeNil)
+			So(response.RenderCache.Misses, ShouldEqual, 1)
+			So(response.RenderCache.Entries, ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given an api with the render cache disabled, responses still carry an etag", t, func() {
+		api := routes(mux.NewRouter(), &hcMock)
+		So(api.cache, ShouldBeNil)
+
+		r, err := http.NewRequest("POST", requestXLSXURL, strings.NewReader(requestBody))
+		So(err, ShouldBeNil)
+		w := httptest.NewRecorder()
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Header().Get("ETag"), ShouldNotBeEmpty)
+	})
+}
+
+func TestCachingWriter(t *testing.T) {
+	Convey("The cachingWriter discards its copy once the output exceeds the limit", t, func() {
+		var out strings.Builder
+		writer := &cachingWriter{w: &out, limit: 5}
+		writer.Write([]byte("abc"))
+		body, ok := writer.cacheable()
+		So(ok, ShouldBeTrue)
+		So(string(body), ShouldEqual, "abc")
+
+		writer.Write([]byte("def"))
+		_, ok = writer.cacheable()
+		So(ok, ShouldBeFalse)
+		So(out.String(), ShouldEqual, "abcdef")
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+	Convey("Requests to render and parse are reported by the metrics endpoint", t, func() {
+		api := routes(mux.NewRouter(), &hcMock)
+		So(api.metrics, ShouldNotBeNil)
+
+		for _, url := range []string{requestCSVURL, host + "/render/foo"} {
+			r, err := http.NewRequest("POST", url, strings.NewReader(requestBody))
+			So(err, ShouldBeNil)
+			api.router.ServeHTTP(httptest.NewRecorder(), r)
+		}
+		r, err := http.NewRequest("POST", parseURL, strings.NewReader(`{"filename": "file_name"}`))
+		So(err, ShouldBeNil)
+		api.router.ServeHTTP(httptest.NewRecorder(), r)
+
+		r, err = http.NewRequest("GET", host+"/metrics", nil)
+		So(err, ShouldBeNil)
+		w := httptest.NewRecorder()
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+
+		body := w.Body.String()
+		So(body, ShouldContainSubstring, `table_renderer_requests_total{operation="render",status="200",type="csv"} 1`)
+		So(body, ShouldContainSubstring, `table_renderer_requests_total{operation="render",status="404",type="unknown"} 1`)
+		So(body, ShouldContainSubstring, `table_renderer_rejections_total{code="unknown_render_type",operation="render"} 1`)
+		So(body, ShouldContainSubstring, `table_renderer_parse_outcomes_total{outcome="missing_fields",type="html"} 1`)
+		So(body, ShouldContainSubstring, `table_renderer_table_rows_count{type="csv"} 1`)
+		So(body, ShouldContainSubstring, `table_renderer_in_flight_requests{operation="render"} 0`)
+	})
+}
+
+// tracedRequest creates a request whose context contains a span from a tracer provider that exports to memory
+func tracedRequest(method string, url string, body string) (*http.Request, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx, _ := provider.Tracer("test").Start(context.Background(), "request")
+	r := httptest.NewRequest(method, url, strings.NewReader(body)).WithContext(ctx)
+	return r, exporter
+}
+
+// spanNamed returns the first exported span with the given name
+func spanNamed(exporter *tracetest.InMemoryExporter, name string) tracetest.SpanStub {
+	for _, span := range exporter.GetSpans() {
+		if span.Name == name {
+			return span
+		}
+	}
+	return tracetest.SpanStub{}
+}
+
+// childSpans returns the exported children of the parent span, indexed by name
+func childSpans(exporter *tracetest.InMemoryExporter, parent tracetest.SpanStub) map[string]tracetest.SpanStub {
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		if span.Parent.SpanID() == parent.SpanContext.SpanID() {
+			spans[span.Name] = span
+		}
+	}
+	return spans
+}
+
+func TestTracing(t *testing.T) {
+	t.Parallel()
+	Convey("Rendering a table creates a span for each step of the pipeline", t, func() {
+		body := `{"filename": "file_name", "data": [["a", "b"], ["c", "d"]], "cell_formats": [{"row": 0, "col": 0, "colspan": 2}]}`
+		r, exporter := tracedRequest("POST", requestXLSXURL, body)
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+
+		root := spanNamed(exporter, "render table")
+		So(root.Attributes, ShouldContain, attribute.String("table.format", "xlsx"))
+		So(root.Attributes, ShouldContain, attribute.String("table.filename", "file_name"))
+		So(root.Attributes, ShouldContain, attribute.Int("table.rows", 2))
+		So(root.Attributes, ShouldContain, attribute.Int("table.columns", 2))
+		So(root.Attributes, ShouldContain, attribute.Int("table.merges", 1))
+		So(root.Attributes, ShouldContain, attribute.Int("table.output_bytes", w.Body.Len()))
+
+		steps := childSpans(exporter, root)
+		So(steps, ShouldContainKey, "decode")
+		So(steps, ShouldContainKey, "validate")
+		So(steps, ShouldContainKey, "render")
+		So(steps["render"].Attributes, ShouldContain, attribute.Int("table.output_bytes", w.Body.Len()))
+
+		renderSteps := childSpans(exporter, steps["render"])
+		So(renderSteps, ShouldContainKey, "createModel")
+		So(renderSteps, ShouldContainKey, "write")
+	})
+
+	Convey("A failed request records the error on the span", t, func() {
+		r, exporter := tracedRequest("POST", requestHTMLURL, "{")
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusBadRequest)
+
+		root := spanNamed(exporter, "render table")
+		So(root.Status.Code, ShouldEqual, codes.Error)
+		steps := childSpans(exporter, root)
+		So(steps["decode"].Status.Code, ShouldEqual, codes.Error)
+		So(steps, ShouldNotContainKey, "render")
+	})
+
+	Convey("Parsing a table creates spans for the parse and the preview render", t, func() {
+		body := `{"filename": "file_name", "table_html": "<table><tr><td>a</td><td>b</td></tr></table>"}`
+		r, exporter := tracedRequest("POST", parseURL, body)
+		w := httptest.NewRecorder()
+		api := routes(mux.NewRouter(), &hcMock)
+		api.router.ServeHTTP(w, r)
+		So(w.Code, ShouldEqual, http.StatusOK)
+
+		root := spanNamed(exporter, "parse table")
+		So(root.Attributes, ShouldContain, attribute.String("table.filename", "file_name"))
+		steps := childSpans(exporter, root)
+		for _, name := range []string{"decode", "validate", "parse", "write"} {
+			So(steps, ShouldContainKey, name)
+		}
 
-		bodyBytes, _ := ioutil.ReadAll(w.Body)
-		response := string(bodyBytes)
-		So(response, ShouldResemble, "Bad request - Invalid request body\n")
+		// the parser builds the model, then renders the preview html
+		parseSteps := childSpans(exporter, steps["parse"])
+		So(parseSteps["createModel"].Attributes, ShouldContain, attribute.Int("table.rows", 1))
+		So(parseSteps["createModel"].Attributes, ShouldContain, attribute.Int("table.columns", 2))
+		So(childSpans(exporter, parseSteps["render"]), ShouldContainKey, "write")
 	})
 }
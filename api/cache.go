@@ -0,0 +1,63 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+)
+
+// renderKey identifies the output of a render request - a hash of the version of the renderer, the themes, the render
+// type and the canonical json of the request. Equivalent requests produce the same key regardless of the order of fields
+// or whitespace in the original json, but the key changes when a new build or a changed theme could change the output.
+func renderKey(version string, renderType string, request *models.RenderRequest) (string, error) {
+	hash := sha256.New()
+	io.WriteString(hash, version+"\n"+renderer.ThemesHash()+"\n"+renderType+"\n")
+	if err := json.NewEncoder(hash).Encode(request); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// etagMatches returns true if the value of an If-None-Match header matches the etag
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		// If-None-Match uses the weak comparison, so the W/ prefix is ignored
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// cachingWriter keeps a copy of everything written through it, so that the response can be cached once it is complete.
+// If the output grows beyond the limit the copy is discarded, but the output continues to be written.
+type cachingWriter struct {
+	w        io.Writer
+	buf      bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (c *cachingWriter) Write(p []byte) (int, error) {
+	if !c.overflow {
+		if int64(c.buf.Len()+len(p)) > c.limit {
+			c.overflow = true
+			c.buf = bytes.Buffer{}
+		} else {
+			c.buf.Write(p)
+		}
+	}
+	return c.w.Write(p)
+}
+
+// cacheable returns the complete output, or false if it was too large to keep
+func (c *cachingWriter) cacheable() ([]byte, bool) {
+	return c.buf.Bytes(), !c.overflow
+}
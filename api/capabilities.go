@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ONSdigital/dp-table-renderer/cache"
+	"github.com/ONSdigital/dp-table-renderer/config"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// the types of input accepted by /parse/{parse_type}
+var parseTypes = []string{"html", "handsontable", "markdown", "text", "docx", "ods"}
+
+// capabilitiesResponse describes the formats supported by the service and the limits it applies to requests
+type capabilitiesResponse struct {
+	RenderTypes []string      `json:"render_types"`
+	ParseTypes  []string      `json:"parse_types"`
+	Themes      []string      `json:"themes"`       // the themes that can be used to render html
+	ImageThemes []string      `json:"image_themes"` // the colour themes that can be used to render png
+	Limits      models.Limits `json:"limits"`
+	RenderCache *cache.Stats  `json:"render_cache,omitempty"` // only present if the render cache is enabled
+}
+
+func (api *RendererAPI) getCapabilities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cfg, err := config.Get()
+	if err != nil {
+		log.Error(ctx, "error getting config", err)
+		setErrorCode(ctx, w, err)
+		return
+	}
+
+	response := capabilitiesResponse{
+		RenderTypes: renderer.FormatNames(),
+		ParseTypes:  parseTypes,
+		Themes:      renderer.ThemeNames(),
+		ImageThemes: renderer.ImageThemeNames(),
+		Limits:      cfg.RequestLimits(),
+	}
+	if api.cache != nil {
+		stats := api.cache.Stats()
+		response.RenderCache = &stats
+	}
+	bytes, err := json.Marshal(response)
+	if err != nil {
+		log.Error(ctx, "error marshalling capabilities", err)
+		setErrorCode(ctx, w, err)
+		return
+	}
+
+	setContentType(w, contentJSON)
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(bytes); err != nil {
+		log.Error(ctx, "failed to write capabilities", err)
+	}
+}
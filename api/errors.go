@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-table-renderer/metrics"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// errorResponse is the json body returned for any request that fails
+type errorResponse struct {
+	Code      string                 `json:"code"`
+	Message   string                 `json:"message"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+// a map of error codes to the http status returned to the client. Codes that are not in the map are internal errors.
+var errorStatusMap = map[string]int{
+	models.CodeReadingBody:          http.StatusBadRequest,
+	models.CodeInvalidJSON:          http.StatusBadRequest,
+	models.CodeMissingData:          http.StatusBadRequest,
+	models.CodeRequestTooLarge:      http.StatusRequestEntityTooLarge,
+	models.CodeUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	models.CodeMissingFields:        http.StatusUnprocessableEntity,
+	models.CodeTableTooLarge:        http.StatusUnprocessableEntity,
+	models.CodeInvalidTableHTML:     http.StatusUnprocessableEntity,
+	models.CodeTableNotFound:        http.StatusUnprocessableEntity,
+	models.CodeInvalidDocument:      http.StatusUnprocessableEntity,
+	models.CodeInvalidCellFormat:    http.StatusUnprocessableEntity,
+	models.CodeUnknownTheme:         http.StatusUnprocessableEntity,
+	models.CodeInvalidChart:         http.StatusUnprocessableEntity,
+	models.CodeInvalidImage:         http.StatusUnprocessableEntity,
+	models.CodeUnknownRenderType:    http.StatusNotFound,
+}
+
+// errUnsupportedMediaType is returned when the request body is not json
+var errUnsupportedMediaType = models.NewError(models.CodeUnsupportedMediaType, "Content-Type must be "+contentJSON, nil)
+
+// checkContentType returns an error if the request declares a Content-Type that isn't json. A missing Content-Type is assumed to be json.
+func checkContentType(r *http.Request) error {
+	contentType := r.Header.Get("Content-Type")
+	if len(contentType) == 0 {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != contentJSON {
+		return errUnsupportedMediaType
+	}
+	return nil
+}
+
+// setErrorCode writes a json error response with the status appropriate to the given error
+func setErrorCode(ctx context.Context, w http.ResponseWriter, err error) {
+	response := errorResponse{
+		Code:      models.CodeInternal,
+		Message:   internalError,
+		RequestID: request.GetRequestId(ctx),
+	}
+	status := http.StatusInternalServerError
+
+	var modelError *models.Error
+	if errors.As(err, &modelError) {
+		if s, ok := errorStatusMap[modelError.Code]; ok {
+			status = s
+			response.Code = modelError.Code
+			response.Message = modelError.Message
+			response.Details = modelError.Details
+		} else {
+			// don't expose the details of internal errors, but do report the code
+			response.Code = modelError.Code
+		}
+	}
+	log.Error(ctx, "request failed", err, log.Data{"code": response.Code, "status": status})
+	tracing.RecordError(ctx, err)
+	if recorder, ok := w.(metrics.ErrorCodeRecorder); ok {
+		recorder.RecordErrorCode(response.Code)
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		log.Error(ctx, "unable to marshal error response", err)
+		http.Error(w, internalError, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentJSON)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if _, err = w.Write(body); err != nil {
+		log.Error(ctx, "failed to write error response", err)
+	}
+}
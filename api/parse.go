@@ -1,10 +1,13 @@
 package api
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/ONSdigital/dp-table-renderer/config"
 	"github.com/ONSdigital/dp-table-renderer/models"
 	"github.com/ONSdigital/dp-table-renderer/parser"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
@@ -13,36 +16,101 @@ var (
 	contentJSON = "application/json"
 )
 
+// parseFunc parses the html (or other input) of a request, returning the json response
+type parseFunc func(ctx context.Context, request *models.ParseRequest) ([]byte, error)
+
+// validateFunc checks the content of a parse request
+type validateFunc func(request *models.ParseRequest, ctx context.Context) error
+
 func (api *RendererAPI) parseHTML(w http.ResponseWriter, r *http.Request) {
+	api.parse(w, r, "parse table", "html", (*models.ParseRequest).ValidateParseRequest, parser.ParseHTML, "parsed an HTML table to JSON")
+}
+
+func (api *RendererAPI) listTables(w http.ResponseWriter, r *http.Request) {
+	api.parse(w, r, "list tables", "html", (*models.ParseRequest).ValidateParseRequest, parser.ListTables, "listed the tables in HTML")
+}
+
+func (api *RendererAPI) parseHandsontable(w http.ResponseWriter, r *http.Request) {
+	api.parse(w, r, "parse table", "handsontable", (*models.ParseRequest).ValidateHandsontableRequest, parser.ParseHandsontable, "parsed the state of a Handsontable editor to JSON")
+}
+
+func (api *RendererAPI) parseMarkdown(w http.ResponseWriter, r *http.Request) {
+	api.parse(w, r, "parse table", "markdown", (*models.ParseRequest).ValidateMarkdownRequest, parser.ParseMarkdown, "parsed a markdown table to JSON")
+}
+
+func (api *RendererAPI) parseText(w http.ResponseWriter, r *http.Request) {
+	api.parse(w, r, "parse table", "text", (*models.ParseRequest).ValidateTextRequest, parser.ParseText, "parsed a fixed-width text table to JSON")
+}
+
+func (api *RendererAPI) parseDocx(w http.ResponseWriter, r *http.Request) {
+	api.parse(w, r, "parse table", "docx", (*models.ParseRequest).ValidateDocxRequest, parser.ParseDocx, "parsed a table in a Word document to JSON")
+}
+
+func (api *RendererAPI) parseOds(w http.ResponseWriter, r *http.Request) {
+	api.parse(w, r, "parse table", "ods", (*models.ParseRequest).ValidateOdsRequest, parser.ParseOds, "parsed a range of an OpenDocument spreadsheet to JSON")
+}
+
+func (api *RendererAPI) listDocxTables(w http.ResponseWriter, r *http.Request) {
+	api.parse(w, r, "list tables", "docx", (*models.ParseRequest).ValidateDocxRequest, parser.ListDocxTables, "listed the tables in a Word document")
+}
 
-	ctx := r.Context()
+// parse handles a parse request of the given input format, writing the response of the parse function
+func (api *RendererAPI) parse(w http.ResponseWriter, r *http.Request, spanName string, format string, validate validateFunc, parse parseFunc, message string) {
 
-	parseRequest, err := models.CreateParseRequest(ctx, r.Body)
+	ctx, span := tracing.StartSpan(r.Context(), spanName, tracing.Format.String(format))
+	defer span.End()
+
+	cfg, err := config.Get()
+	if err != nil {
+		log.Error(ctx, "error getting config", err)
+		setErrorCode(ctx, w, err)
+		return
+	}
+
+	if err = checkContentType(r); err != nil {
+		setErrorCode(ctx, w, err)
+		return
+	}
+
+	_, decodeSpan := tracing.StartSpan(ctx, "decode")
+	parseRequest, err := models.CreateParseRequestWithLimits(ctx, r.Body, cfg.RequestLimits())
+	tracing.EndSpan(decodeSpan, err)
 	if err != nil {
 		log.Error(ctx, "error occurred when trying to create model parse request", err)
-		http.Error(w, badRequest, http.StatusBadRequest)
+		setErrorCode(ctx, w, err)
 		return
 	}
+	span.SetAttributes(tracing.Filename.String(parseRequest.Filename))
 
-	if err = parseRequest.ValidateParseRequest(ctx); err != nil {
+	_, validateSpan := tracing.StartSpan(ctx, "validate")
+	err = validate(parseRequest, ctx)
+	tracing.EndSpan(validateSpan, err)
+	if err != nil {
 		log.Error(ctx, "error occurred when trying to validate model parse request", err)
-		http.Error(w, badRequest, http.StatusBadRequest)
+		setErrorCode(ctx, w, err)
 		return
 	}
 
-	bytes, err := parser.ParseHTML(ctx, parseRequest)
-	setContentType(w, contentJSON)
+	parseCtx, parseSpan := tracing.StartSpan(ctx, "parse", tracing.Format.String(format))
+	bytes, err := parse(parseCtx, parseRequest)
+	tracing.EndSpan(parseSpan, err)
 	if err != nil {
-		log.Error(ctx, "error occurred when trying to parse HTML", err)
+		log.Error(ctx, "error occurred when trying to parse table", err)
 		setErrorCode(ctx, w, err)
 		return
 	}
 
+	setContentType(w, contentJSON)
 	w.WriteHeader(http.StatusOK)
-	if _, err = w.Write(bytes); err != nil {
-		log.Error(ctx, "error occurred when trying to parse HTML", err)
-		setErrorCode(ctx, w, err)
+	_, writeSpan := tracing.StartSpan(ctx, "write", tracing.OutputBytes.Int(len(bytes)))
+	_, err = w.Write(bytes)
+	tracing.EndSpan(writeSpan, err)
+	if err != nil {
+		// the status has already been written, so all we can do is log the error
+		log.Error(ctx, "error occurred when trying to write parsed table", err)
+		tracing.RecordError(ctx, err)
 		return
 	}
-	log.Info(ctx, "parsed an HTML table to JSON", log.Data{"response_bytes": len(bytes)})
+	span.SetAttributes(tracing.OutputBytes.Int(len(bytes)))
+	log.Info(ctx, message, log.Data{"response_bytes": len(bytes)})
 }
@@ -2,110 +2,166 @@ package api
 
 import (
 	"context"
-	"errors"
+	"io"
 	"net/http"
 
 	"github.com/ONSdigital/dp-table-renderer/config"
 	"github.com/ONSdigital/dp-table-renderer/models"
 	"github.com/ONSdigital/dp-table-renderer/renderer"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
 	"github.com/ONSdigital/log.go/v2/log"
-	"go.opentelemetry.io/otel"
 
 	"github.com/gorilla/mux"
 )
 
-// Error types
+// Error messages
 var (
-	internalError     = "Failed to process the request due to an internal error"
-	badRequest        = "Bad request - Invalid request body"
-	unknownRenderType = "Unknown render type"
-	statusBadRequest  = "bad request"
+	internalError = "Failed to process the request due to an internal error"
 )
 
-// Content types
-var (
-	contentHTML = "text/html"
-	contentXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
-	contentCSV  = "text/csv"
-)
+// altTextHeader is the response header that holds the text alternative of an image, as utf-8
+const altTextHeader = "Alt-Text"
+
+// countingWriter counts the bytes written to the response, so that we know whether an error can still be reported to the client
+type countingWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.count += n
+	return n, err
+}
 
 func (api *RendererAPI) renderTable(w http.ResponseWriter, r *http.Request) {
 
 	vars := mux.Vars(r)
 	renderType := vars["render_type"]
-	ctx := r.Context()
+
+	ctx, span := tracing.StartSpan(r.Context(), "render table", tracing.Format.String(renderType))
+	defer span.End()
 
 	cfg, err := config.Get()
 	if err != nil {
 		log.Error(ctx, "error getting config", err)
+		setErrorCode(ctx, w, err)
+		return
+	}
+
+	format, ok := renderer.FindFormat(renderType)
+	if !ok {
+		setErrorCode(ctx, w, &models.Error{
+			Code:    models.CodeUnknownRenderType,
+			Message: "Unknown render type",
+			Details: map[string]interface{}{"render_type": renderType},
+		})
 		return
 	}
 
-	if cfg.OtelEnabled {
-		tracer := otel.GetTracerProvider().Tracer("tablerenderer")
-		_, span := tracer.Start(r.Context(), "table render span")
-		defer span.End()
+	if err = checkContentType(r); err != nil {
+		setErrorCode(ctx, w, err)
+		return
 	}
 
-	renderRequest, err := models.CreateRenderRequest(ctx, r.Body)
+	_, decodeSpan := tracing.StartSpan(ctx, "decode")
+	renderRequest, err := models.CreateRenderRequestWithLimits(ctx, r.Body, cfg.RequestLimits())
+	tracing.EndSpan(decodeSpan, err)
 	if err != nil {
 		log.Error(ctx, "error with creating model render request", err)
-		http.Error(w, badRequest, http.StatusBadRequest)
+		setErrorCode(ctx, w, err)
 		return
 	}
+	span.SetAttributes(tracing.Filename.String(renderRequest.Filename))
+	span.SetAttributes(tracing.TableAttributes(renderRequest)...)
 
-	if err = renderRequest.ValidateRenderRequest(); err != nil {
+	_, validateSpan := tracing.StartSpan(ctx, "validate")
+	err = renderRequest.ValidateRenderRequest()
+	tracing.EndSpan(validateSpan, err)
+	if err != nil {
 		log.Error(ctx, "error with validating model render request", err)
-		http.Error(w, badRequest, http.StatusBadRequest)
+		setErrorCode(ctx, w, err)
 		return
 	}
 
-	var bytes []byte
-
-	switch renderType {
-	case "html":
-		bytes, err = renderer.RenderHTML(ctx, renderRequest)
-		setContentType(w, contentHTML)
-	case "xlsx":
-		bytes, err = renderer.RenderXLSX(ctx, renderRequest)
-		setContentType(w, contentXLSX)
-	case "csv":
-		bytes, err = renderer.RenderCSV(ctx, renderRequest)
-		setContentType(w, contentCSV)
-	default:
-		log.Error(ctx, "Unknown render type", errors.New("Unknown render type"))
-		http.Error(w, unknownRenderType, http.StatusNotFound)
-		return
-	}
+	api.metrics.ObserveTable(renderType, renderRequest)
+
+	key, err := renderKey(api.version, renderType, renderRequest)
 	if err != nil {
-		log.Error(ctx, "Unknown render request", err)
+		log.Error(ctx, "error calculating the render key", err)
 		setErrorCode(ctx, w, err)
 		return
 	}
+	etag := `"` + key + `"`
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if api.cache != nil {
+		if body, ok := api.cache.Get(key); ok {
+			setFormatHeaders(ctx, w, format, renderRequest)
+			w.Header().Set("ETag", etag)
+			_, writeSpan := tracing.StartSpan(ctx, "write", tracing.Format.String(renderType), tracing.OutputBytes.Int(len(body)))
+			_, err = w.Write(body)
+			tracing.EndSpan(writeSpan, err)
+			if err != nil {
+				log.Error(ctx, "failed to write data to connection", err, log.Data{"file_name": renderRequest.Filename})
+				tracing.RecordError(ctx, err)
+				return
+			}
+			span.SetAttributes(tracing.OutputBytes.Int(len(body)))
+			log.Info(ctx, "rendered a table from the cache", log.Data{"file_name": renderRequest.Filename, "response_bytes": len(body)})
+			return
+		}
+	}
 
-	w.WriteHeader(http.StatusOK)
-	_, err = w.Write(bytes)
+	// the table is written straight to the response - the status is sent with the first bytes written
+	setFormatHeaders(ctx, w, format, renderRequest)
+	w.Header().Set("ETag", etag)
+	out := &countingWriter{w: w}
+	var cached *cachingWriter
+	var target io.Writer = out
+	if api.cache != nil {
+		cached = &cachingWriter{w: out, limit: cfg.RenderCacheMaxBytes}
+		target = cached
+	}
+	renderCtx, renderSpan := tracing.StartSpan(ctx, "render", tracing.Format.String(renderType))
+	err = format.Write(renderCtx, target, renderRequest)
+	renderSpan.SetAttributes(tracing.OutputBytes.Int(out.count))
+	tracing.EndSpan(renderSpan, err)
+	span.SetAttributes(tracing.OutputBytes.Int(out.count))
 	if err != nil {
-		log.Error(ctx, "failed to write data to connection", err)
-		setErrorCode(ctx, w, err)
+		if out.count == 0 {
+			w.Header().Del("ETag")
+			w.Header().Del(altTextHeader)
+			setErrorCode(ctx, w, err)
+		} else {
+			// the status has already been written, so all we can do is log the error
+			log.Error(ctx, "failed to write data to connection", err, log.Data{"file_name": renderRequest.Filename, "response_bytes": out.count})
+			tracing.RecordError(ctx, err)
+		}
 		return
 	}
 
-	log.Info(ctx, "rendered a table", log.Data{"file_name": renderRequest.Filename, "response_bytes": len(bytes)})
-}
+	if cached != nil {
+		if body, ok := cached.cacheable(); ok {
+			api.cache.Set(key, body)
+		}
+	}
 
-func setContentType(w http.ResponseWriter, contentType string) {
-	w.Header().Set("Content-Type", contentType)
+	log.Info(ctx, "rendered a table", log.Data{"file_name": renderRequest.Filename, "response_bytes": out.count})
 }
 
-func setErrorCode(ctx context.Context, w http.ResponseWriter, err error) {
-	log.Error(ctx, "error code:", err)
-	switch err.Error() {
-	case "Bad request":
-		http.Error(w, "Bad request", http.StatusBadRequest)
-		return
-	default:
-		http.Error(w, internalError, http.StatusInternalServerError)
-		return
+// setFormatHeaders sets the content type of the format, and the alt text of an image format
+func setFormatHeaders(ctx context.Context, w http.ResponseWriter, format renderer.Format, request *models.RenderRequest) {
+	setContentType(w, format.ContentType)
+	if format.AltText != nil {
+		w.Header().Set(altTextHeader, format.AltText(ctx, request))
 	}
 }
+
+func setContentType(w http.ResponseWriter, contentType string) {
+	w.Header().Set("Content-Type", contentType)
+}
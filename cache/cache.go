@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache stores rendered tables, keyed by a hash of the request that produced them
+type Cache interface {
+	// Get returns the value stored for the key, and whether it was found
+	Get(key string) ([]byte, bool)
+	// Set stores the value for the key, replacing any existing value
+	Set(key string, value []byte)
+	// Stats reports the current size of the cache and how effective it has been
+	Stats() Stats
+}
+
+// Stats describes the state of a Cache
+type Stats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+	Bytes     int64 `json:"bytes"`
+	MaxBytes  int64 `json:"max_bytes"`
+}
+
+// LRU is an in-memory Cache that holds at most maxBytes of values, discarding the least recently used entries to make space
+type LRU struct {
+	mutex    sync.Mutex
+	maxBytes int64
+	entries  map[string]*list.Element
+	order    *list.List // most recently used at the front
+	stats    Stats
+}
+
+type entry struct {
+	key   string
+	value []byte
+}
+
+// NewLRU creates an empty LRU cache with the given size budget
+func NewLRU(maxBytes int64) *LRU {
+	return &LRU{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		stats:    Stats{MaxBytes: maxBytes},
+	}
+}
+
+// size is the number of bytes an entry counts against the budget
+func (e *entry) size() int64 {
+	return int64(len(e.key) + len(e.value))
+}
+
+// Get returns the value stored for the key, marking it as the most recently used
+func (c *LRU) Get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	c.stats.Hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*entry).value, true
+}
+
+// Set stores the value for the key, evicting the least recently used entries if necessary.
+// A value that is larger than the whole budget is not stored.
+func (c *LRU) Set(key string, value []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	e := &entry{key: key, value: value}
+	if e.size() > c.maxBytes {
+		return
+	}
+	for c.stats.Bytes+e.size() > c.maxBytes {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+	c.entries[key] = c.order.PushFront(e)
+	c.stats.Entries++
+	c.stats.Bytes += e.size()
+}
+
+// remove deletes an element from the cache
+func (c *LRU) remove(element *list.Element) {
+	e := c.order.Remove(element).(*entry)
+	delete(c.entries, e.key)
+	c.stats.Entries--
+	c.stats.Bytes -= e.size()
+}
+
+// Stats returns a snapshot of the cache statistics
+func (c *LRU) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stats
+}
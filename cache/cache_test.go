@@ -0,0 +1,63 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/cache"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLRU(t *testing.T) {
+	Convey("Given an empty cache", t, func() {
+		c := cache.NewLRU(20)
+
+		Convey("A missing key is not found and counted as a miss", func() {
+			_, ok := c.Get("a")
+			So(ok, ShouldBeFalse)
+			So(c.Stats().Misses, ShouldEqual, 1)
+		})
+
+		Convey("A stored value is returned and counted as a hit", func() {
+			c.Set("a", []byte("12345"))
+			value, ok := c.Get("a")
+			So(ok, ShouldBeTrue)
+			So(string(value), ShouldEqual, "12345")
+			So(c.Stats(), ShouldResemble, cache.Stats{Hits: 1, Entries: 1, Bytes: 6, MaxBytes: 20})
+		})
+
+		Convey("Replacing a value doesn't count it twice", func() {
+			c.Set("a", []byte("12345"))
+			c.Set("a", []byte("123"))
+			value, _ := c.Get("a")
+			So(string(value), ShouldEqual, "123")
+			So(c.Stats().Entries, ShouldEqual, 1)
+			So(c.Stats().Bytes, ShouldEqual, 4)
+		})
+
+		Convey("The least recently used entries are evicted when the budget is exceeded", func() {
+			c.Set("a", []byte("12345"))
+			c.Set("b", []byte("12345"))
+			c.Set("c", []byte("12345"))
+			c.Get("a")
+			c.Set("d", []byte("12345"))
+
+			_, ok := c.Get("b")
+			So(ok, ShouldBeFalse)
+			for _, key := range []string{"a", "c", "d"} {
+				_, ok = c.Get(key)
+				So(ok, ShouldBeTrue)
+			}
+			So(c.Stats().Evictions, ShouldEqual, 1)
+			So(c.Stats().Bytes, ShouldEqual, 18)
+		})
+
+		Convey("A value larger than the budget is not stored", func() {
+			c.Set("a", []byte("12345"))
+			c.Set("b", make([]byte, 20))
+			_, ok := c.Get("b")
+			So(ok, ShouldBeFalse)
+			_, ok = c.Get("a")
+			So(ok, ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+)
+
+// BatchItem is a table to render as part of a batch
+type BatchItem struct {
+	Format  string
+	Request *models.RenderRequest
+}
+
+// BatchResult is the outcome of rendering a BatchItem: either the rendered table or the error that prevented it
+type BatchResult struct {
+	Body []byte
+	Err  error
+}
+
+// Batch renders each of the items, making up to the configured number of requests at once.
+// The results are in the same order as the items. A failure to render one item doesn't prevent the others being rendered.
+func (c *Client) Batch(ctx context.Context, items []BatchItem) []BatchResult {
+	results := make([]BatchResult, len(items))
+	slots := make(chan struct{}, c.batchConcurrency)
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(i int, item BatchItem) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			results[i].Body, results[i].Err = c.renderBytes(ctx, item)
+		}(i, item)
+	}
+	wg.Wait()
+	return results
+}
+
+// renderBytes renders a batch item, reading the whole of the response
+func (c *Client) renderBytes(ctx context.Context, item BatchItem) ([]byte, error) {
+	body, err := c.Render(ctx, item.Format, item.Request)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
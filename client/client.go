@@ -0,0 +1,236 @@
+// Package client is a Go client for the table renderer api.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+)
+
+const (
+	defaultRetries          = 3
+	defaultBackoff          = 100 * time.Millisecond
+	defaultMaxBackoff       = 2 * time.Second
+	defaultBatchConcurrency = 4
+
+	contentJSON = "application/json"
+)
+
+// Client makes requests to a table renderer
+type Client struct {
+	url              string
+	httpClient       *http.Client
+	retries          int
+	backoff          time.Duration
+	maxBackoff       time.Duration
+	batchConcurrency int
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the http client used to make requests. The default is http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets the number of times a request is retried after a network error or a 429, 502, 503 or 504 response
+func WithRetries(retries int) Option {
+	return func(c *Client) {
+		c.retries = retries
+	}
+}
+
+// WithBackoff sets the delay before the first retry, and the maximum delay. The delay doubles after each retry.
+func WithBackoff(initial, max time.Duration) Option {
+	return func(c *Client) {
+		c.backoff = initial
+		c.maxBackoff = max
+	}
+}
+
+// WithBatchConcurrency sets the maximum number of requests made at once by Batch
+func WithBatchConcurrency(concurrency int) Option {
+	return func(c *Client) {
+		if concurrency > 0 {
+			c.batchConcurrency = concurrency
+		}
+	}
+}
+
+// New creates a client for the renderer at the given url, e.g. http://localhost:23300
+func New(url string, options ...Option) *Client {
+	c := &Client{
+		url:              strings.TrimSuffix(url, "/"),
+		httpClient:       http.DefaultClient,
+		retries:          defaultRetries,
+		backoff:          defaultBackoff,
+		maxBackoff:       defaultMaxBackoff,
+		batchConcurrency: defaultBatchConcurrency,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// URL returns the url of the renderer
+func (c *Client) URL() string {
+	return c.url
+}
+
+// Render renders a table in the given format (html, csv, xlsx etc). The caller must close the returned body.
+func (c *Client) Render(ctx context.Context, format string, renderRequest *models.RenderRequest) (io.ReadCloser, error) {
+	body, err := json.Marshal(renderRequest)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.post(ctx, "/render/"+format, body)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Parse converts an html table into the json used to render it, along with a preview of the rendered table
+func (c *Client) Parse(ctx context.Context, parseRequest *models.ParseRequest) (*parser.ResponseModel, error) {
+	return c.parse(ctx, "/parse/html", parseRequest)
+}
+
+// ParseHandsontable converts the state of a Handsontable editor, given in the Handsontable field of the request, into
+// the json used to render the table, along with a preview of the rendered table
+func (c *Client) ParseHandsontable(ctx context.Context, parseRequest *models.ParseRequest) (*parser.ResponseModel, error) {
+	return c.parse(ctx, "/parse/handsontable", parseRequest)
+}
+
+// ParseMarkdown converts the first pipe table in the TableMarkdown of the request into the json used to render it,
+// along with a preview of the rendered table
+func (c *Client) ParseMarkdown(ctx context.Context, parseRequest *models.ParseRequest) (*parser.ResponseModel, error) {
+	return c.parse(ctx, "/parse/markdown", parseRequest)
+}
+
+// ParseText converts the table of fixed-width text in the TableText of the request into the json used to render it,
+// along with a preview of the rendered table
+func (c *Client) ParseText(ctx context.Context, parseRequest *models.ParseRequest) (*parser.ResponseModel, error) {
+	return c.parse(ctx, "/parse/text", parseRequest)
+}
+
+// ParseDocx converts a table of the Word document in the Docx of the request, chosen by its TableSelector, into the
+// json used to render it, along with a preview of the rendered table
+func (c *Client) ParseDocx(ctx context.Context, parseRequest *models.ParseRequest) (*parser.ResponseModel, error) {
+	return c.parse(ctx, "/parse/docx", parseRequest)
+}
+
+// ParseOds converts a range of a sheet of the OpenDocument spreadsheet in the Ods of the request, chosen by its Sheet
+// and Range, into the json used to render it, along with a preview of the rendered table
+func (c *Client) ParseOds(ctx context.Context, parseRequest *models.ParseRequest) (*parser.ResponseModel, error) {
+	return c.parse(ctx, "/parse/ods", parseRequest)
+}
+
+// ListTables lists the tables in the html of the request, so that one can be chosen with its TableSelector
+func (c *Client) ListTables(ctx context.Context, parseRequest *models.ParseRequest) (*parser.TablesResponse, error) {
+	return c.listTables(ctx, "/parse/html/tables", parseRequest)
+}
+
+// ListDocxTables lists the tables in the Word document in the Docx of the request, so that one can be chosen with its
+// TableSelector
+func (c *Client) ListDocxTables(ctx context.Context, parseRequest *models.ParseRequest) (*parser.TablesResponse, error) {
+	return c.listTables(ctx, "/parse/docx/tables", parseRequest)
+}
+
+// listTables posts a request to list tables to the path, decoding the response
+func (c *Client) listTables(ctx context.Context, path string, parseRequest *models.ParseRequest) (*parser.TablesResponse, error) {
+	body, err := json.Marshal(parseRequest)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.post(ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response parser.TablesResponse
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode tables response: %w", err)
+	}
+	return &response, nil
+}
+
+// parse posts a parse request to the path, decoding the response
+func (c *Client) parse(ctx context.Context, path string, parseRequest *models.ParseRequest) (*parser.ResponseModel, error) {
+	body, err := json.Marshal(parseRequest)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.post(ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response parser.ResponseModel
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode parse response: %w", err)
+	}
+	return &response, nil
+}
+
+// post sends a json body to the path, retrying if the renderer is unavailable. Any response other than 200 is
+// returned as an *APIError.
+func (c *Client) post(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.do(ctx, http.MethodPost, path, body)
+		if err == nil && resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		if err == nil {
+			err = newAPIError(resp)
+		}
+		if attempt >= c.retries || !retryable(err) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+// do makes a single request, passing on the request id in the context
+func (c *Client) do(ctx context.Context, method string, path string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentJSON)
+	}
+	if requestID := request.GetRequestId(ctx); len(requestID) > 0 {
+		req.Header.Set(request.RequestHeaderKey, requestID)
+	}
+	return c.httpClient.Do(req)
+}
@@ -0,0 +1,262 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-table-renderer/api"
+	"github.com/ONSdigital/dp-table-renderer/client"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	"github.com/ONSdigital/dp-table-renderer/testdata"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var renderRequest = &models.RenderRequest{Filename: "table1", Title: "A table", Data: [][]string{{"a", "b"}, {"1", "2"}}}
+
+// newHealthCheck creates a health check with no dependencies, which is always healthy
+func newHealthCheck(t *testing.T) healthcheck.HealthCheck {
+	version, err := healthcheck.NewVersionInfo("1", "abc", "1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return healthcheck.New(version, time.Minute, time.Minute)
+}
+
+// newRenderer starts a server running the real renderer api
+func newRenderer(t *testing.T) *httptest.Server {
+	hc := newHealthCheck(t)
+	server := httptest.NewServer(api.NewRouter(&hc))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newFlakyServer starts a server that responds with the given status to the first failures requests, then with the renderer api
+func newFlakyServer(t *testing.T, status int, failures int32) (*httptest.Server, *int32) {
+	hc := newHealthCheck(t)
+	router := api.NewRouter(&hc)
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &count
+}
+
+func TestRender(t *testing.T) {
+	Convey("Given a client of a running renderer", t, func() {
+		c := client.New(newRenderer(t).URL)
+
+		Convey("A table is rendered in the requested format", func() {
+			body, err := c.Render(context.Background(), "csv", renderRequest)
+			So(err, ShouldBeNil)
+			defer body.Close()
+			content, err := io.ReadAll(body)
+			So(err, ShouldBeNil)
+			So(string(content), ShouldContainSubstring, "a,b\n1,2\n")
+		})
+
+		Convey("An error response is returned as an APIError that matches the model error", func() {
+			_, err := c.Render(context.Background(), "pdf", renderRequest)
+			So(errors.Is(err, &models.Error{Code: models.CodeUnknownRenderType}), ShouldBeTrue)
+
+			var apiError *client.APIError
+			So(errors.As(err, &apiError), ShouldBeTrue)
+			So(apiError.StatusCode, ShouldEqual, http.StatusNotFound)
+			So(apiError.Message, ShouldEqual, "Unknown render type")
+		})
+	})
+}
+
+func TestParse(t *testing.T) {
+	Convey("Given a client of a running renderer", t, func() {
+		c := client.New(newRenderer(t).URL)
+
+		Convey("An html table is parsed", func() {
+			response, err := c.Parse(context.Background(), &models.ParseRequest{
+				Filename:   "table1",
+				TableHTML:  "<table><tr><th>Year</th></tr><tr><td>2020</td></tr></table>",
+				HeaderRows: 1,
+			})
+			So(err, ShouldBeNil)
+			So(response.JSON.Data, ShouldResemble, [][]string{{"Year"}, {"2020"}})
+			So(response.PreviewHTML, ShouldContainSubstring, "<table")
+		})
+
+		Convey("The details of an invalid request are returned", func() {
+			_, err := c.Parse(context.Background(), &models.ParseRequest{Filename: "table1"})
+			var apiError *client.APIError
+			So(errors.As(err, &apiError), ShouldBeTrue)
+			So(apiError.Code, ShouldEqual, models.CodeMissingFields)
+			So(apiError.Details["missing_fields"], ShouldResemble, []interface{}{"table_html"})
+		})
+
+		Convey("The state of a Handsontable editor is parsed", func() {
+			response, err := c.ParseHandsontable(context.Background(), &models.ParseRequest{
+				Filename:   "table1",
+				HeaderRows: 1,
+				Handsontable: &models.HandsontableState{
+					Data: [][]models.HandsontableValue{{"Year"}, {"2020"}},
+					Cell: []models.HandsontableCell{{Row: 1, Col: 0, ClassName: "htRight"}},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(response.JSON.Data, ShouldResemble, [][]string{{"Year"}, {"2020"}})
+			So(response.PreviewHTML, ShouldContainSubstring, "<table")
+		})
+
+		Convey("Markdown and fixed-width text tables are parsed", func() {
+			response, err := c.ParseMarkdown(context.Background(), &models.ParseRequest{
+				Filename:      "table1",
+				TableMarkdown: "| Year |\n|---|\n| 2020 |",
+			})
+			So(err, ShouldBeNil)
+			So(response.JSON.Data, ShouldResemble, [][]string{{"Year"}, {"2020"}})
+
+			response, err = c.ParseText(context.Background(), &models.ParseRequest{
+				Filename:  "table1",
+				TableText: "Year\n----\n2020",
+			})
+			So(err, ShouldBeNil)
+			So(response.JSON.Data, ShouldResemble, [][]string{{"Year"}, {"2020"}})
+			So(response.Inferred.HeaderRows, ShouldEqual, 1)
+		})
+
+		Convey("The tables in a page are listed", func() {
+			response, err := c.ListTables(context.Background(), &models.ParseRequest{
+				Filename:  "page1",
+				TableHTML: "<h2>Years</h2><table id=\"years\"><tr><td>2020</td><td>2021</td></tr></table><table><tr><td>a</td></tr></table>",
+			})
+			So(err, ShouldBeNil)
+			So(response.Tables, ShouldHaveLength, 2)
+			So(response.Tables[0], ShouldResemble, parser.TableSummary{Index: 0, ID: "years", Title: "Years", Rows: 1, Columns: 2})
+		})
+
+		Convey("The tables in a Word document are listed and parsed", func() {
+			request := &models.ParseRequest{Filename: "document1", Docx: testdata.LoadExampleDocx(t)}
+			tables, err := c.ListDocxTables(context.Background(), request)
+			So(err, ShouldBeNil)
+			So(tables.Tables, ShouldResemble, []parser.TableSummary{{Index: 0, Rows: 2, Columns: 2}})
+
+			response, err := c.ParseDocx(context.Background(), request)
+			So(err, ShouldBeNil)
+			So(response.JSON.Data, ShouldResemble, [][]string{{"Year", "Count"}, {"2020", "5"}})
+		})
+
+		Convey("A sheet of an OpenDocument spreadsheet is parsed", func() {
+			response, err := c.ParseOds(context.Background(), &models.ParseRequest{Filename: "spreadsheet1", Ods: testdata.LoadExampleOds(t), Sheet: "Sheet1"})
+			So(err, ShouldBeNil)
+			So(response.JSON.Data, ShouldResemble, [][]string{{"Year", "Count"}, {"2020", "5"}})
+		})
+	})
+}
+
+func TestRetries(t *testing.T) {
+	Convey("A request is retried while the renderer is unavailable", t, func() {
+		server, count := newFlakyServer(t, http.StatusServiceUnavailable, 2)
+		c := client.New(server.URL, client.WithBackoff(time.Millisecond, time.Millisecond))
+
+		body, err := c.Render(context.Background(), "html", renderRequest)
+		So(err, ShouldBeNil)
+		body.Close()
+		So(atomic.LoadInt32(count), ShouldEqual, 3)
+	})
+
+	Convey("The last error is returned once the retries are exhausted", t, func() {
+		server, count := newFlakyServer(t, http.StatusBadGateway, 10)
+		c := client.New(server.URL, client.WithRetries(1), client.WithBackoff(time.Millisecond, time.Millisecond))
+
+		_, err := c.Render(context.Background(), "html", renderRequest)
+		var apiError *client.APIError
+		So(errors.As(err, &apiError), ShouldBeTrue)
+		So(apiError.StatusCode, ShouldEqual, http.StatusBadGateway)
+		So(apiError.Code, ShouldEqual, models.CodeInternal)
+		So(atomic.LoadInt32(count), ShouldEqual, 2)
+	})
+
+	Convey("A client error is not retried", t, func() {
+		server, count := newFlakyServer(t, http.StatusServiceUnavailable, 0)
+		c := client.New(server.URL, client.WithBackoff(time.Millisecond, time.Millisecond))
+
+		_, err := c.Render(context.Background(), "pdf", renderRequest)
+		So(err, ShouldNotBeNil)
+		So(atomic.LoadInt32(count), ShouldEqual, 1)
+	})
+
+	Convey("Retrying stops when the context is cancelled", t, func() {
+		server, _ := newFlakyServer(t, http.StatusServiceUnavailable, 10)
+		c := client.New(server.URL, client.WithBackoff(time.Hour, time.Hour))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := c.Render(ctx, "html", renderRequest)
+		So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+	})
+
+	Convey("The request id in the context is sent to the renderer", t, func() {
+		var requestID string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID = r.Header.Get(request.RequestHeaderKey)
+		}))
+		defer server.Close()
+
+		body, err := client.New(server.URL).Render(request.WithRequestId(context.Background(), "myRequestID"), "html", renderRequest)
+		So(err, ShouldBeNil)
+		body.Close()
+		So(requestID, ShouldEqual, "myRequestID")
+	})
+}
+
+func TestBatch(t *testing.T) {
+	Convey("Each item of a batch is rendered, and failures are reported against the item", t, func() {
+		c := client.New(newRenderer(t).URL, client.WithBatchConcurrency(2))
+
+		results := c.Batch(context.Background(), []client.BatchItem{
+			{Format: "csv", Request: renderRequest},
+			{Format: "pdf", Request: renderRequest},
+			{Format: "html", Request: renderRequest},
+		})
+		So(results, ShouldHaveLength, 3)
+		So(results[0].Err, ShouldBeNil)
+		So(string(results[0].Body), ShouldContainSubstring, "a,b")
+		So(errors.Is(results[1].Err, &models.Error{Code: models.CodeUnknownRenderType}), ShouldBeTrue)
+		So(results[2].Err, ShouldBeNil)
+		So(string(results[2].Body), ShouldContainSubstring, "<table")
+	})
+}
+
+func TestChecker(t *testing.T) {
+	Convey("A healthy renderer is reported as ok", t, func() {
+		state := healthcheck.NewCheckState(client.Name)
+		So(client.New(newRenderer(t).URL).Checker(context.Background(), state), ShouldBeNil)
+		So(state.Status(), ShouldEqual, healthcheck.StatusOK)
+		So(state.StatusCode(), ShouldEqual, http.StatusOK)
+	})
+
+	Convey("A renderer reporting a warning is reported as a warning", t, func() {
+		server, _ := newFlakyServer(t, http.StatusTooManyRequests, 1)
+		state := healthcheck.NewCheckState(client.Name)
+		So(client.New(server.URL).Checker(context.Background(), state), ShouldBeNil)
+		So(state.Status(), ShouldEqual, healthcheck.StatusWarning)
+	})
+
+	Convey("An unreachable renderer is critical", t, func() {
+		server := newRenderer(t)
+		server.Close()
+		state := healthcheck.NewCheckState(client.Name)
+		So(client.New(server.URL).Checker(context.Background(), state), ShouldBeNil)
+		So(state.Status(), ShouldEqual, healthcheck.StatusCritical)
+	})
+}
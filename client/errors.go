@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+)
+
+// maxErrorBody is the most of an error response that is read
+const maxErrorBody = 64 * 1024
+
+// APIError is returned when the renderer responds with an error.
+// errors.Is(err, models.ErrorNoData) etc can be used to test for a particular error code.
+type APIError struct {
+	StatusCode int                    // the http status of the response
+	Code       string                 // one of the models.Code* constants
+	Message    string                 // a human readable description of the error
+	Details    map[string]interface{} // additional information about the error, e.g. the fields that are missing
+	RequestID  string                 // the id of the failed request, if the renderer reported one
+}
+
+// Error returns the status, code and message of the error
+func (e *APIError) Error() string {
+	return fmt.Sprintf("table renderer returned %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Is returns true if the target is an APIError or models.Error with the same code
+func (e *APIError) Is(target error) bool {
+	switch t := target.(type) {
+	case *APIError:
+		return t.Code == e.Code
+	case *models.Error:
+		return t.Code == e.Code
+	}
+	return false
+}
+
+// newAPIError reads the error response of the renderer, closing the body
+func newAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+
+	var body struct {
+		Code      string                 `json:"code"`
+		Message   string                 `json:"message"`
+		Details   map[string]interface{} `json:"details"`
+		RequestID string                 `json:"request_id"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBody)).Decode(&body); err != nil || len(body.Code) == 0 {
+		// not an error from the renderer, e.g. from a proxy in front of it
+		body.Code = models.CodeInternal
+		body.Message = http.StatusText(resp.StatusCode)
+	}
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Code:       body.Code,
+		Message:    body.Message,
+		Details:    body.Details,
+		RequestID:  body.RequestID,
+	}
+}
+
+// retryable returns true if a request that failed with err might succeed if it is made again
+func retryable(err error) bool {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		switch apiError.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// the request wasn't made, or no response was received
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
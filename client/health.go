@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+)
+
+// Name is the name of the renderer used in health checks
+const Name = "table-renderer"
+
+// Checker checks the health of the renderer, for use with dp-healthcheck:
+//
+//	hc.AddCheck(client.Name, rendererClient.Checker)
+func (c *Client) Checker(ctx context.Context, state *healthcheck.CheckState) error {
+	resp, err := c.do(ctx, http.MethodGet, "/health", nil)
+	if err != nil {
+		return state.Update(healthcheck.StatusCritical, fmt.Sprintf("%s is unreachable: %v", Name, err), 0)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return state.Update(healthcheck.StatusOK, Name+" is ok", resp.StatusCode)
+	case http.StatusTooManyRequests:
+		return state.Update(healthcheck.StatusWarning, Name+" is degraded, but at least partially functioning", resp.StatusCode)
+	default:
+		return state.Update(healthcheck.StatusCritical, Name+" functionality is unavailable or non-functioning", resp.StatusCode)
+	}
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+)
+
+// render renders each input in a single format, to stdout, a named file or a file per input
+func (c *cli) render(args []string) int {
+	flags, verbose := c.newFlagSet("render", "[file|glob|-]...")
+	formatName := flags.String("format", "html", "the output format: "+strings.Join(renderer.FormatNames(), ", "))
+	output := flags.String("o", "", "the file to write (only if there is a single input)")
+	outDir := flags.String("out-dir", "", "the directory to write to, creating a file per input named after the input")
+	theme := flags.String("theme", "", "the theme used to render html, overriding the theme of each input: "+strings.Join(renderer.ThemeNames(), ", "))
+	args, ok := c.parseFlags(flags, verbose, args)
+	if !ok {
+		return exitUsage
+	}
+
+	format, ok := renderer.FindFormat(*formatName)
+	if !ok {
+		return c.usageError(flags, "unknown format %q", *formatName)
+	}
+	inputs, err := expandInputs(args)
+	if err != nil {
+		return c.usageError(flags, "%v", err)
+	}
+	if len(*output) > 0 && (len(inputs) > 1 || len(*outDir) > 0) {
+		return c.usageError(flags, "-o can only be used with a single input, and not with -out-dir")
+	}
+	if err = makeOutDir(*outDir); err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return exitFailed
+	}
+
+	status := exitOK
+	for _, input := range inputs {
+		request, err := c.readRenderRequest(input)
+		if err != nil {
+			c.reportError(input, err)
+			status = exitFailed
+			continue
+		}
+		if len(*theme) > 0 {
+			request.Theme = *theme
+		}
+
+		write := func(w io.Writer) error { return format.Write(c.ctx, w, request) }
+		switch {
+		case len(*output) > 0:
+			err = writeFile(*output, write)
+		case input == stdinName && len(*outDir) == 0:
+			err = write(c.stdout)
+		default:
+			err = writeFile(outputPath(input, request, *outDir, format.Extension), write)
+		}
+		if err != nil {
+			c.reportError(input, err)
+			status = exitFailed
+		}
+	}
+	return status
+}
+
+// validate checks that each input would be accepted by the service, reporting any that would not
+func (c *cli) validate(args []string) int {
+	flags, verbose := c.newFlagSet("validate", "[file|glob|-]...")
+	args, ok := c.parseFlags(flags, verbose, args)
+	if !ok {
+		return exitUsage
+	}
+	inputs, err := expandInputs(args)
+	if err != nil {
+		return c.usageError(flags, "%v", err)
+	}
+
+	status := exitOK
+	for _, input := range inputs {
+		request, err := c.readRenderRequest(input)
+		if err != nil {
+			c.reportError(input, err)
+			status = exitFailed
+			continue
+		}
+		c.reportWarnings(input, request.CheckFootnotes())
+		fmt.Fprintf(c.stdout, "%s: ok\n", input)
+	}
+	return status
+}
+
+// footnotes reports unused footnotes and dangling references in a table definition, optionally renumbering its
+// footnotes in the order in which they are referenced
+func (c *cli) footnotes(args []string) int {
+	flags, verbose := c.newFlagSet("footnotes", "[file|-]")
+	renumber := flags.Bool("renumber", false, "renumber the footnotes in the order in which they are referenced, deleting empty footnotes, and write the table definition")
+	output := flags.String("o", "", "the file to write the renumbered table definition to, instead of stdout")
+	args, ok := c.parseFlags(flags, verbose, args)
+	if !ok {
+		return exitUsage
+	}
+	if len(args) > 1 {
+		return c.usageError(flags, "footnotes accepts a single input")
+	}
+	input := stdinName
+	if len(args) == 1 {
+		input = args[0]
+	}
+
+	request, err := c.readRenderRequest(input)
+	if err != nil {
+		c.reportError(input, err)
+		return exitFailed
+	}
+	if !*renumber {
+		c.reportWarnings(input, request.CheckFootnotes())
+		return exitOK
+	}
+	c.reportWarnings(input, request.RenumberFootnotes())
+	if len(*output) > 0 {
+		err = writeFile(*output, func(w io.Writer) error { return writeJSON(w, request) })
+	} else {
+		err = writeJSON(c.stdout, request)
+	}
+	if err != nil {
+		c.reportError(input, err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+// batch renders every json file in the given directories (or matching the given patterns) in one or more formats
+func (c *cli) batch(args []string) int {
+	flags, verbose := c.newFlagSet("batch", "dir|file|glob...")
+	formatNames := flags.String("formats", strings.Join(renderer.FormatNames(), ","), "a comma separated list of the formats to render")
+	outDir := flags.String("out-dir", "", "the directory to write to, instead of alongside each input")
+	theme := flags.String("theme", "", "the theme used to render html, overriding the theme of each input: "+strings.Join(renderer.ThemeNames(), ", "))
+	args, ok := c.parseFlags(flags, verbose, args)
+	if !ok {
+		return exitUsage
+	}
+
+	var formats []renderer.Format
+	for _, name := range strings.Split(*formatNames, ",") {
+		format, ok := renderer.FindFormat(strings.TrimSpace(name))
+		if !ok {
+			return c.usageError(flags, "unknown format %q", name)
+		}
+		formats = append(formats, format)
+	}
+	if len(args) == 0 {
+		return c.usageError(flags, "no input directories")
+	}
+	var patterns []string
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			arg = filepath.Join(arg, "*.json")
+		}
+		patterns = append(patterns, arg)
+	}
+	inputs, err := expandInputs(patterns)
+	if err != nil {
+		return c.usageError(flags, "%v", err)
+	}
+	if err = makeOutDir(*outDir); err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return exitFailed
+	}
+
+	failed := 0
+	for _, input := range inputs {
+		request, err := c.readRenderRequest(input)
+		if err == nil && len(*theme) > 0 {
+			request.Theme = *theme
+		}
+		if err == nil {
+			for _, format := range formats {
+				path := outputPath(input, request, *outDir, format.Extension)
+				if err = writeFile(path, func(w io.Writer) error { return format.Write(c.ctx, w, request) }); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			c.reportError(input, err)
+			failed++
+		}
+	}
+	fmt.Fprintf(c.stdout, "rendered %d of %d tables\n", len(inputs)-failed, len(inputs))
+	if failed > 0 {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// parse converts an html table, the state of a Handsontable editor, a markdown or fixed-width text table, or a table of a
+// Word document, into the json used to render it
+func (c *cli) parse(args []string) int {
+	flags, verbose := c.newFlagSet("parse", "[file|-]")
+	request := models.ParseRequest{Limits: c.limits}
+	flags.StringVar(&request.Filename, "filename", "", "the filename (id) of the table - defaults to the name of the input file")
+	flags.StringVar(&request.Title, "title", "", "the title of the table")
+	flags.StringVar(&request.Subtitle, "subtitle", "", "the subtitle of the table")
+	flags.StringVar(&request.Source, "source", "", "the source of the data in the table")
+	flags.StringVar(&request.Units, "units", "", "the units used in the table")
+	flags.IntVar(&request.HeaderRows, "header-rows", 0, "the number of rows that are headings")
+	flags.IntVar(&request.HeaderCols, "header-cols", 0, "the number of columns that are headings")
+	flags.BoolVar(&request.IgnoreFirstRow, "ignore-first-row", false, "ignore the first row of the html table")
+	flags.BoolVar(&request.IgnoreFirstColumn, "ignore-first-column", false, "ignore the first column of the html table")
+	flags.StringVar(&request.CellLayout, "cell-layout", models.CellLayoutAuto, "whether each row of the html includes the cells hidden by merged cells: handsontable, standard or auto")
+	flags.BoolVar(&request.InferStructure, "infer", false, "infer headings, totals and the title from the html where they are not given")
+	flags.BoolVar(&request.KeepHeadersTogether, "keep-headers-together", false, "prevent the content of heading cells wrapping")
+	flags.Func("footnote", "a footnote (may be repeated)", func(note string) error {
+		request.Footnotes = append(request.Footnotes, note)
+		return nil
+	})
+	output := flags.String("o", "", "the file to write, instead of stdout")
+	flags.StringVar(&request.TableSelector, "table", "", "the table to parse from a page: its index, or a css selector - defaults to the first table")
+	preview := flags.Bool("preview", false, "write the full response, including the preview html, rather than just the json")
+	list := flags.Bool("list", false, "list the tables in the html or Word document, rather than parsing one")
+	handsontable := flags.Bool("handsontable", false, "the input is the json state of a Handsontable editor (data, mergeCells, cell, colWidths and rowHeights), rather than html")
+	markdown := flags.Bool("markdown", false, "the input is markdown containing a pipe table, rather than html")
+	text := flags.Bool("text", false, "the input is a table of fixed-width text, rather than html")
+	docx := flags.Bool("docx", false, "the input is a Word document (.docx), rather than html - -table chooses a table by its index")
+	ods := flags.Bool("ods", false, "the input is an OpenDocument spreadsheet (.ods), rather than html - -sheet and -range choose the cells")
+	flags.StringVar(&request.Sheet, "sheet", "", "the sheet of -ods to parse: its name, or its index - defaults to the first sheet")
+	flags.StringVar(&request.Range, "range", "", "the range of cells of -ods to parse, e.g. A1:D10 - defaults to the cells that aren't empty")
+	flags.Func("column-boundaries", "the comma separated character positions at which the columns of -text after the first begin - detected from the text by default", func(value string) error {
+		for _, boundary := range strings.Split(value, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(boundary))
+			if err != nil {
+				return err
+			}
+			request.ColumnBoundaries = append(request.ColumnBoundaries, n)
+		}
+		return nil
+	})
+	args, ok := c.parseFlags(flags, verbose, args)
+	if !ok {
+		return exitUsage
+	}
+	if len(args) > 1 {
+		return c.usageError(flags, "parse accepts a single input")
+	}
+	if countTrue(*handsontable, *markdown, *text, *docx, *ods) > 1 {
+		return c.usageError(flags, "only one of -handsontable, -markdown, -text, -docx and -ods can be used")
+	}
+	if *list && countTrue(*handsontable, *markdown, *text, *ods) > 0 {
+		return c.usageError(flags, "-list can only be used with html or -docx")
+	}
+	input := stdinName
+	if len(args) == 1 {
+		input = args[0]
+	}
+
+	reader, err := c.openInput(input)
+	if err != nil {
+		c.reportError(input, err)
+		return exitFailed
+	}
+	content, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		c.reportError(input, err)
+		return exitFailed
+	}
+	if *handsontable {
+		if err = json.Unmarshal(content, &request.Handsontable); err != nil {
+			c.reportError(input, models.NewError(models.CodeInvalidJSON, models.ErrorParsingBody.Message, err))
+			return exitFailed
+		}
+	} else if *markdown {
+		request.TableMarkdown = string(content)
+	} else if *text {
+		request.TableText = string(content)
+	} else if *docx {
+		request.Docx = content
+	} else if *ods {
+		request.Ods = content
+	} else {
+		request.TableHTML = strings.TrimSpace(string(content))
+	}
+	if len(request.Filename) == 0 && input != stdinName {
+		request.Filename = strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	}
+
+	validate, parse := request.ValidateParseRequest, parser.ParseHTML
+	if *list && *docx {
+		validate, parse = request.ValidateDocxRequest, parser.ListDocxTables
+	} else if *list {
+		parse = parser.ListTables
+	} else if *docx {
+		validate, parse = request.ValidateDocxRequest, parser.ParseDocx
+	} else if *ods {
+		validate, parse = request.ValidateOdsRequest, parser.ParseOds
+	} else if *handsontable {
+		validate, parse = request.ValidateHandsontableRequest, parser.ParseHandsontable
+	} else if *markdown {
+		validate, parse = request.ValidateMarkdownRequest, parser.ParseMarkdown
+	} else if *text {
+		validate, parse = request.ValidateTextRequest, parser.ParseText
+	}
+	if err = validate(c.ctx); err != nil {
+		c.reportError(input, err)
+		return exitFailed
+	}
+	body, err := parse(c.ctx, &request)
+	if err != nil {
+		c.reportError(input, err)
+		return exitFailed
+	}
+	var response parser.ResponseModel
+	var tables parser.TablesResponse
+	if *list {
+		err = json.Unmarshal(body, &tables)
+	} else {
+		err = json.Unmarshal(body, &response)
+	}
+	if err != nil {
+		c.reportError(input, err)
+		return exitFailed
+	}
+
+	var result interface{} = response.JSON
+	if *list {
+		result = tables
+	} else if *preview {
+		result = response
+	}
+	if len(*output) > 0 {
+		err = writeFile(*output, func(w io.Writer) error { return writeJSON(w, result) })
+	} else {
+		err = writeJSON(c.stdout, result)
+	}
+	if err != nil {
+		c.reportError(input, err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+// countTrue returns the number of values that are true
+func countTrue(values ...bool) int {
+	count := 0
+	for _, value := range values {
+		if value {
+			count++
+		}
+	}
+	return count
+}
+
+// writeJSON writes the value as indented json, without escaping html
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+)
+
+// stdinName identifies stdin in the list of inputs
+const stdinName = "-"
+
+// expandInputs expands any glob patterns in the arguments, returning the list of files to process.
+// If there are no arguments the input is read from stdin.
+func expandInputs(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{stdinName}, nil
+	}
+	var inputs []string
+	for _, arg := range args {
+		if arg == stdinName || !strings.ContainsAny(arg, "*?[") {
+			inputs = append(inputs, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", arg)
+		}
+		inputs = append(inputs, matches...)
+	}
+	return inputs, nil
+}
+
+// openInput opens the named input, which may be stdin
+func (c *cli) openInput(name string) (io.ReadCloser, error) {
+	if name == stdinName {
+		return io.NopCloser(c.stdin), nil
+	}
+	return os.Open(name)
+}
+
+// readRenderRequest reads and validates a RenderRequest from the named input, applying the same limits as the service
+func (c *cli) readRenderRequest(name string) (*models.RenderRequest, error) {
+	reader, err := c.openInput(name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	request, err := models.CreateRenderRequestWithLimits(c.ctx, reader, c.limits)
+	if err != nil {
+		return nil, err
+	}
+	if err = request.ValidateRenderRequest(); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// outputPath returns the path of the file to write for an input: the input's name with the given extension,
+// in outDir if one is specified, otherwise alongside the input
+func outputPath(input string, request *models.RenderRequest, outDir string, extension string) string {
+	base := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	dir := filepath.Dir(input)
+	if input == stdinName {
+		base = request.Filename
+		if len(base) == 0 {
+			base = "table"
+		}
+		dir = "."
+	}
+	if len(outDir) > 0 {
+		dir = outDir
+	}
+	return filepath.Join(dir, base+extension)
+}
+
+// makeOutDir creates the output directory, if one has been specified
+func makeOutDir(outDir string) error {
+	if len(outDir) == 0 {
+		return nil
+	}
+	return os.MkdirAll(outDir, 0755)
+}
+
+// writeFile creates the file at path using write, removing the file again if write fails
+func writeFile(path string, write func(io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+// Command table-renderer renders, parses and validates tables without running the http service.
+//
+//	table-renderer render   [-format html] [-theme name] [-o file | -out-dir dir] [file|glob|-]...
+//	table-renderer parse    [options] [-o file] [file|-]
+//	table-renderer validate [file|glob|-]...
+//	table-renderer batch    [-formats html,csv,xlsx] [-theme name] [-out-dir dir] dir|file|glob...
+//
+// Input is read from stdin if no files are given, or a file is '-'.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ONSdigital/dp-table-renderer/config"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// exit codes
+const (
+	exitOK     = 0
+	exitFailed = 1 // one or more tables could not be rendered, parsed or validated
+	exitUsage  = 2 // the command line was invalid
+)
+
+const usage = `Usage: table-renderer <command> [options] [files]
+
+Commands:
+  render    render json table definitions as html, csv, xlsx etc
+  parse     parse an html, markdown, fixed-width text or Word (.docx) table, a range of an OpenDocument spreadsheet (.ods), or a Handsontable state, into the json used to render it
+  validate  check that json table definitions would be accepted by the service
+  batch     render every json table definition in one or more directories
+  footnotes report unused footnotes and dangling references, and renumber footnotes
+
+Run 'table-renderer <command> -h' for the options of each command.
+`
+
+// cli holds the streams and settings shared by all commands
+type cli struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	limits models.Limits
+}
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command in args, returning the exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	// the renderer logs json events, which would otherwise be mixed with the output - see the -v flag
+	log.SetDestination(io.Discard, nil)
+
+	cfg, err := config.Get()
+	if err != nil {
+		fmt.Fprintln(stderr, "invalid configuration:", err)
+		return exitUsage
+	}
+	if len(cfg.ThemesDir) > 0 {
+		if err = renderer.LoadThemes(cfg.ThemesDir); err != nil {
+			fmt.Fprintln(stderr, "invalid themes:", err)
+			return exitUsage
+		}
+	}
+	c := &cli{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr, limits: cfg.RequestLimits()}
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+	commands := map[string]func([]string) int{
+		"render":    c.render,
+		"parse":     c.parse,
+		"validate":  c.validate,
+		"batch":     c.batch,
+		"footnotes": c.footnotes,
+	}
+	command, ok := commands[args[0]]
+	if !ok {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			fmt.Fprint(stdout, usage)
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return exitUsage
+	}
+	return command(args[1:])
+}
+
+// newFlagSet creates the flags for a command, including the -v flag common to all commands
+func (c *cli) newFlagSet(name string, arguments string) (*flag.FlagSet, *bool) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: table-renderer %s [options] %s\n\nOptions:\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	verbose := flags.Bool("v", false, "write the service log events to stderr")
+	return flags, verbose
+}
+
+// parseFlags parses the arguments of a command, returning the arguments that aren't flags, or false if the flags are invalid.
+// Unlike flag.Parse, flags may follow the other arguments.
+func (c *cli) parseFlags(flags *flag.FlagSet, verbose *bool, args []string) ([]string, bool) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, false
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if *verbose {
+		log.SetDestination(c.stderr, nil)
+	}
+	return positional, true
+}
+
+// usageError reports a problem with the command line
+func (c *cli) usageError(flags *flag.FlagSet, format string, args ...interface{}) int {
+	fmt.Fprintf(c.stderr, format+"\n\n", args...)
+	flags.Usage()
+	return exitUsage
+}
+
+// reportError writes a readable description of an error that occurred while processing the named input
+func (c *cli) reportError(name string, err error) {
+	fmt.Fprintf(c.stderr, "%s: %s\n", name, describeError(err))
+}
+
+// reportWarnings writes each warning about the named input
+func (c *cli) reportWarnings(name string, warnings []models.Warning) {
+	for _, warning := range warnings {
+		fmt.Fprintf(c.stderr, "%s: warning: %s: %s: %s\n", name, warning.Code, warning.Location, warning.Message)
+	}
+}
+
+// describeError returns the code and message of an error, including any details, e.g.
+// 'table_too_large: Request exceeds the limit max_rows (10) [limit=max_rows max=10]'
+func describeError(err error) string {
+	var e *models.Error
+	if !errors.As(err, &e) {
+		return err.Error()
+	}
+	description := e.Code + ": " + e.Error()
+	if len(e.Details) > 0 {
+		keys := make([]string, 0, len(e.Details))
+		for key := range e.Details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		details := make([]string, len(keys))
+		for i, key := range keys {
+			details[i] = fmt.Sprintf("%s=%v", key, e.Details[key])
+		}
+		description += " [" + strings.Join(details, " ") + "]"
+	}
+	return description
+}
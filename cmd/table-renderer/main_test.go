@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	"github.com/ONSdigital/dp-table-renderer/testdata"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const validRequest = `{"filename": "table1", "title": "A table", "data": [["a", "b"], ["1", "2"]]}`
+
+// runCommand runs the cli with the given arguments and stdin, returning the exit code, stdout and stderr
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// writeInputs creates files with the given names and content in a new directory
+func writeInputs(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCommands(t *testing.T) {
+	Convey("With no command, the usage is reported", t, func() {
+		code, _, stderr := runCommand("")
+		So(code, ShouldEqual, exitUsage)
+		So(stderr, ShouldContainSubstring, "Usage: table-renderer <command>")
+	})
+
+	Convey("An unknown command is reported", t, func() {
+		code, _, stderr := runCommand("", "draw")
+		So(code, ShouldEqual, exitUsage)
+		So(stderr, ShouldContainSubstring, `unknown command "draw"`)
+	})
+}
+
+func TestRender(t *testing.T) {
+	Convey("A table read from stdin is rendered to stdout", t, func() {
+		code, stdout, stderr := runCommand(validRequest, "render", "-format", "csv")
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		So(stdout, ShouldStartWith, "A table\n")
+		So(stdout, ShouldContainSubstring, "a,b\n1,2\n")
+	})
+
+	Convey("Files matching a pattern are rendered to the output directory, with flags following the files", t, func() {
+		dir := writeInputs(t, map[string]string{"one.json": validRequest, "two.json": validRequest, "notes.txt": "ignored"})
+		outDir := filepath.Join(t.TempDir(), "out")
+
+		code, _, stderr := runCommand("", "render", filepath.Join(dir, "*.json"), "-format", "html", "-out-dir", outDir)
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		for _, name := range []string{"one.html", "two.html"} {
+			content, err := os.ReadFile(filepath.Join(outDir, name))
+			So(err, ShouldBeNil)
+			So(string(content), ShouldContainSubstring, `<figure class="figure" id="table-table1">`)
+		}
+	})
+
+	Convey("The theme flag overrides the theme of the table", t, func() {
+		code, stdout, stderr := runCommand(validRequest, "render", "-theme", "govuk")
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		So(stdout, ShouldContainSubstring, `<table class="govuk-table">`)
+
+		code, _, stderr = runCommand(validRequest, "render", "-theme", "unknown")
+		So(code, ShouldEqual, exitFailed)
+		So(stderr, ShouldContainSubstring, "unknown_theme")
+	})
+
+	Convey("An invalid table is reported, and the exit code is non-zero", t, func() {
+		dir := writeInputs(t, map[string]string{"good.json": validRequest, "bad.json": "{"})
+
+		code, _, stderr := runCommand("", "render", "-format", "xlsx", filepath.Join(dir, "bad.json"), filepath.Join(dir, "good.json"))
+		So(code, ShouldEqual, exitFailed)
+		So(stderr, ShouldContainSubstring, "bad.json: invalid_json: Failed to parse json body")
+		_, err := os.Stat(filepath.Join(dir, "good.xlsx"))
+		So(err, ShouldBeNil)
+		_, err = os.Stat(filepath.Join(dir, "bad.xlsx"))
+		So(os.IsNotExist(err), ShouldBeTrue)
+	})
+
+	Convey("Invalid arguments are reported as usage errors", t, func() {
+		code, _, stderr := runCommand("", "render", "-format", "pdf")
+		So(code, ShouldEqual, exitUsage)
+		So(stderr, ShouldContainSubstring, `unknown format "pdf"`)
+
+		code, _, stderr = runCommand("", "render", filepath.Join(t.TempDir(), "*.json"))
+		So(code, ShouldEqual, exitUsage)
+		So(stderr, ShouldContainSubstring, "no files match")
+
+		code, _, _ = runCommand("", "render", "-o", "out.html", "a.json", "b.json")
+		So(code, ShouldEqual, exitUsage)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	Convey("Each input is reported as valid or invalid, with the reason it is invalid", t, func() {
+		dir := writeInputs(t, map[string]string{"good.json": validRequest, "empty.json": "{}"})
+
+		code, stdout, stderr := runCommand("", "validate", filepath.Join(dir, "*.json"))
+		So(code, ShouldEqual, exitFailed)
+		So(stdout, ShouldContainSubstring, "good.json: ok")
+		So(stderr, ShouldContainSubstring, "empty.json: missing_data:")
+	})
+
+	Convey("The details of an error are included", t, func() {
+		So(describeError(models.NewMissingFieldsError([]string{"table_html"})), ShouldEqual,
+			"missing_fields: Missing mandatory fields: [table_html] [missing_fields=[table_html]]")
+	})
+}
+
+func TestBatch(t *testing.T) {
+	Convey("Every json file in a directory is rendered in each format", t, func() {
+		dir := writeInputs(t, map[string]string{"one.json": validRequest, "two.json": validRequest})
+		outDir := t.TempDir()
+
+		code, stdout, _ := runCommand("", "batch", "-formats", "csv,html", "-out-dir", outDir, dir)
+		So(code, ShouldEqual, exitOK)
+		So(stdout, ShouldEqual, "rendered 2 of 2 tables\n")
+		files, err := filepath.Glob(filepath.Join(outDir, "*"))
+		So(err, ShouldBeNil)
+		So(len(files), ShouldEqual, 4)
+	})
+
+	Convey("Failures are counted and reported", t, func() {
+		dir := writeInputs(t, map[string]string{"one.json": validRequest, "two.json": "[]"})
+
+		code, stdout, stderr := runCommand("", "batch", "-formats", "csv", dir)
+		So(code, ShouldEqual, exitFailed)
+		So(stdout, ShouldEqual, "rendered 1 of 2 tables\n")
+		So(stderr, ShouldContainSubstring, "two.json: invalid_json")
+	})
+}
+
+func TestFootnotes(t *testing.T) {
+	const request = `{"filename": "t", "title": "A table[2]", "data": [["a[3]", "b"]], "footnotes": ["unused", "first", "second"]}`
+
+	Convey("Unused footnotes and dangling references are reported", t, func() {
+		code, stdout, stderr := runCommand(`{"data": [["a[2]"]], "footnotes": ["unused"]}`, "footnotes")
+		So(code, ShouldEqual, exitOK)
+		So(stdout, ShouldBeEmpty)
+		So(stderr, ShouldEqual, "-: warning: dangling_reference: data[0][0]: [2] refers to footnote 2, which doesn't exist\n"+
+			"-: warning: unused_footnote: footnotes[0]: footnote 1 isn't referenced from the table\n")
+	})
+
+	Convey("The footnotes are renumbered in the order in which they are referenced", t, func() {
+		code, stdout, stderr := runCommand(request, "footnotes", "-renumber")
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldEqual, "-: warning: unused_footnote: footnotes[2]: footnote 3 isn't referenced from the table\n")
+
+		var renumbered models.RenderRequest
+		So(json.Unmarshal([]byte(stdout), &renumbered), ShouldBeNil)
+		So(renumbered.Title, ShouldEqual, "A table[1]")
+		So(renumbered.Data, ShouldResemble, [][]string{{"a[2]", "b"}})
+		So(renumbered.Footnotes, ShouldResemble, []string{"first", "second", "unused"})
+	})
+
+	Convey("The validate command reports the same warnings", t, func() {
+		_, stdout, stderr := runCommand(request, "validate")
+		So(stdout, ShouldEqual, "-: ok\n")
+		So(stderr, ShouldContainSubstring, "-: warning: unused_footnote: footnotes[0]")
+	})
+}
+
+func TestParse(t *testing.T) {
+	Convey("An html table is parsed into the json used to render it", t, func() {
+		dir := writeInputs(t, map[string]string{"population.html": "<table><tr><th>Year</th><th>Count</th></tr><tr><td>2020</td><td>5</td></tr></table>\n"})
+
+		code, stdout, stderr := runCommand("", "parse", filepath.Join(dir, "population.html"), "-header-rows", "1", "-title", "Population", "-footnote", "a note")
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+
+		var request models.RenderRequest
+		So(json.Unmarshal([]byte(stdout), &request), ShouldBeNil)
+		So(request.Filename, ShouldEqual, "population")
+		So(request.Title, ShouldEqual, "Population")
+		So(request.Data, ShouldResemble, [][]string{{"Year", "Count"}, {"2020", "5"}})
+		So(request.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}})
+		So(request.Footnotes, ShouldResemble, []string{"a note"})
+	})
+
+	Convey("The tables in a page are listed, and one is chosen", t, func() {
+		page := "<h2>First</h2><table><tr><td>a</td></tr></table><table id=\"second\"><tr><td>b</td><td>c</td></tr></table>"
+
+		code, stdout, stderr := runCommand(page, "parse", "-list")
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		var tables parser.TablesResponse
+		So(json.Unmarshal([]byte(stdout), &tables), ShouldBeNil)
+		So(tables.Tables, ShouldHaveLength, 2)
+		So(tables.Tables[1].ID, ShouldEqual, "second")
+
+		code, stdout, _ = runCommand(page, "parse", "-table", "#second")
+		So(code, ShouldEqual, exitOK)
+		var request models.RenderRequest
+		So(json.Unmarshal([]byte(stdout), &request), ShouldBeNil)
+		So(request.Data, ShouldResemble, [][]string{{"b", "c"}})
+	})
+
+	Convey("The state of a Handsontable editor is parsed", t, func() {
+		state := `{"data":[["Year","Count"],[2020,5]],"mergeCells":true,"cell":[{"row":1,"col":1,"className":"htRight"}]}`
+
+		code, stdout, stderr := runCommand(state, "parse", "-handsontable", "-header-rows", "1")
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		var request models.RenderRequest
+		So(json.Unmarshal([]byte(stdout), &request), ShouldBeNil)
+		So(request.Data, ShouldResemble, [][]string{{"Year", "Count"}, {"2020", "5"}})
+		So(request.CellFormats, ShouldResemble, []models.CellFormat{{Row: 1, Column: 1, Align: models.AlignRight}})
+
+		code, _, stderr = runCommand("<table></table>", "parse", "-handsontable")
+		So(code, ShouldEqual, exitFailed)
+		So(stderr, ShouldContainSubstring, "-: invalid_json")
+	})
+
+	Convey("Markdown and fixed-width text tables are parsed", t, func() {
+		code, stdout, stderr := runCommand("| Year | Count |\n|---|---|\n| 2020 | 5 |\n", "parse", "-markdown")
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		var request models.RenderRequest
+		So(json.Unmarshal([]byte(stdout), &request), ShouldBeNil)
+		So(request.Data, ShouldResemble, [][]string{{"Year", "Count"}, {"2020", "5"}})
+		So(request.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}})
+
+		code, stdout, stderr = runCommand("Year Count\n2020 5\n", "parse", "-text", "-column-boundaries", "5")
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		So(json.Unmarshal([]byte(stdout), &request), ShouldBeNil)
+		So(request.Data, ShouldResemble, [][]string{{"Year", "Count"}, {"2020", "5"}})
+
+		code, _, stderr = runCommand("", "parse", "-text", "-markdown")
+		So(code, ShouldEqual, exitUsage)
+		So(stderr, ShouldContainSubstring, "only one of")
+	})
+
+	Convey("The tables of a Word document are listed and parsed", t, func() {
+		dir := writeInputs(t, map[string]string{"document.docx": string(testdata.LoadExampleDocx(t))})
+		input := filepath.Join(dir, "document.docx")
+
+		code, stdout, stderr := runCommand("", "parse", "-docx", "-list", input)
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		var tables parser.TablesResponse
+		So(json.Unmarshal([]byte(stdout), &tables), ShouldBeNil)
+		So(tables.Tables, ShouldResemble, []parser.TableSummary{{Index: 0, Rows: 2, Columns: 2}})
+
+		code, stdout, stderr = runCommand("", "parse", "-docx", "-table", "0", input)
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		var request models.RenderRequest
+		So(json.Unmarshal([]byte(stdout), &request), ShouldBeNil)
+		So(request.Filename, ShouldEqual, "document")
+		So(request.Data, ShouldResemble, [][]string{{"Year", "Count"}, {"2020", "5"}})
+
+		code, _, stderr = runCommand("", "parse", "-markdown", "-list")
+		So(code, ShouldEqual, exitUsage)
+		So(stderr, ShouldContainSubstring, "-list can only be used")
+	})
+
+	Convey("A range of an OpenDocument spreadsheet is parsed", t, func() {
+		dir := writeInputs(t, map[string]string{"spreadsheet.ods": string(testdata.LoadExampleOds(t))})
+		input := filepath.Join(dir, "spreadsheet.ods")
+
+		code, stdout, stderr := runCommand("", "parse", "-ods", "-sheet", "0", "-range", "A1:A1", input)
+		So(code, ShouldEqual, exitOK)
+		So(stderr, ShouldBeEmpty)
+		var request models.RenderRequest
+		So(json.Unmarshal([]byte(stdout), &request), ShouldBeNil)
+		So(request.Filename, ShouldEqual, "spreadsheet")
+		So(request.Data, ShouldResemble, [][]string{{"Year"}})
+
+		code, _, stderr = runCommand("", "parse", "-ods", "-list", input)
+		So(code, ShouldEqual, exitUsage)
+		So(stderr, ShouldContainSubstring, "-list can only be used")
+	})
+
+	Convey("Html that doesn't contain a table is rejected", t, func() {
+		code, _, stderr := runCommand("<p>not a table</p>", "parse")
+		So(code, ShouldEqual, exitFailed)
+		So(stderr, ShouldContainSubstring, "-: invalid_table_html")
+	})
+}
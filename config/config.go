@@ -3,6 +3,7 @@ package config
 import (
 	"time"
 
+	"github.com/ONSdigital/dp-table-renderer/models"
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -17,6 +18,19 @@ type Config struct {
 	OTServiceName              string        `envconfig:"OTEL_SERVICE_NAME"`
 	OTBatchTimeout             time.Duration `envconfig:"OTEL_BATCH_TIMEOUT"`
 	OtelEnabled                bool          `envconfig:"OTEL_ENABLED"`
+	MaxBodyBytes               int64         `envconfig:"MAX_BODY_BYTES"`
+	MaxRows                    int           `envconfig:"MAX_ROWS"`
+	MaxColumns                 int           `envconfig:"MAX_COLUMNS"`
+	MaxCells                   int           `envconfig:"MAX_CELLS"`
+	MaxCellLength              int           `envconfig:"MAX_CELL_LENGTH"`
+	MaxMerges                  int           `envconfig:"MAX_MERGES"`
+	MaxFootnotes               int           `envconfig:"MAX_FOOTNOTES"`
+	RenderCacheEnabled         bool          `envconfig:"RENDER_CACHE_ENABLED"`
+	RenderCacheMaxBytes        int64         `envconfig:"RENDER_CACHE_MAX_BYTES"`
+	MetricsEnabled             bool          `envconfig:"METRICS_ENABLED"`
+	GRPCEnabled                bool          `envconfig:"GRPC_ENABLED"`
+	GRPCBindAddr               string        `envconfig:"GRPC_BIND_ADDR"`
+	ThemesDir                  string        `envconfig:"THEMES_DIR"`
 }
 
 var cfg *Config
@@ -37,7 +51,33 @@ func Get() (*Config, error) {
 		OTServiceName:              "dp-table-renderer",
 		OTBatchTimeout:             5 * time.Second,
 		OtelEnabled:                false,
+		MaxBodyBytes:               50 * 1024 * 1024,
+		MaxRows:                    250000,
+		MaxColumns:                 500,
+		MaxCells:                   5000000,
+		MaxCellLength:              10000,
+		MaxMerges:                  10000,
+		MaxFootnotes:               1000,
+		RenderCacheEnabled:         false,
+		RenderCacheMaxBytes:        64 * 1024 * 1024,
+		MetricsEnabled:             true,
+		GRPCEnabled:                false,
+		GRPCBindAddr:               ":23301",
+		ThemesDir:                  "",
 	}
 
 	return cfg, envconfig.Process("", cfg)
 }
+
+// RequestLimits returns the Limits applied to requests
+func (cfg *Config) RequestLimits() models.Limits {
+	return models.Limits{
+		BodyBytes:  cfg.MaxBodyBytes,
+		Rows:       cfg.MaxRows,
+		Columns:    cfg.MaxColumns,
+		Cells:      cfg.MaxCells,
+		CellLength: cfg.MaxCellLength,
+		Merges:     cfg.MaxMerges,
+		Footnotes:  cfg.MaxFootnotes,
+	}
+}
@@ -22,6 +22,19 @@ func TestSpec(t *testing.T) {
 				So(cfg.ShutdownTimeout, ShouldEqual, 5*time.Second)
 				So(cfg.HealthCheckInterval, ShouldEqual, 30*time.Second)
 				So(cfg.HealthCheckCriticalTimeout, ShouldEqual, 90*time.Second)
+				So(cfg.MaxBodyBytes, ShouldEqual, 50*1024*1024)
+				So(cfg.MaxRows, ShouldEqual, 250000)
+				So(cfg.MaxColumns, ShouldEqual, 500)
+				So(cfg.MaxCells, ShouldEqual, 5000000)
+				So(cfg.MaxCellLength, ShouldEqual, 10000)
+				So(cfg.MaxMerges, ShouldEqual, 10000)
+				So(cfg.MaxFootnotes, ShouldEqual, 1000)
+				So(cfg.RenderCacheEnabled, ShouldBeFalse)
+				So(cfg.RenderCacheMaxBytes, ShouldEqual, 64*1024*1024)
+				So(cfg.MetricsEnabled, ShouldBeTrue)
+				So(cfg.GRPCEnabled, ShouldBeFalse)
+				So(cfg.GRPCBindAddr, ShouldEqual, ":23301")
+				So(cfg.ThemesDir, ShouldBeEmpty)
 			})
 		})
 	})
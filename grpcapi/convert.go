@@ -0,0 +1,275 @@
+package grpcapi
+
+import (
+	"github.com/ONSdigital/dp-table-renderer/grpcapi/rendererpb"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+)
+
+// toRenderRequest converts a protobuf RenderRequest to the model used by the renderer
+func toRenderRequest(pb *rendererpb.RenderRequest) *models.RenderRequest {
+	request := &models.RenderRequest{
+		Title:               pb.GetTitle(),
+		Subtitle:            pb.GetSubtitle(),
+		Source:              pb.GetSource(),
+		TableType:           pb.GetType(),
+		TableVersion:        pb.GetTypeVersion(),
+		Filename:            pb.GetFilename(),
+		Units:               pb.GetUnits(),
+		KeepHeadersTogether: pb.GetKeepHeadersTogether(),
+		Footnotes:           pb.GetFootnotes(),
+		Theme:               pb.GetTheme(),
+		Language:            pb.GetLanguage(),
+		InlineStyles:        pb.GetInlineStyles(),
+		Layout:              pb.GetLayout(),
+	}
+	for _, row := range pb.GetRowFormats() {
+		request.RowFormats = append(request.RowFormats, models.RowFormat{
+			Row:           int(row.GetRow()),
+			VerticalAlign: row.GetVerticalAlign(),
+			Heading:       row.GetHeading(),
+			Height:        row.GetHeight(),
+			Total:         row.GetTotal(),
+		})
+	}
+	for _, col := range pb.GetColumnFormats() {
+		request.ColumnFormats = append(request.ColumnFormats, models.ColumnFormat{
+			Column:  int(col.GetCol()),
+			Align:   col.GetAlign(),
+			Heading: col.GetHeading(),
+			Width:   col.GetWidth(),
+		})
+	}
+	for _, cell := range pb.GetCellFormats() {
+		request.CellFormats = append(request.CellFormats, models.CellFormat{
+			Row:           int(cell.GetRow()),
+			Column:        int(cell.GetCol()),
+			Align:         cell.GetAlign(),
+			VerticalAlign: cell.GetVerticalAlign(),
+			Rowspan:       int(cell.GetRowspan()),
+			Colspan:       int(cell.GetColspan()),
+		})
+	}
+	if chart := pb.GetChart(); chart != nil {
+		request.Chart = &models.ChartSpec{
+			Type:   chart.GetType(),
+			Width:  int(chart.GetWidth()),
+			Height: int(chart.GetHeight()),
+		}
+		if chart.CategoryColumn != nil {
+			column := int(chart.GetCategoryColumn())
+			request.Chart.CategoryColumn = &column
+		}
+		for _, column := range chart.GetSeriesColumns() {
+			request.Chart.SeriesColumns = append(request.Chart.SeriesColumns, int(column))
+		}
+		for _, row := range chart.GetRows() {
+			request.Chart.Rows = append(request.Chart.Rows, int(row))
+		}
+	}
+	if image := pb.GetImage(); image != nil {
+		request.Image = &models.ImageSpec{
+			Width: int(image.GetWidth()),
+			Scale: image.GetScale(),
+			Theme: image.GetTheme(),
+		}
+	}
+	if rows := pb.GetData(); len(rows) > 0 {
+		request.Data = make([][]string, len(rows))
+		for i, row := range rows {
+			request.Data[i] = row.GetCells()
+		}
+	}
+	return request
+}
+
+// fromRenderRequest converts the model used by the renderer to a protobuf RenderRequest
+func fromRenderRequest(request *models.RenderRequest) *rendererpb.RenderRequest {
+	pb := &rendererpb.RenderRequest{
+		Title:               request.Title,
+		Subtitle:            request.Subtitle,
+		Source:              request.Source,
+		Type:                request.TableType,
+		TypeVersion:         request.TableVersion,
+		Filename:            request.Filename,
+		Units:               request.Units,
+		KeepHeadersTogether: request.KeepHeadersTogether,
+		Footnotes:           request.Footnotes,
+		Theme:               request.Theme,
+		Language:            request.Language,
+		InlineStyles:        request.InlineStyles,
+		Layout:              request.Layout,
+	}
+	for _, row := range request.RowFormats {
+		pb.RowFormats = append(pb.RowFormats, &rendererpb.RowFormat{
+			Row:           int32(row.Row),
+			VerticalAlign: row.VerticalAlign,
+			Heading:       row.Heading,
+			Height:        row.Height,
+			Total:         row.Total,
+		})
+	}
+	for _, col := range request.ColumnFormats {
+		pb.ColumnFormats = append(pb.ColumnFormats, &rendererpb.ColumnFormat{
+			Col:     int32(col.Column),
+			Align:   col.Align,
+			Heading: col.Heading,
+			Width:   col.Width,
+		})
+	}
+	for _, cell := range request.CellFormats {
+		pb.CellFormats = append(pb.CellFormats, &rendererpb.CellFormat{
+			Row:           int32(cell.Row),
+			Col:           int32(cell.Column),
+			Align:         cell.Align,
+			VerticalAlign: cell.VerticalAlign,
+			Rowspan:       int32(cell.Rowspan),
+			Colspan:       int32(cell.Colspan),
+		})
+	}
+	if chart := request.Chart; chart != nil {
+		pb.Chart = &rendererpb.ChartSpec{
+			Type:   chart.Type,
+			Width:  int32(chart.Width),
+			Height: int32(chart.Height),
+		}
+		if chart.CategoryColumn != nil {
+			column := int32(*chart.CategoryColumn)
+			pb.Chart.CategoryColumn = &column
+		}
+		for _, column := range chart.SeriesColumns {
+			pb.Chart.SeriesColumns = append(pb.Chart.SeriesColumns, int32(column))
+		}
+		for _, row := range chart.Rows {
+			pb.Chart.Rows = append(pb.Chart.Rows, int32(row))
+		}
+	}
+	if image := request.Image; image != nil {
+		pb.Image = &rendererpb.ImageSpec{
+			Width: int32(image.Width),
+			Scale: image.Scale,
+			Theme: image.Theme,
+		}
+	}
+	for _, row := range request.Data {
+		pb.Data = append(pb.Data, &rendererpb.Row{Cells: row})
+	}
+	return pb
+}
+
+// toParseRequest converts a protobuf ParseRequest to the model used by the parser
+func toParseRequest(pb *rendererpb.ParseRequest) *models.ParseRequest {
+	alignments := pb.GetAlignmentClasses()
+	request := &models.ParseRequest{
+		Title:               pb.GetTitle(),
+		Subtitle:            pb.GetSubtitle(),
+		Source:              pb.GetSource(),
+		Filename:            pb.GetFilename(),
+		Units:               pb.GetUnits(),
+		KeepHeadersTogether: pb.GetKeepHeadersTogether(),
+		Footnotes:           pb.GetFootnotes(),
+		TableHTML:           pb.GetTableHtml(),
+		IgnoreFirstRow:      pb.GetIgnoreFirstRow(),
+		IgnoreFirstColumn:   pb.GetIgnoreFirstColumn(),
+		HeaderRows:          int(pb.GetHeaderRows()),
+		HeaderCols:          int(pb.GetHeaderCols()),
+		CurrentTableWidth:   int(pb.GetCurrentTableWidth()),
+		CurrentTableHeight:  int(pb.GetCurrentTableHeight()),
+		SingleEmHeight:      pb.GetSingleEmHeight(),
+		CellSizeUnits:       pb.GetCellSizeUnits(),
+		ColumnWidthToIgnore: pb.GetColumnWidthToIgnore(),
+		CellLayout:          pb.GetCellLayout(),
+		InferStructure:      pb.GetInferStructure(),
+		TableSelector:       pb.GetTableSelector(),
+		Handsontable:        toHandsontableState(pb.GetHandsontable()),
+		TableMarkdown:       pb.GetTableMarkdown(),
+		TableText:           pb.GetTableText(),
+		Docx:                pb.GetDocx(),
+		Ods:                 pb.GetOds(),
+		Sheet:               pb.GetSheet(),
+		Range:               pb.GetRange(),
+		AlignmentClasses: models.ParseAlignments{
+			Top:     alignments.GetTop(),
+			Middle:  alignments.GetMiddle(),
+			Bottom:  alignments.GetBottom(),
+			Left:    alignments.GetLeft(),
+			Right:   alignments.GetRight(),
+			Center:  alignments.GetCenter(),
+			Justify: alignments.GetJustify(),
+		},
+	}
+	for _, boundary := range pb.GetColumnBoundaries() {
+		request.ColumnBoundaries = append(request.ColumnBoundaries, int(boundary))
+	}
+	return request
+}
+
+// toHandsontableState converts the protobuf state of a Handsontable editor to the model used by the parser
+func toHandsontableState(pb *rendererpb.HandsontableState) *models.HandsontableState {
+	if pb == nil {
+		return nil
+	}
+	state := &models.HandsontableState{
+		ColWidths:  models.HandsontableSizes{Each: pb.GetColWidths()},
+		RowHeights: models.HandsontableSizes{Each: pb.GetRowHeights()},
+	}
+	for _, row := range pb.GetData() {
+		values := make([]models.HandsontableValue, len(row.GetCells()))
+		for i, cell := range row.GetCells() {
+			values[i] = models.HandsontableValue(cell)
+		}
+		state.Data = append(state.Data, values)
+	}
+	for _, m := range pb.GetMergeCells() {
+		state.MergeCells = append(state.MergeCells, models.HandsontableMerge{
+			Row:     int(m.GetRow()),
+			Col:     int(m.GetCol()),
+			Rowspan: int(m.GetRowspan()),
+			Colspan: int(m.GetColspan()),
+		})
+	}
+	for _, c := range pb.GetCell() {
+		state.Cell = append(state.Cell, models.HandsontableCell{Row: int(c.GetRow()), Col: int(c.GetCol()), ClassName: c.GetClassName()})
+	}
+	return state
+}
+
+// fromParseResponse converts the response of the parser to a protobuf ParseResponse
+func fromParseResponse(response *parser.ResponseModel) *rendererpb.ParseResponse {
+	pb := &rendererpb.ParseResponse{
+		Table:       fromRenderRequest(&response.JSON),
+		PreviewHtml: response.PreviewHTML,
+		CellLayout:  response.CellLayout,
+	}
+	if inferred := response.Inferred; inferred != nil {
+		pb.Inferred = &rendererpb.Inferred{
+			HeaderRows: int32(inferred.HeaderRows),
+			HeaderCols: int32(inferred.HeaderCols),
+			Title:      inferred.Title,
+			Footnotes:  inferred.Footnotes,
+		}
+		for _, row := range inferred.TotalRows {
+			pb.Inferred.TotalRows = append(pb.Inferred.TotalRows, int32(row))
+		}
+	}
+	for _, warning := range response.Warnings {
+		pb.Warnings = append(pb.Warnings, &rendererpb.Warning{Code: warning.Code, Message: warning.Message, Location: warning.Location})
+	}
+	return pb
+}
+
+// fromTablesResponse converts the tables listed by the parser to a protobuf TablesResponse
+func fromTablesResponse(response *parser.TablesResponse) *rendererpb.TablesResponse {
+	pb := &rendererpb.TablesResponse{}
+	for _, table := range response.Tables {
+		pb.Tables = append(pb.Tables, &rendererpb.TableSummary{
+			Index:   int32(table.Index),
+			Id:      table.ID,
+			Class:   table.Class,
+			Title:   table.Title,
+			Rows:    int32(table.Rows),
+			Columns: int32(table.Columns),
+		})
+	}
+	return pb
+}
@@ -0,0 +1,66 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
+	"github.com/ONSdigital/log.go/v2/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain identifies the renderer as the source of the error codes reported in ErrorInfo
+const errorDomain = "dp-table-renderer"
+
+// a map of error codes to the grpc status code returned to the client, equivalent to the http status of the REST api.
+// Codes that are not in the map are internal errors.
+var errorStatusMap = map[string]codes.Code{
+	models.CodeInvalidJSON:       codes.InvalidArgument,
+	models.CodeMissingData:       codes.InvalidArgument,
+	models.CodeMissingFields:     codes.InvalidArgument,
+	models.CodeInvalidTableHTML:  codes.InvalidArgument,
+	models.CodeTableNotFound:     codes.InvalidArgument,
+	models.CodeInvalidDocument:   codes.InvalidArgument,
+	models.CodeInvalidCellFormat: codes.InvalidArgument,
+	models.CodeTableTooLarge:     codes.InvalidArgument,
+	models.CodeUnknownTheme:      codes.InvalidArgument,
+	models.CodeInvalidChart:      codes.InvalidArgument,
+	models.CodeInvalidImage:      codes.InvalidArgument,
+	models.CodeRequestTooLarge:   codes.ResourceExhausted,
+	models.CodeUnknownRenderType: codes.NotFound,
+}
+
+// statusError converts an error to a grpc status error. The code of the error is reported in an ErrorInfo detail,
+// with the details of the error as its metadata.
+func statusError(ctx context.Context, err error) error {
+	code := codes.Internal
+	info := &errdetails.ErrorInfo{Reason: models.CodeInternal, Domain: errorDomain}
+	message := "Internal server error"
+
+	var modelError *models.Error
+	if errors.As(err, &modelError) {
+		info.Reason = modelError.Code
+		if c, ok := errorStatusMap[modelError.Code]; ok {
+			code = c
+			message = modelError.Message
+			if len(modelError.Details) > 0 {
+				info.Metadata = make(map[string]string, len(modelError.Details))
+				for key, value := range modelError.Details {
+					info.Metadata[key] = fmt.Sprint(value)
+				}
+			}
+		}
+	}
+	log.Error(ctx, "grpc request failed", err, log.Data{"code": info.Reason, "status": code.String()})
+	tracing.RecordError(ctx, err)
+
+	st, detailsErr := status.New(code, message).WithDetails(info)
+	if detailsErr != nil {
+		return status.Error(code, message)
+	}
+	return st.Err()
+}
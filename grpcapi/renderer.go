@@ -0,0 +1,242 @@
+package grpcapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+
+	"github.com/ONSdigital/dp-table-renderer/grpcapi/rendererpb"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// chunkSize is the maximum size of the data in each RenderChunk
+const chunkSize = 64 * 1024
+
+// rendererServer implements the TableRenderer service
+type rendererServer struct {
+	rendererpb.UnimplementedTableRendererServer
+	limits models.Limits
+}
+
+// Render renders a table, streaming the output in chunks
+func (s *rendererServer) Render(request *rendererpb.RenderTableRequest, stream rendererpb.TableRenderer_RenderServer) error {
+	ctx, span := tracing.StartSpan(stream.Context(), "render table", tracing.Format.String(request.GetFormat()))
+	defer span.End()
+
+	format, ok := renderer.FindFormat(request.GetFormat())
+	if !ok {
+		return statusError(ctx, models.NewError(models.CodeUnknownRenderType, "Unknown render type", nil))
+	}
+	renderRequest, err := s.validRenderRequest(ctx, request.GetTable())
+	if err != nil {
+		return statusError(ctx, err)
+	}
+	span.SetAttributes(tracing.Filename.String(renderRequest.Filename))
+	span.SetAttributes(tracing.TableAttributes(renderRequest)...)
+
+	chunks := &chunkWriter{stream: stream, contentType: format.ContentType}
+	if format.AltText != nil {
+		chunks.altText = format.AltText(ctx, renderRequest)
+	}
+	writer := bufio.NewWriterSize(chunks, chunkSize)
+	renderCtx, renderSpan := tracing.StartSpan(ctx, "render", tracing.Format.String(format.Name))
+	err = format.Write(renderCtx, writer, renderRequest)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil && chunks.sent == 0 {
+		// always send the content type, even if there is no content
+		err = chunks.send(nil)
+	}
+	tracing.EndSpan(renderSpan, err)
+	if err != nil {
+		return statusError(ctx, err)
+	}
+
+	span.SetAttributes(tracing.OutputBytes.Int(chunks.bytes))
+	log.Info(ctx, "rendered a table over grpc", log.Data{"file_name": renderRequest.Filename, "response_bytes": chunks.bytes})
+	return nil
+}
+
+// Parse converts an html table into the table used to render it
+func (s *rendererServer) Parse(ctx context.Context, request *rendererpb.ParseRequest) (*rendererpb.ParseResponse, error) {
+	return s.parse(ctx, request, "html", (*models.ParseRequest).ValidateParseRequest, parser.ParseHTML, "parsed an HTML table over grpc")
+}
+
+// ParseHandsontable converts the state of a Handsontable editor into the table used to render it
+func (s *rendererServer) ParseHandsontable(ctx context.Context, request *rendererpb.ParseRequest) (*rendererpb.ParseResponse, error) {
+	return s.parse(ctx, request, "handsontable", (*models.ParseRequest).ValidateHandsontableRequest, parser.ParseHandsontable, "parsed the state of a Handsontable editor over grpc")
+}
+
+// ParseMarkdown converts the first pipe table in markdown into the table used to render it
+func (s *rendererServer) ParseMarkdown(ctx context.Context, request *rendererpb.ParseRequest) (*rendererpb.ParseResponse, error) {
+	return s.parse(ctx, request, "markdown", (*models.ParseRequest).ValidateMarkdownRequest, parser.ParseMarkdown, "parsed a markdown table over grpc")
+}
+
+// ParseText converts a table of fixed-width text into the table used to render it
+func (s *rendererServer) ParseText(ctx context.Context, request *rendererpb.ParseRequest) (*rendererpb.ParseResponse, error) {
+	return s.parse(ctx, request, "text", (*models.ParseRequest).ValidateTextRequest, parser.ParseText, "parsed a fixed-width text table over grpc")
+}
+
+// ParseDocx converts a table of a Word document into the table used to render it
+func (s *rendererServer) ParseDocx(ctx context.Context, request *rendererpb.ParseRequest) (*rendererpb.ParseResponse, error) {
+	return s.parse(ctx, request, "docx", (*models.ParseRequest).ValidateDocxRequest, parser.ParseDocx, "parsed a table in a Word document over grpc")
+}
+
+// ParseOds converts a range of a sheet of an OpenDocument spreadsheet into the table used to render it
+func (s *rendererServer) ParseOds(ctx context.Context, request *rendererpb.ParseRequest) (*rendererpb.ParseResponse, error) {
+	return s.parse(ctx, request, "ods", (*models.ParseRequest).ValidateOdsRequest, parser.ParseOds, "parsed a range of an OpenDocument spreadsheet over grpc")
+}
+
+// parse validates a request to parse a table of the given input format, then parses it with the parse function
+func (s *rendererServer) parse(ctx context.Context, request *rendererpb.ParseRequest, format string,
+	validate func(*models.ParseRequest, context.Context) error,
+	parse func(context.Context, *models.ParseRequest) ([]byte, error), message string) (*rendererpb.ParseResponse, error) {
+
+	ctx, span := tracing.StartSpan(ctx, "parse table", tracing.Format.String(format))
+	defer span.End()
+
+	parseRequest := toParseRequest(request)
+	parseRequest.Limits = s.limits
+	span.SetAttributes(tracing.Filename.String(parseRequest.Filename))
+	err := s.limits.CheckParseRequest(parseRequest)
+	if err == nil {
+		err = validate(parseRequest, ctx)
+	}
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	parseCtx, parseSpan := tracing.StartSpan(ctx, "parse", tracing.Format.String(format))
+	body, err := parse(parseCtx, parseRequest)
+	tracing.EndSpan(parseSpan, err)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	var response parser.ResponseModel
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	log.Info(ctx, message, log.Data{"file_name": parseRequest.Filename})
+	return fromParseResponse(&response), nil
+}
+
+// ListTables lists the tables in the html of a request
+func (s *rendererServer) ListTables(ctx context.Context, request *rendererpb.ParseRequest) (*rendererpb.TablesResponse, error) {
+	return s.listTables(ctx, request, "html", (*models.ParseRequest).ValidateParseRequest, parser.ListTables, "listed the tables in HTML over grpc")
+}
+
+// ListDocxTables lists the tables in the Word document of a request
+func (s *rendererServer) ListDocxTables(ctx context.Context, request *rendererpb.ParseRequest) (*rendererpb.TablesResponse, error) {
+	return s.listTables(ctx, request, "docx", (*models.ParseRequest).ValidateDocxRequest, parser.ListDocxTables, "listed the tables in a Word document over grpc")
+}
+
+// listTables validates a request to list the tables of the given input format, then lists them with the list function
+func (s *rendererServer) listTables(ctx context.Context, request *rendererpb.ParseRequest, format string,
+	validate func(*models.ParseRequest, context.Context) error,
+	list func(context.Context, *models.ParseRequest) ([]byte, error), message string) (*rendererpb.TablesResponse, error) {
+
+	ctx, span := tracing.StartSpan(ctx, "list tables", tracing.Format.String(format))
+	defer span.End()
+
+	parseRequest := toParseRequest(request)
+	parseRequest.Limits = s.limits
+	span.SetAttributes(tracing.Filename.String(parseRequest.Filename))
+	err := s.limits.CheckParseRequest(parseRequest)
+	if err == nil {
+		err = validate(parseRequest, ctx)
+	}
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	parseCtx, parseSpan := tracing.StartSpan(ctx, "parse", tracing.Format.String(format))
+	body, err := list(parseCtx, parseRequest)
+	tracing.EndSpan(parseSpan, err)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	var response parser.TablesResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, statusError(ctx, err)
+	}
+
+	log.Info(ctx, message, log.Data{"file_name": parseRequest.Filename, "tables": len(response.Tables)})
+	return fromTablesResponse(&response), nil
+}
+
+// Validate checks that a table would be accepted by Render, returning its size
+func (s *rendererServer) Validate(ctx context.Context, request *rendererpb.RenderRequest) (*rendererpb.ValidateResponse, error) {
+	renderRequest, err := s.validRenderRequest(ctx, request)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	size := renderRequest.Size()
+	return &rendererpb.ValidateResponse{
+		Rows:    int32(size.Rows),
+		Columns: int32(size.Columns),
+		Cells:   int32(size.Cells),
+		Merges:  int32(size.Merges),
+	}, nil
+}
+
+// validRenderRequest converts the request to the model used by the renderer, checking that it is within the limits and valid
+func (s *rendererServer) validRenderRequest(ctx context.Context, request *rendererpb.RenderRequest) (*models.RenderRequest, error) {
+	if request == nil {
+		return nil, models.ErrorNoData
+	}
+	_, span := tracing.StartSpan(ctx, "validate")
+	renderRequest := toRenderRequest(request)
+	err := s.limits.CheckRenderRequest(renderRequest)
+	if err == nil {
+		err = renderRequest.ValidateRenderRequest()
+	}
+	tracing.EndSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return renderRequest, nil
+}
+
+// chunkWriter sends everything written to it as RenderChunks, setting the content type and alt text on the first chunk
+type chunkWriter struct {
+	stream      rendererpb.TableRenderer_RenderServer
+	contentType string
+	altText     string
+	sent        int // the number of chunks sent
+	bytes       int // the number of bytes sent
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	for written := 0; written < len(p); {
+		n := len(p) - written
+		if n > chunkSize {
+			n = chunkSize
+		}
+		if err := w.send(p[written : written+n]); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return len(p), nil
+}
+
+// send sends a single chunk
+func (w *chunkWriter) send(data []byte) error {
+	chunk := &rendererpb.RenderChunk{Data: data}
+	if w.sent == 0 {
+		chunk.ContentType = w.contentType
+		chunk.AltText = w.altText
+	}
+	if err := w.stream.Send(chunk); err != nil {
+		return err
+	}
+	w.sent++
+	w.bytes += len(data)
+	return nil
+}
@@ -0,0 +1,2182 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        (unknown)
+// source: renderer.proto
+
+// The table renderer api, for services that would rather not encode large tables as json.
+// The messages mirror the json models of the REST api.
+
+package rendererpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RenderRequest is a table to render - see models.RenderRequest
+type RenderRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Title               string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Subtitle            string                 `protobuf:"bytes,2,opt,name=subtitle,proto3" json:"subtitle,omitempty"`
+	Source              string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Type                string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	TypeVersion         string                 `protobuf:"bytes,5,opt,name=type_version,json=typeVersion,proto3" json:"type_version,omitempty"`
+	Filename            string                 `protobuf:"bytes,6,opt,name=filename,proto3" json:"filename,omitempty"`
+	Units               string                 `protobuf:"bytes,7,opt,name=units,proto3" json:"units,omitempty"`
+	KeepHeadersTogether bool                   `protobuf:"varint,8,opt,name=keep_headers_together,json=keepHeadersTogether,proto3" json:"keep_headers_together,omitempty"`
+	RowFormats          []*RowFormat           `protobuf:"bytes,9,rep,name=row_formats,json=rowFormats,proto3" json:"row_formats,omitempty"`
+	ColumnFormats       []*ColumnFormat        `protobuf:"bytes,10,rep,name=column_formats,json=columnFormats,proto3" json:"column_formats,omitempty"`
+	CellFormats         []*CellFormat          `protobuf:"bytes,11,rep,name=cell_formats,json=cellFormats,proto3" json:"cell_formats,omitempty"`
+	Data                []*Row                 `protobuf:"bytes,12,rep,name=data,proto3" json:"data,omitempty"`
+	Footnotes           []string               `protobuf:"bytes,13,rep,name=footnotes,proto3" json:"footnotes,omitempty"`
+	Theme               string                 `protobuf:"bytes,14,opt,name=theme,proto3" json:"theme,omitempty"`                                    // the theme used to render html - see renderer.ThemeNames
+	Language            string                 `protobuf:"bytes,15,opt,name=language,proto3" json:"language,omitempty"`                              // the language of the table, used as the lang of an html document
+	InlineStyles        bool                   `protobuf:"varint,16,opt,name=inline_styles,json=inlineStyles,proto3" json:"inline_styles,omitempty"` // if true, the styles of an html document are applied to each element
+	Layout              string                 `protobuf:"bytes,17,opt,name=layout,proto3" json:"layout,omitempty"`                                  // the responsive layout of html on narrow screens: stacked or scroll
+	Chart               *ChartSpec             `protobuf:"bytes,18,opt,name=chart,proto3" json:"chart,omitempty"`                                    // how the table is drawn as an svg-chart
+	Image               *ImageSpec             `protobuf:"bytes,19,opt,name=image,proto3" json:"image,omitempty"`                                    // the size and colours of a png
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RenderRequest) Reset() {
+	*x = RenderRequest{}
+	mi := &file_renderer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderRequest) ProtoMessage() {}
+
+func (x *RenderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderRequest.ProtoReflect.Descriptor instead.
+func (*RenderRequest) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RenderRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetSubtitle() string {
+	if x != nil {
+		return x.Subtitle
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetTypeVersion() string {
+	if x != nil {
+		return x.TypeVersion
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetKeepHeadersTogether() bool {
+	if x != nil {
+		return x.KeepHeadersTogether
+	}
+	return false
+}
+
+func (x *RenderRequest) GetRowFormats() []*RowFormat {
+	if x != nil {
+		return x.RowFormats
+	}
+	return nil
+}
+
+func (x *RenderRequest) GetColumnFormats() []*ColumnFormat {
+	if x != nil {
+		return x.ColumnFormats
+	}
+	return nil
+}
+
+func (x *RenderRequest) GetCellFormats() []*CellFormat {
+	if x != nil {
+		return x.CellFormats
+	}
+	return nil
+}
+
+func (x *RenderRequest) GetData() []*Row {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *RenderRequest) GetFootnotes() []string {
+	if x != nil {
+		return x.Footnotes
+	}
+	return nil
+}
+
+func (x *RenderRequest) GetTheme() string {
+	if x != nil {
+		return x.Theme
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetInlineStyles() bool {
+	if x != nil {
+		return x.InlineStyles
+	}
+	return false
+}
+
+func (x *RenderRequest) GetLayout() string {
+	if x != nil {
+		return x.Layout
+	}
+	return ""
+}
+
+func (x *RenderRequest) GetChart() *ChartSpec {
+	if x != nil {
+		return x.Chart
+	}
+	return nil
+}
+
+func (x *RenderRequest) GetImage() *ImageSpec {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+// ImageSpec chooses the size and colours of a png image - see models.ImageSpec
+type ImageSpec struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Width         int32                  `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Scale         float64                `protobuf:"fixed64,2,opt,name=scale,proto3" json:"scale,omitempty"`
+	Theme         string                 `protobuf:"bytes,3,opt,name=theme,proto3" json:"theme,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageSpec) Reset() {
+	*x = ImageSpec{}
+	mi := &file_renderer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageSpec) ProtoMessage() {}
+
+func (x *ImageSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageSpec.ProtoReflect.Descriptor instead.
+func (*ImageSpec) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ImageSpec) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *ImageSpec) GetScale() float64 {
+	if x != nil {
+		return x.Scale
+	}
+	return 0
+}
+
+func (x *ImageSpec) GetTheme() string {
+	if x != nil {
+		return x.Theme
+	}
+	return ""
+}
+
+// ChartSpec chooses the type of a chart and the parts of the table it shows - see models.ChartSpec
+type ChartSpec struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Type           string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // line, bar or stacked-bar
+	CategoryColumn *int32                 `protobuf:"varint,2,opt,name=category_column,json=categoryColumn,proto3,oneof" json:"category_column,omitempty"`
+	SeriesColumns  []int32                `protobuf:"varint,3,rep,packed,name=series_columns,json=seriesColumns,proto3" json:"series_columns,omitempty"`
+	Rows           []int32                `protobuf:"varint,4,rep,packed,name=rows,proto3" json:"rows,omitempty"`
+	Width          int32                  `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`
+	Height         int32                  `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ChartSpec) Reset() {
+	*x = ChartSpec{}
+	mi := &file_renderer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChartSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChartSpec) ProtoMessage() {}
+
+func (x *ChartSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChartSpec.ProtoReflect.Descriptor instead.
+func (*ChartSpec) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ChartSpec) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChartSpec) GetCategoryColumn() int32 {
+	if x != nil && x.CategoryColumn != nil {
+		return *x.CategoryColumn
+	}
+	return 0
+}
+
+func (x *ChartSpec) GetSeriesColumns() []int32 {
+	if x != nil {
+		return x.SeriesColumns
+	}
+	return nil
+}
+
+func (x *ChartSpec) GetRows() []int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *ChartSpec) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *ChartSpec) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+// Row is a row of data
+type Row struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cells         []string               `protobuf:"bytes,1,rep,name=cells,proto3" json:"cells,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Row) Reset() {
+	*x = Row{}
+	mi := &file_renderer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Row) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Row) ProtoMessage() {}
+
+func (x *Row) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Row.ProtoReflect.Descriptor instead.
+func (*Row) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Row) GetCells() []string {
+	if x != nil {
+		return x.Cells
+	}
+	return nil
+}
+
+type RowFormat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	VerticalAlign string                 `protobuf:"bytes,2,opt,name=vertical_align,json=verticalAlign,proto3" json:"vertical_align,omitempty"` // Top, Middle or Bottom
+	Heading       bool                   `protobuf:"varint,3,opt,name=heading,proto3" json:"heading,omitempty"`
+	Height        string                 `protobuf:"bytes,4,opt,name=height,proto3" json:"height,omitempty"`
+	Total         bool                   `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"` // a row of totals
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RowFormat) Reset() {
+	*x = RowFormat{}
+	mi := &file_renderer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RowFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RowFormat) ProtoMessage() {}
+
+func (x *RowFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RowFormat.ProtoReflect.Descriptor instead.
+func (*RowFormat) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RowFormat) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *RowFormat) GetVerticalAlign() string {
+	if x != nil {
+		return x.VerticalAlign
+	}
+	return ""
+}
+
+func (x *RowFormat) GetHeading() bool {
+	if x != nil {
+		return x.Heading
+	}
+	return false
+}
+
+func (x *RowFormat) GetHeight() string {
+	if x != nil {
+		return x.Height
+	}
+	return ""
+}
+
+func (x *RowFormat) GetTotal() bool {
+	if x != nil {
+		return x.Total
+	}
+	return false
+}
+
+type ColumnFormat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Col           int32                  `protobuf:"varint,1,opt,name=col,proto3" json:"col,omitempty"`
+	Align         string                 `protobuf:"bytes,2,opt,name=align,proto3" json:"align,omitempty"` // Left, Center or Right
+	Heading       bool                   `protobuf:"varint,3,opt,name=heading,proto3" json:"heading,omitempty"`
+	Width         string                 `protobuf:"bytes,4,opt,name=width,proto3" json:"width,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColumnFormat) Reset() {
+	*x = ColumnFormat{}
+	mi := &file_renderer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColumnFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColumnFormat) ProtoMessage() {}
+
+func (x *ColumnFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColumnFormat.ProtoReflect.Descriptor instead.
+func (*ColumnFormat) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ColumnFormat) GetCol() int32 {
+	if x != nil {
+		return x.Col
+	}
+	return 0
+}
+
+func (x *ColumnFormat) GetAlign() string {
+	if x != nil {
+		return x.Align
+	}
+	return ""
+}
+
+func (x *ColumnFormat) GetHeading() bool {
+	if x != nil {
+		return x.Heading
+	}
+	return false
+}
+
+func (x *ColumnFormat) GetWidth() string {
+	if x != nil {
+		return x.Width
+	}
+	return ""
+}
+
+type CellFormat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Col           int32                  `protobuf:"varint,2,opt,name=col,proto3" json:"col,omitempty"`
+	Align         string                 `protobuf:"bytes,3,opt,name=align,proto3" json:"align,omitempty"`                                      // Left, Center or Right
+	VerticalAlign string                 `protobuf:"bytes,4,opt,name=vertical_align,json=verticalAlign,proto3" json:"vertical_align,omitempty"` // Top, Middle or Bottom
+	Rowspan       int32                  `protobuf:"varint,5,opt,name=rowspan,proto3" json:"rowspan,omitempty"`
+	Colspan       int32                  `protobuf:"varint,6,opt,name=colspan,proto3" json:"colspan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CellFormat) Reset() {
+	*x = CellFormat{}
+	mi := &file_renderer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CellFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CellFormat) ProtoMessage() {}
+
+func (x *CellFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CellFormat.ProtoReflect.Descriptor instead.
+func (*CellFormat) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CellFormat) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *CellFormat) GetCol() int32 {
+	if x != nil {
+		return x.Col
+	}
+	return 0
+}
+
+func (x *CellFormat) GetAlign() string {
+	if x != nil {
+		return x.Align
+	}
+	return ""
+}
+
+func (x *CellFormat) GetVerticalAlign() string {
+	if x != nil {
+		return x.VerticalAlign
+	}
+	return ""
+}
+
+func (x *CellFormat) GetRowspan() int32 {
+	if x != nil {
+		return x.Rowspan
+	}
+	return 0
+}
+
+func (x *CellFormat) GetColspan() int32 {
+	if x != nil {
+		return x.Colspan
+	}
+	return 0
+}
+
+type RenderTableRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Format        string                 `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"` // html, csv, xlsx etc - as the render_type of /render/{render_type}
+	Table         *RenderRequest         `protobuf:"bytes,2,opt,name=table,proto3" json:"table,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderTableRequest) Reset() {
+	*x = RenderTableRequest{}
+	mi := &file_renderer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderTableRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderTableRequest) ProtoMessage() {}
+
+func (x *RenderTableRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderTableRequest.ProtoReflect.Descriptor instead.
+func (*RenderTableRequest) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RenderTableRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *RenderTableRequest) GetTable() *RenderRequest {
+	if x != nil {
+		return x.Table
+	}
+	return nil
+}
+
+// RenderChunk is part of the rendered output. The content type and alt text are set on the first chunk only.
+type RenderChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContentType   string                 `protobuf:"bytes,1,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	AltText       string                 `protobuf:"bytes,3,opt,name=alt_text,json=altText,proto3" json:"alt_text,omitempty"` // the text alternative of an image format, e.g. png
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderChunk) Reset() {
+	*x = RenderChunk{}
+	mi := &file_renderer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderChunk) ProtoMessage() {}
+
+func (x *RenderChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderChunk.ProtoReflect.Descriptor instead.
+func (*RenderChunk) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RenderChunk) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *RenderChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *RenderChunk) GetAltText() string {
+	if x != nil {
+		return x.AltText
+	}
+	return ""
+}
+
+// ParseRequest is an html table to parse - see models.ParseRequest
+type ParseRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Title               string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Subtitle            string                 `protobuf:"bytes,2,opt,name=subtitle,proto3" json:"subtitle,omitempty"`
+	Source              string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Filename            string                 `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
+	Units               string                 `protobuf:"bytes,5,opt,name=units,proto3" json:"units,omitempty"`
+	KeepHeadersTogether bool                   `protobuf:"varint,6,opt,name=keep_headers_together,json=keepHeadersTogether,proto3" json:"keep_headers_together,omitempty"`
+	Footnotes           []string               `protobuf:"bytes,7,rep,name=footnotes,proto3" json:"footnotes,omitempty"`
+	TableHtml           string                 `protobuf:"bytes,8,opt,name=table_html,json=tableHtml,proto3" json:"table_html,omitempty"`
+	IgnoreFirstRow      bool                   `protobuf:"varint,9,opt,name=ignore_first_row,json=ignoreFirstRow,proto3" json:"ignore_first_row,omitempty"`
+	IgnoreFirstColumn   bool                   `protobuf:"varint,10,opt,name=ignore_first_column,json=ignoreFirstColumn,proto3" json:"ignore_first_column,omitempty"`
+	HeaderRows          int32                  `protobuf:"varint,11,opt,name=header_rows,json=headerRows,proto3" json:"header_rows,omitempty"`
+	HeaderCols          int32                  `protobuf:"varint,12,opt,name=header_cols,json=headerCols,proto3" json:"header_cols,omitempty"`
+	CurrentTableWidth   int32                  `protobuf:"varint,13,opt,name=current_table_width,json=currentTableWidth,proto3" json:"current_table_width,omitempty"`
+	CurrentTableHeight  int32                  `protobuf:"varint,14,opt,name=current_table_height,json=currentTableHeight,proto3" json:"current_table_height,omitempty"`
+	SingleEmHeight      float32                `protobuf:"fixed32,15,opt,name=single_em_height,json=singleEmHeight,proto3" json:"single_em_height,omitempty"`
+	CellSizeUnits       string                 `protobuf:"bytes,16,opt,name=cell_size_units,json=cellSizeUnits,proto3" json:"cell_size_units,omitempty"`
+	ColumnWidthToIgnore string                 `protobuf:"bytes,17,opt,name=column_width_to_ignore,json=columnWidthToIgnore,proto3" json:"column_width_to_ignore,omitempty"`
+	AlignmentClasses    *ParseAlignments       `protobuf:"bytes,18,opt,name=alignment_classes,json=alignmentClasses,proto3" json:"alignment_classes,omitempty"`
+	// handsontable, standard or auto (the default): whether each row of the html includes the cells hidden by merged cells
+	CellLayout string `protobuf:"bytes,19,opt,name=cell_layout,json=cellLayout,proto3" json:"cell_layout,omitempty"`
+	// infer headings, totals and the title from the html where they are not given
+	InferStructure bool `protobuf:"varint,20,opt,name=infer_structure,json=inferStructure,proto3" json:"infer_structure,omitempty"`
+	// chooses the table in table_html, which may be a complete document: its index, or a css selector. Defaults to the first table
+	TableSelector string `protobuf:"bytes,21,opt,name=table_selector,json=tableSelector,proto3" json:"table_selector,omitempty"`
+	// the state of a Handsontable editor, parsed by ParseHandsontable instead of table_html
+	Handsontable *HandsontableState `protobuf:"bytes,22,opt,name=handsontable,proto3" json:"handsontable,omitempty"`
+	// markdown containing a pipe table, parsed by ParseMarkdown instead of table_html
+	TableMarkdown string `protobuf:"bytes,23,opt,name=table_markdown,json=tableMarkdown,proto3" json:"table_markdown,omitempty"`
+	// a table of fixed-width text, parsed by ParseText instead of table_html
+	TableText string `protobuf:"bytes,24,opt,name=table_text,json=tableText,proto3" json:"table_text,omitempty"`
+	// the character positions at which the columns of table_text after the first begin. Detected from the text if empty
+	ColumnBoundaries []int32 `protobuf:"varint,25,rep,packed,name=column_boundaries,json=columnBoundaries,proto3" json:"column_boundaries,omitempty"`
+	// a Word document (.docx), whose tables are parsed by ParseDocx instead of table_html
+	Docx []byte `protobuf:"bytes,26,opt,name=docx,proto3" json:"docx,omitempty"`
+	// an OpenDocument spreadsheet (.ods), a range of whose cells is parsed by ParseOds instead of table_html
+	Ods []byte `protobuf:"bytes,27,opt,name=ods,proto3" json:"ods,omitempty"`
+	// the name of the sheet of ods to parse, or its index. Defaults to the first sheet
+	Sheet string `protobuf:"bytes,28,opt,name=sheet,proto3" json:"sheet,omitempty"`
+	// the range of cells of the sheet to parse, e.g. A1:D10. Defaults to the cells that aren't empty
+	Range         string `protobuf:"bytes,29,opt,name=range,proto3" json:"range,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseRequest) Reset() {
+	*x = ParseRequest{}
+	mi := &file_renderer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseRequest) ProtoMessage() {}
+
+func (x *ParseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseRequest.ProtoReflect.Descriptor instead.
+func (*ParseRequest) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ParseRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetSubtitle() string {
+	if x != nil {
+		return x.Subtitle
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetUnits() string {
+	if x != nil {
+		return x.Units
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetKeepHeadersTogether() bool {
+	if x != nil {
+		return x.KeepHeadersTogether
+	}
+	return false
+}
+
+func (x *ParseRequest) GetFootnotes() []string {
+	if x != nil {
+		return x.Footnotes
+	}
+	return nil
+}
+
+func (x *ParseRequest) GetTableHtml() string {
+	if x != nil {
+		return x.TableHtml
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetIgnoreFirstRow() bool {
+	if x != nil {
+		return x.IgnoreFirstRow
+	}
+	return false
+}
+
+func (x *ParseRequest) GetIgnoreFirstColumn() bool {
+	if x != nil {
+		return x.IgnoreFirstColumn
+	}
+	return false
+}
+
+func (x *ParseRequest) GetHeaderRows() int32 {
+	if x != nil {
+		return x.HeaderRows
+	}
+	return 0
+}
+
+func (x *ParseRequest) GetHeaderCols() int32 {
+	if x != nil {
+		return x.HeaderCols
+	}
+	return 0
+}
+
+func (x *ParseRequest) GetCurrentTableWidth() int32 {
+	if x != nil {
+		return x.CurrentTableWidth
+	}
+	return 0
+}
+
+func (x *ParseRequest) GetCurrentTableHeight() int32 {
+	if x != nil {
+		return x.CurrentTableHeight
+	}
+	return 0
+}
+
+func (x *ParseRequest) GetSingleEmHeight() float32 {
+	if x != nil {
+		return x.SingleEmHeight
+	}
+	return 0
+}
+
+func (x *ParseRequest) GetCellSizeUnits() string {
+	if x != nil {
+		return x.CellSizeUnits
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetColumnWidthToIgnore() string {
+	if x != nil {
+		return x.ColumnWidthToIgnore
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetAlignmentClasses() *ParseAlignments {
+	if x != nil {
+		return x.AlignmentClasses
+	}
+	return nil
+}
+
+func (x *ParseRequest) GetCellLayout() string {
+	if x != nil {
+		return x.CellLayout
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetInferStructure() bool {
+	if x != nil {
+		return x.InferStructure
+	}
+	return false
+}
+
+func (x *ParseRequest) GetTableSelector() string {
+	if x != nil {
+		return x.TableSelector
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetHandsontable() *HandsontableState {
+	if x != nil {
+		return x.Handsontable
+	}
+	return nil
+}
+
+func (x *ParseRequest) GetTableMarkdown() string {
+	if x != nil {
+		return x.TableMarkdown
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetTableText() string {
+	if x != nil {
+		return x.TableText
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetColumnBoundaries() []int32 {
+	if x != nil {
+		return x.ColumnBoundaries
+	}
+	return nil
+}
+
+func (x *ParseRequest) GetDocx() []byte {
+	if x != nil {
+		return x.Docx
+	}
+	return nil
+}
+
+func (x *ParseRequest) GetOds() []byte {
+	if x != nil {
+		return x.Ods
+	}
+	return nil
+}
+
+func (x *ParseRequest) GetSheet() string {
+	if x != nil {
+		return x.Sheet
+	}
+	return ""
+}
+
+func (x *ParseRequest) GetRange() string {
+	if x != nil {
+		return x.Range
+	}
+	return ""
+}
+
+// HandsontableState is the native state of a Handsontable editor - see models.HandsontableState
+type HandsontableState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the values of the cells, from getData()
+	Data       []*Row               `protobuf:"bytes,1,rep,name=data,proto3" json:"data,omitempty"`
+	MergeCells []*HandsontableMerge `protobuf:"bytes,2,rep,name=merge_cells,json=mergeCells,proto3" json:"merge_cells,omitempty"`
+	Cell       []*HandsontableCell  `protobuf:"bytes,3,rep,name=cell,proto3" json:"cell,omitempty"`
+	// the width of each column in pixels, or 0 for none
+	ColWidths []float64 `protobuf:"fixed64,4,rep,packed,name=col_widths,json=colWidths,proto3" json:"col_widths,omitempty"`
+	// the height of each row in pixels, or 0 for none
+	RowHeights    []float64 `protobuf:"fixed64,5,rep,packed,name=row_heights,json=rowHeights,proto3" json:"row_heights,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandsontableState) Reset() {
+	*x = HandsontableState{}
+	mi := &file_renderer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandsontableState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandsontableState) ProtoMessage() {}
+
+func (x *HandsontableState) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandsontableState.ProtoReflect.Descriptor instead.
+func (*HandsontableState) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *HandsontableState) GetData() []*Row {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *HandsontableState) GetMergeCells() []*HandsontableMerge {
+	if x != nil {
+		return x.MergeCells
+	}
+	return nil
+}
+
+func (x *HandsontableState) GetCell() []*HandsontableCell {
+	if x != nil {
+		return x.Cell
+	}
+	return nil
+}
+
+func (x *HandsontableState) GetColWidths() []float64 {
+	if x != nil {
+		return x.ColWidths
+	}
+	return nil
+}
+
+func (x *HandsontableState) GetRowHeights() []float64 {
+	if x != nil {
+		return x.RowHeights
+	}
+	return nil
+}
+
+type HandsontableMerge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Col           int32                  `protobuf:"varint,2,opt,name=col,proto3" json:"col,omitempty"`
+	Rowspan       int32                  `protobuf:"varint,3,opt,name=rowspan,proto3" json:"rowspan,omitempty"`
+	Colspan       int32                  `protobuf:"varint,4,opt,name=colspan,proto3" json:"colspan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandsontableMerge) Reset() {
+	*x = HandsontableMerge{}
+	mi := &file_renderer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandsontableMerge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandsontableMerge) ProtoMessage() {}
+
+func (x *HandsontableMerge) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandsontableMerge.ProtoReflect.Descriptor instead.
+func (*HandsontableMerge) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HandsontableMerge) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *HandsontableMerge) GetCol() int32 {
+	if x != nil {
+		return x.Col
+	}
+	return 0
+}
+
+func (x *HandsontableMerge) GetRowspan() int32 {
+	if x != nil {
+		return x.Rowspan
+	}
+	return 0
+}
+
+func (x *HandsontableMerge) GetColspan() int32 {
+	if x != nil {
+		return x.Colspan
+	}
+	return 0
+}
+
+type HandsontableCell struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           int32                  `protobuf:"varint,1,opt,name=row,proto3" json:"row,omitempty"`
+	Col           int32                  `protobuf:"varint,2,opt,name=col,proto3" json:"col,omitempty"`
+	ClassName     string                 `protobuf:"bytes,3,opt,name=class_name,json=className,proto3" json:"class_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandsontableCell) Reset() {
+	*x = HandsontableCell{}
+	mi := &file_renderer_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandsontableCell) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandsontableCell) ProtoMessage() {}
+
+func (x *HandsontableCell) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandsontableCell.ProtoReflect.Descriptor instead.
+func (*HandsontableCell) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *HandsontableCell) GetRow() int32 {
+	if x != nil {
+		return x.Row
+	}
+	return 0
+}
+
+func (x *HandsontableCell) GetCol() int32 {
+	if x != nil {
+		return x.Col
+	}
+	return 0
+}
+
+func (x *HandsontableCell) GetClassName() string {
+	if x != nil {
+		return x.ClassName
+	}
+	return ""
+}
+
+type ParseAlignments struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Top           string                 `protobuf:"bytes,1,opt,name=top,proto3" json:"top,omitempty"`
+	Middle        string                 `protobuf:"bytes,2,opt,name=middle,proto3" json:"middle,omitempty"`
+	Bottom        string                 `protobuf:"bytes,3,opt,name=bottom,proto3" json:"bottom,omitempty"`
+	Left          string                 `protobuf:"bytes,4,opt,name=left,proto3" json:"left,omitempty"`
+	Right         string                 `protobuf:"bytes,5,opt,name=right,proto3" json:"right,omitempty"`
+	Center        string                 `protobuf:"bytes,6,opt,name=center,proto3" json:"center,omitempty"`
+	Justify       string                 `protobuf:"bytes,7,opt,name=justify,proto3" json:"justify,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseAlignments) Reset() {
+	*x = ParseAlignments{}
+	mi := &file_renderer_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseAlignments) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseAlignments) ProtoMessage() {}
+
+func (x *ParseAlignments) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseAlignments.ProtoReflect.Descriptor instead.
+func (*ParseAlignments) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ParseAlignments) GetTop() string {
+	if x != nil {
+		return x.Top
+	}
+	return ""
+}
+
+func (x *ParseAlignments) GetMiddle() string {
+	if x != nil {
+		return x.Middle
+	}
+	return ""
+}
+
+func (x *ParseAlignments) GetBottom() string {
+	if x != nil {
+		return x.Bottom
+	}
+	return ""
+}
+
+func (x *ParseAlignments) GetLeft() string {
+	if x != nil {
+		return x.Left
+	}
+	return ""
+}
+
+func (x *ParseAlignments) GetRight() string {
+	if x != nil {
+		return x.Right
+	}
+	return ""
+}
+
+func (x *ParseAlignments) GetCenter() string {
+	if x != nil {
+		return x.Center
+	}
+	return ""
+}
+
+func (x *ParseAlignments) GetJustify() string {
+	if x != nil {
+		return x.Justify
+	}
+	return ""
+}
+
+type ParseResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Table       *RenderRequest         `protobuf:"bytes,1,opt,name=table,proto3" json:"table,omitempty"`
+	PreviewHtml string                 `protobuf:"bytes,2,opt,name=preview_html,json=previewHtml,proto3" json:"preview_html,omitempty"`
+	// the cell layout used to parse the html: handsontable or standard
+	CellLayout string `protobuf:"bytes,3,opt,name=cell_layout,json=cellLayout,proto3" json:"cell_layout,omitempty"`
+	// the structure inferred from the html, if requested
+	Inferred *Inferred `protobuf:"bytes,4,opt,name=inferred,proto3" json:"inferred,omitempty"`
+	// problems with the parsed table, such as unused footnotes
+	Warnings      []*Warning `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseResponse) Reset() {
+	*x = ParseResponse{}
+	mi := &file_renderer_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseResponse) ProtoMessage() {}
+
+func (x *ParseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseResponse.ProtoReflect.Descriptor instead.
+func (*ParseResponse) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ParseResponse) GetTable() *RenderRequest {
+	if x != nil {
+		return x.Table
+	}
+	return nil
+}
+
+func (x *ParseResponse) GetPreviewHtml() string {
+	if x != nil {
+		return x.PreviewHtml
+	}
+	return ""
+}
+
+func (x *ParseResponse) GetCellLayout() string {
+	if x != nil {
+		return x.CellLayout
+	}
+	return ""
+}
+
+func (x *ParseResponse) GetInferred() *Inferred {
+	if x != nil {
+		return x.Inferred
+	}
+	return nil
+}
+
+func (x *ParseResponse) GetWarnings() []*Warning {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Warning describes a problem with a table that doesn't prevent it being rendered
+type Warning struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// unused_footnote or dangling_reference
+	Code    string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// where the problem was found, e.g. title, data[2][3] or footnotes[0]
+	Location      string `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Warning) Reset() {
+	*x = Warning{}
+	mi := &file_renderer_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Warning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Warning) ProtoMessage() {}
+
+func (x *Warning) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Warning.ProtoReflect.Descriptor instead.
+func (*Warning) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Warning) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Warning) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Warning) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+// Inferred reports the structure inferred from the html. A field is empty if nothing was inferred, or it was given in the request.
+type Inferred struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HeaderRows    int32                  `protobuf:"varint,1,opt,name=header_rows,json=headerRows,proto3" json:"header_rows,omitempty"`
+	HeaderCols    int32                  `protobuf:"varint,2,opt,name=header_cols,json=headerCols,proto3" json:"header_cols,omitempty"`
+	TotalRows     []int32                `protobuf:"varint,3,rep,packed,name=total_rows,json=totalRows,proto3" json:"total_rows,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Footnotes     []string               `protobuf:"bytes,5,rep,name=footnotes,proto3" json:"footnotes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Inferred) Reset() {
+	*x = Inferred{}
+	mi := &file_renderer_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Inferred) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Inferred) ProtoMessage() {}
+
+func (x *Inferred) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Inferred.ProtoReflect.Descriptor instead.
+func (*Inferred) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Inferred) GetHeaderRows() int32 {
+	if x != nil {
+		return x.HeaderRows
+	}
+	return 0
+}
+
+func (x *Inferred) GetHeaderCols() int32 {
+	if x != nil {
+		return x.HeaderCols
+	}
+	return 0
+}
+
+func (x *Inferred) GetTotalRows() []int32 {
+	if x != nil {
+		return x.TotalRows
+	}
+	return nil
+}
+
+func (x *Inferred) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Inferred) GetFootnotes() []string {
+	if x != nil {
+		return x.Footnotes
+	}
+	return nil
+}
+
+// TablesResponse lists the tables found in html, or a document
+type TablesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tables        []*TableSummary        `protobuf:"bytes,1,rep,name=tables,proto3" json:"tables,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TablesResponse) Reset() {
+	*x = TablesResponse{}
+	mi := &file_renderer_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TablesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TablesResponse) ProtoMessage() {}
+
+func (x *TablesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TablesResponse.ProtoReflect.Descriptor instead.
+func (*TablesResponse) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TablesResponse) GetTables() []*TableSummary {
+	if x != nil {
+		return x.Tables
+	}
+	return nil
+}
+
+// TableSummary describes a table found in html
+type TableSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Class         string                 `protobuf:"bytes,3,opt,name=class,proto3" json:"class,omitempty"`
+	Title         string                 `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Rows          int32                  `protobuf:"varint,5,opt,name=rows,proto3" json:"rows,omitempty"`
+	Columns       int32                  `protobuf:"varint,6,opt,name=columns,proto3" json:"columns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TableSummary) Reset() {
+	*x = TableSummary{}
+	mi := &file_renderer_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TableSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableSummary) ProtoMessage() {}
+
+func (x *TableSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableSummary.ProtoReflect.Descriptor instead.
+func (*TableSummary) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TableSummary) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *TableSummary) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TableSummary) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *TableSummary) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *TableSummary) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *TableSummary) GetColumns() int32 {
+	if x != nil {
+		return x.Columns
+	}
+	return 0
+}
+
+// ValidateResponse describes a valid table. An invalid table is reported as an INVALID_ARGUMENT error.
+type ValidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          int32                  `protobuf:"varint,1,opt,name=rows,proto3" json:"rows,omitempty"`
+	Columns       int32                  `protobuf:"varint,2,opt,name=columns,proto3" json:"columns,omitempty"`
+	Cells         int32                  `protobuf:"varint,3,opt,name=cells,proto3" json:"cells,omitempty"`
+	Merges        int32                  `protobuf:"varint,4,opt,name=merges,proto3" json:"merges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_renderer_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_renderer_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_renderer_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ValidateResponse) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *ValidateResponse) GetColumns() int32 {
+	if x != nil {
+		return x.Columns
+	}
+	return 0
+}
+
+func (x *ValidateResponse) GetCells() int32 {
+	if x != nil {
+		return x.Cells
+	}
+	return 0
+}
+
+func (x *ValidateResponse) GetMerges() int32 {
+	if x != nil {
+		return x.Merges
+	}
+	return 0
+}
+
+var File_renderer_proto protoreflect.FileDescriptor
+
+var file_renderer_proto_rawDesc = string([]byte{
+	0x0a, 0x0e, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x13, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x22, 0xec, 0x05, 0x0a, 0x0d, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x73, 0x75, 0x62, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x75, 0x62, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x74, 0x79, 0x70,
+	0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x6b, 0x65,
+	0x65, 0x70, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x5f, 0x74, 0x6f, 0x67, 0x65, 0x74,
+	0x68, 0x65, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x6b, 0x65, 0x65, 0x70, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x54, 0x6f, 0x67, 0x65, 0x74, 0x68, 0x65, 0x72, 0x12, 0x3f,
+	0x0a, 0x0b, 0x72, 0x6f, 0x77, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x77, 0x46, 0x6f, 0x72,
+	0x6d, 0x61, 0x74, 0x52, 0x0a, 0x72, 0x6f, 0x77, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x12,
+	0x48, 0x0a, 0x0e, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x0d, 0x63, 0x6f, 0x6c, 0x75,
+	0x6d, 0x6e, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x12, 0x42, 0x0a, 0x0c, 0x63, 0x65, 0x6c,
+	0x6c, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x65, 0x6c, 0x6c, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x52, 0x0b, 0x63, 0x65, 0x6c, 0x6c, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x73, 0x12, 0x2c, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x70,
+	0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x6f, 0x77, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x66,
+	0x6f, 0x6f, 0x74, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09,
+	0x66, 0x6f, 0x6f, 0x74, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x68, 0x65,
+	0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x12,
+	0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x69,
+	0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x73, 0x74, 0x79, 0x6c, 0x65, 0x73, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x6c, 0x69, 0x6e, 0x65, 0x53, 0x74, 0x79, 0x6c, 0x65, 0x73,
+	0x12, 0x16, 0x0a, 0x06, 0x6c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x34, 0x0a, 0x05, 0x63, 0x68, 0x61, 0x72,
+	0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68,
+	0x61, 0x72, 0x74, 0x53, 0x70, 0x65, 0x63, 0x52, 0x05, 0x63, 0x68, 0x61, 0x72, 0x74, 0x12, 0x34,
+	0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x53, 0x70, 0x65, 0x63, 0x52, 0x05, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x22, 0x4d, 0x0a, 0x09, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x53, 0x70, 0x65,
+	0x63, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x61, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x68,
+	0x65, 0x6d, 0x65, 0x22, 0xca, 0x01, 0x0a, 0x09, 0x43, 0x68, 0x61, 0x72, 0x74, 0x53, 0x70, 0x65,
+	0x63, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2c, 0x0a, 0x0f, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72,
+	0x79, 0x5f, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00,
+	0x52, 0x0e, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x63, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x05, 0x52, 0x0d, 0x73, 0x65, 0x72,
+	0x69, 0x65, 0x73, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f,
+	0x77, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x42, 0x12, 0x0a, 0x10,
+	0x5f, 0x63, 0x61, 0x74, 0x65, 0x67, 0x6f, 0x72, 0x79, 0x5f, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x22, 0x1b, 0x0a, 0x03, 0x52, 0x6f, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x22, 0x8c, 0x01,
+	0x0a, 0x09, 0x52, 0x6f, 0x77, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x72,
+	0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x72, 0x6f, 0x77, 0x12, 0x25, 0x0a,
+	0x0e, 0x76, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x76, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x41,
+	0x6c, 0x69, 0x67, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x22, 0x66, 0x0a, 0x0c,
+	0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03,
+	0x63, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x63, 0x6f, 0x6c, 0x12, 0x14,
+	0x0a, 0x05, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61,
+	0x6c, 0x69, 0x67, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x14,
+	0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x22, 0xa1, 0x01, 0x0a, 0x0a, 0x43, 0x65, 0x6c, 0x6c, 0x46, 0x6f, 0x72,
+	0x6d, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x03, 0x72, 0x6f, 0x77, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x03, 0x63, 0x6f, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x69, 0x67, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x12, 0x25, 0x0a,
+	0x0e, 0x76, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x76, 0x65, 0x72, 0x74, 0x69, 0x63, 0x61, 0x6c, 0x41,
+	0x6c, 0x69, 0x67, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x6f, 0x77, 0x73, 0x70, 0x61, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72, 0x6f, 0x77, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x63, 0x6f, 0x6c, 0x73, 0x70, 0x61, 0x6e, 0x22, 0x66, 0x0a, 0x12, 0x52, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16,
+	0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x38, 0x0a, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x22, 0x5f, 0x0a, 0x0b, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12,
+	0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x6c, 0x74, 0x5f, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x74, 0x54, 0x65, 0x78,
+	0x74, 0x22, 0xd5, 0x08, 0x0a, 0x0c, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x62, 0x74,
+	0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x75, 0x62, 0x74,
+	0x69, 0x74, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x32,
+	0x0a, 0x15, 0x6b, 0x65, 0x65, 0x70, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x5f, 0x74,
+	0x6f, 0x67, 0x65, 0x74, 0x68, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x6b,
+	0x65, 0x65, 0x70, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x54, 0x6f, 0x67, 0x65, 0x74, 0x68,
+	0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x6f, 0x6f, 0x74, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x66, 0x6f, 0x6f, 0x74, 0x6e, 0x6f, 0x74, 0x65, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x68, 0x74, 0x6d, 0x6c, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x48, 0x74, 0x6d, 0x6c, 0x12,
+	0x28, 0x0a, 0x10, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x5f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f,
+	0x72, 0x6f, 0x77, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x69, 0x67, 0x6e, 0x6f, 0x72,
+	0x65, 0x46, 0x69, 0x72, 0x73, 0x74, 0x52, 0x6f, 0x77, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x67, 0x6e,
+	0x6f, 0x72, 0x65, 0x5f, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x46, 0x69,
+	0x72, 0x73, 0x74, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x5f, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x6f, 0x77, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6c, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0a, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6c, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x77, 0x69, 0x64,
+	0x74, 0x68, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x57, 0x69, 0x64, 0x74, 0x68, 0x12, 0x30, 0x0a, 0x14, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x68, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x12, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x28, 0x0a,
+	0x10, 0x73, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x5f, 0x65, 0x6d, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0e, 0x73, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x45,
+	0x6d, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x63, 0x65, 0x6c, 0x6c, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x63, 0x65, 0x6c, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12,
+	0x33, 0x0a, 0x16, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x5f, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f,
+	0x74, 0x6f, 0x5f, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x13, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x57, 0x69, 0x64, 0x74, 0x68, 0x54, 0x6f, 0x49, 0x67,
+	0x6e, 0x6f, 0x72, 0x65, 0x12, 0x51, 0x0a, 0x11, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e,
+	0x74, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x24, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72,
+	0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x41, 0x6c, 0x69, 0x67, 0x6e,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x10, 0x61, 0x6c, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74,
+	0x43, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x65, 0x6c, 0x6c, 0x5f,
+	0x6c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x65,
+	0x6c, 0x6c, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x66, 0x65,
+	0x72, 0x5f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0e, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x75, 0x72,
+	0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x65, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x4a, 0x0a, 0x0c, 0x68, 0x61, 0x6e, 0x64,
+	0x73, 0x6f, 0x6e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26,
+	0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x6f, 0x6e, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x6f, 0x6e, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6d, 0x61,
+	0x72, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x18, 0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x4d, 0x61, 0x72, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x18, 0x18, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x54, 0x65, 0x78, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x5f, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x19, 0x20, 0x03, 0x28, 0x05, 0x52, 0x10, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x42, 0x6f, 0x75,
+	0x6e, 0x64, 0x61, 0x72, 0x69, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x63, 0x78, 0x18,
+	0x1a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x6f, 0x63, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x6f,
+	0x64, 0x73, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6f, 0x64, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x68, 0x65, 0x65, 0x74, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x68,
+	0x65, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x1d, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x22, 0x85, 0x02, 0x0a, 0x11, 0x48, 0x61,
+	0x6e, 0x64, 0x73, 0x6f, 0x6e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x2c, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x77, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x47, 0x0a,
+	0x0b, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x5f, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x6f, 0x6e,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x67,
+	0x65, 0x43, 0x65, 0x6c, 0x6c, 0x73, 0x12, 0x39, 0x0a, 0x04, 0x63, 0x65, 0x6c, 0x6c, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x73,
+	0x6f, 0x6e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x65, 0x6c, 0x6c, 0x52, 0x04, 0x63, 0x65, 0x6c,
+	0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x5f, 0x77, 0x69, 0x64, 0x74, 0x68, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x01, 0x52, 0x09, 0x63, 0x6f, 0x6c, 0x57, 0x69, 0x64, 0x74, 0x68, 0x73,
+	0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x6f, 0x77, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x01, 0x52, 0x0a, 0x72, 0x6f, 0x77, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x73, 0x22, 0x6b, 0x0a, 0x11, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x6f, 0x6e, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x6f, 0x77, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x03, 0x72, 0x6f, 0x77, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x6f, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x63, 0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x6f,
+	0x77, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x72, 0x6f, 0x77,
+	0x73, 0x70, 0x61, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x73, 0x70, 0x61, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x63, 0x6f, 0x6c, 0x73, 0x70, 0x61, 0x6e, 0x22, 0x55,
+	0x0a, 0x10, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x6f, 0x6e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x43, 0x65,
+	0x6c, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x03, 0x72, 0x6f, 0x77, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x03, 0x63, 0x6f, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x73,
+	0x73, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xaf, 0x01, 0x0a, 0x0f, 0x50, 0x61, 0x72, 0x73, 0x65, 0x41,
+	0x6c, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x6f, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x6f, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x6d,
+	0x69, 0x64, 0x64, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x69, 0x64,
+	0x64, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x6f, 0x74, 0x74, 0x6f, 0x6d, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x6f, 0x74, 0x74, 0x6f, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6c,
+	0x65, 0x66, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x72, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x18, 0x0a,
+	0x07, 0x6a, 0x75, 0x73, 0x74, 0x69, 0x66, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6a, 0x75, 0x73, 0x74, 0x69, 0x66, 0x79, 0x22, 0x82, 0x02, 0x0a, 0x0d, 0x50, 0x61, 0x72, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x05, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x5f, 0x68,
+	0x74, 0x6d, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x48, 0x74, 0x6d, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x65, 0x6c, 0x6c, 0x5f, 0x6c,
+	0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x65, 0x6c,
+	0x6c, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x39, 0x0a, 0x08, 0x69, 0x6e, 0x66, 0x65, 0x72,
+	0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x64, 0x70, 0x2e, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x49, 0x6e, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x52, 0x08, 0x69, 0x6e, 0x66, 0x65, 0x72, 0x72,
+	0x65, 0x64, 0x12, 0x38, 0x0a, 0x08, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x72, 0x6e, 0x69,
+	0x6e, 0x67, 0x52, 0x08, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x53, 0x0a, 0x07,
+	0x57, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x22, 0x9f, 0x01, 0x0a, 0x08, 0x49, 0x6e, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x12, 0x1f,
+	0x0a, 0x0b, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x6f, 0x77, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6c, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x43, 0x6f, 0x6c, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x6f, 0x77, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x6f, 0x6f, 0x74, 0x6e, 0x6f, 0x74,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x66, 0x6f, 0x6f, 0x74, 0x6e, 0x6f,
+	0x74, 0x65, 0x73, 0x22, 0x4b, 0x0a, 0x0e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x06, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x62, 0x6c,
+	0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x06, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x73,
+	0x22, 0x8e, 0x01, 0x0a, 0x0c, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6c, 0x61, 0x73, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x14, 0x0a,
+	0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69,
+	0x74, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d,
+	0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e,
+	0x73, 0x22, 0x6e, 0x0a, 0x10, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x63, 0x6f, 0x6c, 0x75,
+	0x6d, 0x6e, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x63, 0x65, 0x6c, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x72,
+	0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6d, 0x65, 0x72, 0x67, 0x65,
+	0x73, 0x32, 0xec, 0x06, 0x0a, 0x0d, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x65, 0x72, 0x12, 0x55, 0x0a, 0x06, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x27, 0x2e,
+	0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x05, 0x50, 0x61,
+	0x72, 0x73, 0x65, 0x12, 0x21, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c,
+	0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72,
+	0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a, 0x0a, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x64, 0x70,
+	0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x5a, 0x0a, 0x11, 0x50, 0x61, 0x72, 0x73, 0x65, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x6f, 0x6e,
+	0x74, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x21, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56, 0x0a, 0x0d,
+	0x50, 0x61, 0x72, 0x73, 0x65, 0x4d, 0x61, 0x72, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x21, 0x2e,
+	0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x22, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x09, 0x50, 0x61, 0x72, 0x73, 0x65, 0x54, 0x65, 0x78,
+	0x74, 0x12, 0x21, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x09, 0x50, 0x61, 0x72, 0x73,
+	0x65, 0x44, 0x6f, 0x63, 0x78, 0x12, 0x21, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61,
+	0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x0e,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x6f, 0x63, 0x78, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x21,
+	0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x23, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x08, 0x50, 0x61, 0x72, 0x73, 0x65, 0x4f,
+	0x64, 0x73, 0x12, 0x21, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x08, 0x56, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x12, 0x22, 0x2e, 0x64, 0x70, 0x2e, 0x74, 0x61, 0x62, 0x6c, 0x65,
+	0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x64, 0x70, 0x2e, 0x74,
+	0x61, 0x62, 0x6c, 0x65, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x42, 0x3c, 0x5a, 0x3a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4f,
+	0x4e, 0x53, 0x64, 0x69, 0x67, 0x69, 0x74, 0x61, 0x6c, 0x2f, 0x64, 0x70, 0x2d, 0x74, 0x61, 0x62,
+	0x6c, 0x65, 0x2d, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x2f, 0x67, 0x72, 0x70, 0x63,
+	0x61, 0x70, 0x69, 0x2f, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x65, 0x72, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_renderer_proto_rawDescOnce sync.Once
+	file_renderer_proto_rawDescData []byte
+)
+
+func file_renderer_proto_rawDescGZIP() []byte {
+	file_renderer_proto_rawDescOnce.Do(func() {
+		file_renderer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_renderer_proto_rawDesc), len(file_renderer_proto_rawDesc)))
+	})
+	return file_renderer_proto_rawDescData
+}
+
+var file_renderer_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_renderer_proto_goTypes = []any{
+	(*RenderRequest)(nil),      // 0: dp.tablerenderer.v1.RenderRequest
+	(*ImageSpec)(nil),          // 1: dp.tablerenderer.v1.ImageSpec
+	(*ChartSpec)(nil),          // 2: dp.tablerenderer.v1.ChartSpec
+	(*Row)(nil),                // 3: dp.tablerenderer.v1.Row
+	(*RowFormat)(nil),          // 4: dp.tablerenderer.v1.RowFormat
+	(*ColumnFormat)(nil),       // 5: dp.tablerenderer.v1.ColumnFormat
+	(*CellFormat)(nil),         // 6: dp.tablerenderer.v1.CellFormat
+	(*RenderTableRequest)(nil), // 7: dp.tablerenderer.v1.RenderTableRequest
+	(*RenderChunk)(nil),        // 8: dp.tablerenderer.v1.RenderChunk
+	(*ParseRequest)(nil),       // 9: dp.tablerenderer.v1.ParseRequest
+	(*HandsontableState)(nil),  // 10: dp.tablerenderer.v1.HandsontableState
+	(*HandsontableMerge)(nil),  // 11: dp.tablerenderer.v1.HandsontableMerge
+	(*HandsontableCell)(nil),   // 12: dp.tablerenderer.v1.HandsontableCell
+	(*ParseAlignments)(nil),    // 13: dp.tablerenderer.v1.ParseAlignments
+	(*ParseResponse)(nil),      // 14: dp.tablerenderer.v1.ParseResponse
+	(*Warning)(nil),            // 15: dp.tablerenderer.v1.Warning
+	(*Inferred)(nil),           // 16: dp.tablerenderer.v1.Inferred
+	(*TablesResponse)(nil),     // 17: dp.tablerenderer.v1.TablesResponse
+	(*TableSummary)(nil),       // 18: dp.tablerenderer.v1.TableSummary
+	(*ValidateResponse)(nil),   // 19: dp.tablerenderer.v1.ValidateResponse
+}
+var file_renderer_proto_depIdxs = []int32{
+	4,  // 0: dp.tablerenderer.v1.RenderRequest.row_formats:type_name -> dp.tablerenderer.v1.RowFormat
+	5,  // 1: dp.tablerenderer.v1.RenderRequest.column_formats:type_name -> dp.tablerenderer.v1.ColumnFormat
+	6,  // 2: dp.tablerenderer.v1.RenderRequest.cell_formats:type_name -> dp.tablerenderer.v1.CellFormat
+	3,  // 3: dp.tablerenderer.v1.RenderRequest.data:type_name -> dp.tablerenderer.v1.Row
+	2,  // 4: dp.tablerenderer.v1.RenderRequest.chart:type_name -> dp.tablerenderer.v1.ChartSpec
+	1,  // 5: dp.tablerenderer.v1.RenderRequest.image:type_name -> dp.tablerenderer.v1.ImageSpec
+	0,  // 6: dp.tablerenderer.v1.RenderTableRequest.table:type_name -> dp.tablerenderer.v1.RenderRequest
+	13, // 7: dp.tablerenderer.v1.ParseRequest.alignment_classes:type_name -> dp.tablerenderer.v1.ParseAlignments
+	10, // 8: dp.tablerenderer.v1.ParseRequest.handsontable:type_name -> dp.tablerenderer.v1.HandsontableState
+	3,  // 9: dp.tablerenderer.v1.HandsontableState.data:type_name -> dp.tablerenderer.v1.Row
+	11, // 10: dp.tablerenderer.v1.HandsontableState.merge_cells:type_name -> dp.tablerenderer.v1.HandsontableMerge
+	12, // 11: dp.tablerenderer.v1.HandsontableState.cell:type_name -> dp.tablerenderer.v1.HandsontableCell
+	0,  // 12: dp.tablerenderer.v1.ParseResponse.table:type_name -> dp.tablerenderer.v1.RenderRequest
+	16, // 13: dp.tablerenderer.v1.ParseResponse.inferred:type_name -> dp.tablerenderer.v1.Inferred
+	15, // 14: dp.tablerenderer.v1.ParseResponse.warnings:type_name -> dp.tablerenderer.v1.Warning
+	18, // 15: dp.tablerenderer.v1.TablesResponse.tables:type_name -> dp.tablerenderer.v1.TableSummary
+	7,  // 16: dp.tablerenderer.v1.TableRenderer.Render:input_type -> dp.tablerenderer.v1.RenderTableRequest
+	9,  // 17: dp.tablerenderer.v1.TableRenderer.Parse:input_type -> dp.tablerenderer.v1.ParseRequest
+	9,  // 18: dp.tablerenderer.v1.TableRenderer.ListTables:input_type -> dp.tablerenderer.v1.ParseRequest
+	9,  // 19: dp.tablerenderer.v1.TableRenderer.ParseHandsontable:input_type -> dp.tablerenderer.v1.ParseRequest
+	9,  // 20: dp.tablerenderer.v1.TableRenderer.ParseMarkdown:input_type -> dp.tablerenderer.v1.ParseRequest
+	9,  // 21: dp.tablerenderer.v1.TableRenderer.ParseText:input_type -> dp.tablerenderer.v1.ParseRequest
+	9,  // 22: dp.tablerenderer.v1.TableRenderer.ParseDocx:input_type -> dp.tablerenderer.v1.ParseRequest
+	9,  // 23: dp.tablerenderer.v1.TableRenderer.ListDocxTables:input_type -> dp.tablerenderer.v1.ParseRequest
+	9,  // 24: dp.tablerenderer.v1.TableRenderer.ParseOds:input_type -> dp.tablerenderer.v1.ParseRequest
+	0,  // 25: dp.tablerenderer.v1.TableRenderer.Validate:input_type -> dp.tablerenderer.v1.RenderRequest
+	8,  // 26: dp.tablerenderer.v1.TableRenderer.Render:output_type -> dp.tablerenderer.v1.RenderChunk
+	14, // 27: dp.tablerenderer.v1.TableRenderer.Parse:output_type -> dp.tablerenderer.v1.ParseResponse
+	17, // 28: dp.tablerenderer.v1.TableRenderer.ListTables:output_type -> dp.tablerenderer.v1.TablesResponse
+	14, // 29: dp.tablerenderer.v1.TableRenderer.ParseHandsontable:output_type -> dp.tablerenderer.v1.ParseResponse
+	14, // 30: dp.tablerenderer.v1.TableRenderer.ParseMarkdown:output_type -> dp.tablerenderer.v1.ParseResponse
+	14, // 31: dp.tablerenderer.v1.TableRenderer.ParseText:output_type -> dp.tablerenderer.v1.ParseResponse
+	14, // 32: dp.tablerenderer.v1.TableRenderer.ParseDocx:output_type -> dp.tablerenderer.v1.ParseResponse
+	17, // 33: dp.tablerenderer.v1.TableRenderer.ListDocxTables:output_type -> dp.tablerenderer.v1.TablesResponse
+	14, // 34: dp.tablerenderer.v1.TableRenderer.ParseOds:output_type -> dp.tablerenderer.v1.ParseResponse
+	19, // 35: dp.tablerenderer.v1.TableRenderer.Validate:output_type -> dp.tablerenderer.v1.ValidateResponse
+	26, // [26:36] is the sub-list for method output_type
+	16, // [16:26] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_renderer_proto_init() }
+func file_renderer_proto_init() {
+	if File_renderer_proto != nil {
+		return
+	}
+	file_renderer_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_renderer_proto_rawDesc), len(file_renderer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_renderer_proto_goTypes,
+		DependencyIndexes: file_renderer_proto_depIdxs,
+		MessageInfos:      file_renderer_proto_msgTypes,
+	}.Build()
+	File_renderer_proto = out.File
+	file_renderer_proto_goTypes = nil
+	file_renderer_proto_depIdxs = nil
+}
@@ -0,0 +1,490 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: renderer.proto
+
+// The table renderer api, for services that would rather not encode large tables as json.
+// The messages mirror the json models of the REST api.
+
+package rendererpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TableRenderer_Render_FullMethodName            = "/dp.tablerenderer.v1.TableRenderer/Render"
+	TableRenderer_Parse_FullMethodName             = "/dp.tablerenderer.v1.TableRenderer/Parse"
+	TableRenderer_ListTables_FullMethodName        = "/dp.tablerenderer.v1.TableRenderer/ListTables"
+	TableRenderer_ParseHandsontable_FullMethodName = "/dp.tablerenderer.v1.TableRenderer/ParseHandsontable"
+	TableRenderer_ParseMarkdown_FullMethodName     = "/dp.tablerenderer.v1.TableRenderer/ParseMarkdown"
+	TableRenderer_ParseText_FullMethodName         = "/dp.tablerenderer.v1.TableRenderer/ParseText"
+	TableRenderer_ParseDocx_FullMethodName         = "/dp.tablerenderer.v1.TableRenderer/ParseDocx"
+	TableRenderer_ListDocxTables_FullMethodName    = "/dp.tablerenderer.v1.TableRenderer/ListDocxTables"
+	TableRenderer_ParseOds_FullMethodName          = "/dp.tablerenderer.v1.TableRenderer/ParseOds"
+	TableRenderer_Validate_FullMethodName          = "/dp.tablerenderer.v1.TableRenderer/Validate"
+)
+
+// TableRendererClient is the client API for TableRenderer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TableRendererClient interface {
+	// Render renders a table in the requested format, streaming the output in chunks
+	Render(ctx context.Context, in *RenderTableRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RenderChunk], error)
+	// Parse converts an html table into the table used to render it
+	Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// ListTables lists the tables in the html of a request, so that one can be chosen with table_selector
+	ListTables(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*TablesResponse, error)
+	// ParseHandsontable converts the state of a Handsontable editor, given in handsontable, into the table used to render it
+	ParseHandsontable(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// ParseMarkdown converts the first pipe table in table_markdown into the table used to render it
+	ParseMarkdown(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// ParseText converts the table of fixed-width text in table_text into the table used to render it
+	ParseText(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// ParseDocx converts a table of the Word document in docx, chosen by its index in table_selector, into the table used to render it
+	ParseDocx(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// ListDocxTables lists the tables in the Word document in docx, so that one can be chosen with table_selector
+	ListDocxTables(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*TablesResponse, error)
+	// ParseOds converts the range of cells of a sheet of the OpenDocument spreadsheet in ods, chosen by sheet and range, into the table used to render it
+	ParseOds(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error)
+	// Validate checks that a table would be accepted by Render
+	Validate(ctx context.Context, in *RenderRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+}
+
+type tableRendererClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTableRendererClient(cc grpc.ClientConnInterface) TableRendererClient {
+	return &tableRendererClient{cc}
+}
+
+func (c *tableRendererClient) Render(ctx context.Context, in *RenderTableRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RenderChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TableRenderer_ServiceDesc.Streams[0], TableRenderer_Render_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RenderTableRequest, RenderChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TableRenderer_RenderClient = grpc.ServerStreamingClient[RenderChunk]
+
+func (c *tableRendererClient) Parse(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, TableRenderer_Parse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableRendererClient) ListTables(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*TablesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TablesResponse)
+	err := c.cc.Invoke(ctx, TableRenderer_ListTables_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableRendererClient) ParseHandsontable(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, TableRenderer_ParseHandsontable_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableRendererClient) ParseMarkdown(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, TableRenderer_ParseMarkdown_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableRendererClient) ParseText(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, TableRenderer_ParseText_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableRendererClient) ParseDocx(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, TableRenderer_ParseDocx_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableRendererClient) ListDocxTables(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*TablesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TablesResponse)
+	err := c.cc.Invoke(ctx, TableRenderer_ListDocxTables_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableRendererClient) ParseOds(ctx context.Context, in *ParseRequest, opts ...grpc.CallOption) (*ParseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ParseResponse)
+	err := c.cc.Invoke(ctx, TableRenderer_ParseOds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tableRendererClient) Validate(ctx context.Context, in *RenderRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, TableRenderer_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TableRendererServer is the server API for TableRenderer service.
+// All implementations must embed UnimplementedTableRendererServer
+// for forward compatibility.
+type TableRendererServer interface {
+	// Render renders a table in the requested format, streaming the output in chunks
+	Render(*RenderTableRequest, grpc.ServerStreamingServer[RenderChunk]) error
+	// Parse converts an html table into the table used to render it
+	Parse(context.Context, *ParseRequest) (*ParseResponse, error)
+	// ListTables lists the tables in the html of a request, so that one can be chosen with table_selector
+	ListTables(context.Context, *ParseRequest) (*TablesResponse, error)
+	// ParseHandsontable converts the state of a Handsontable editor, given in handsontable, into the table used to render it
+	ParseHandsontable(context.Context, *ParseRequest) (*ParseResponse, error)
+	// ParseMarkdown converts the first pipe table in table_markdown into the table used to render it
+	ParseMarkdown(context.Context, *ParseRequest) (*ParseResponse, error)
+	// ParseText converts the table of fixed-width text in table_text into the table used to render it
+	ParseText(context.Context, *ParseRequest) (*ParseResponse, error)
+	// ParseDocx converts a table of the Word document in docx, chosen by its index in table_selector, into the table used to render it
+	ParseDocx(context.Context, *ParseRequest) (*ParseResponse, error)
+	// ListDocxTables lists the tables in the Word document in docx, so that one can be chosen with table_selector
+	ListDocxTables(context.Context, *ParseRequest) (*TablesResponse, error)
+	// ParseOds converts the range of cells of a sheet of the OpenDocument spreadsheet in ods, chosen by sheet and range, into the table used to render it
+	ParseOds(context.Context, *ParseRequest) (*ParseResponse, error)
+	// Validate checks that a table would be accepted by Render
+	Validate(context.Context, *RenderRequest) (*ValidateResponse, error)
+	mustEmbedUnimplementedTableRendererServer()
+}
+
+// UnimplementedTableRendererServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTableRendererServer struct{}
+
+func (UnimplementedTableRendererServer) Render(*RenderTableRequest, grpc.ServerStreamingServer[RenderChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method Render not implemented")
+}
+func (UnimplementedTableRendererServer) Parse(context.Context, *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Parse not implemented")
+}
+func (UnimplementedTableRendererServer) ListTables(context.Context, *ParseRequest) (*TablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTables not implemented")
+}
+func (UnimplementedTableRendererServer) ParseHandsontable(context.Context, *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ParseHandsontable not implemented")
+}
+func (UnimplementedTableRendererServer) ParseMarkdown(context.Context, *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ParseMarkdown not implemented")
+}
+func (UnimplementedTableRendererServer) ParseText(context.Context, *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ParseText not implemented")
+}
+func (UnimplementedTableRendererServer) ParseDocx(context.Context, *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ParseDocx not implemented")
+}
+func (UnimplementedTableRendererServer) ListDocxTables(context.Context, *ParseRequest) (*TablesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDocxTables not implemented")
+}
+func (UnimplementedTableRendererServer) ParseOds(context.Context, *ParseRequest) (*ParseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ParseOds not implemented")
+}
+func (UnimplementedTableRendererServer) Validate(context.Context, *RenderRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedTableRendererServer) mustEmbedUnimplementedTableRendererServer() {}
+func (UnimplementedTableRendererServer) testEmbeddedByValue()                       {}
+
+// UnsafeTableRendererServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TableRendererServer will
+// result in compilation errors.
+type UnsafeTableRendererServer interface {
+	mustEmbedUnimplementedTableRendererServer()
+}
+
+func RegisterTableRendererServer(s grpc.ServiceRegistrar, srv TableRendererServer) {
+	// If the following call pancis, it indicates UnimplementedTableRendererServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TableRenderer_ServiceDesc, srv)
+}
+
+func _TableRenderer_Render_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RenderTableRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TableRendererServer).Render(m, &grpc.GenericServerStream[RenderTableRequest, RenderChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TableRenderer_RenderServer = grpc.ServerStreamingServer[RenderChunk]
+
+func _TableRenderer_Parse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TableRendererServer).Parse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TableRenderer_Parse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TableRendererServer).Parse(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TableRenderer_ListTables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TableRendererServer).ListTables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TableRenderer_ListTables_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TableRendererServer).ListTables(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TableRenderer_ParseHandsontable_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TableRendererServer).ParseHandsontable(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TableRenderer_ParseHandsontable_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TableRendererServer).ParseHandsontable(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TableRenderer_ParseMarkdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TableRendererServer).ParseMarkdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TableRenderer_ParseMarkdown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TableRendererServer).ParseMarkdown(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TableRenderer_ParseText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TableRendererServer).ParseText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TableRenderer_ParseText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TableRendererServer).ParseText(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TableRenderer_ParseDocx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TableRendererServer).ParseDocx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TableRenderer_ParseDocx_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TableRendererServer).ParseDocx(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TableRenderer_ListDocxTables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TableRendererServer).ListDocxTables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TableRenderer_ListDocxTables_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TableRendererServer).ListDocxTables(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TableRenderer_ParseOds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ParseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TableRendererServer).ParseOds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TableRenderer_ParseOds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TableRendererServer).ParseOds(ctx, req.(*ParseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TableRenderer_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TableRendererServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TableRenderer_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TableRendererServer).Validate(ctx, req.(*RenderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TableRenderer_ServiceDesc is the grpc.ServiceDesc for TableRenderer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TableRenderer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "dp.tablerenderer.v1.TableRenderer",
+	HandlerType: (*TableRendererServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Parse",
+			Handler:    _TableRenderer_Parse_Handler,
+		},
+		{
+			MethodName: "ListTables",
+			Handler:    _TableRenderer_ListTables_Handler,
+		},
+		{
+			MethodName: "ParseHandsontable",
+			Handler:    _TableRenderer_ParseHandsontable_Handler,
+		},
+		{
+			MethodName: "ParseMarkdown",
+			Handler:    _TableRenderer_ParseMarkdown_Handler,
+		},
+		{
+			MethodName: "ParseText",
+			Handler:    _TableRenderer_ParseText_Handler,
+		},
+		{
+			MethodName: "ParseDocx",
+			Handler:    _TableRenderer_ParseDocx_Handler,
+		},
+		{
+			MethodName: "ListDocxTables",
+			Handler:    _TableRenderer_ListDocxTables_Handler,
+		},
+		{
+			MethodName: "ParseOds",
+			Handler:    _TableRenderer_ParseOds_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _TableRenderer_Validate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Render",
+			Handler:       _TableRenderer_Render_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "renderer.proto",
+}
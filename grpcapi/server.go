@@ -0,0 +1,113 @@
+// Package grpcapi serves the renderer over grpc, for services that would rather not encode large tables as json.
+// It shares the renderer and parser with the REST api.
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime/debug"
+
+	"github.com/ONSdigital/dp-table-renderer/config"
+	"github.com/ONSdigital/dp-table-renderer/grpcapi/rendererpb"
+	"github.com/ONSdigital/log.go/v2/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+var (
+	grpcServer   *grpc.Server
+	healthServer *health.Server
+)
+
+// CreateGRPCServer starts serving the grpc api on bindAddr. Any error that stops the server is sent to errorChan.
+func CreateGRPCServer(ctx context.Context, bindAddr string, errorChan chan error) {
+	cfg, err := config.Get()
+	if err != nil {
+		log.Error(ctx, "error occurred when getting config", err)
+		return
+	}
+
+	listener, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		log.Error(ctx, "error occurred when listening for grpc requests", err, log.Data{"bind_addr": bindAddr})
+		errorChan <- err
+		return
+	}
+
+	grpcServer, healthServer = NewServer(cfg)
+
+	go func() {
+		log.Info(ctx, "starting table renderer grpc server", log.Data{"bind_addr": bindAddr})
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Error(ctx, "error occurred when running the grpc server", err)
+			errorChan <- err
+		}
+	}()
+}
+
+// NewServer creates a grpc server with the renderer, health and reflection services registered.
+// The health server reports the renderer as serving until it is shut down.
+func NewServer(cfg *config.Config) (*grpc.Server, *health.Server) {
+	var options []grpc.ServerOption
+	if cfg.MaxBodyBytes > 0 {
+		options = append(options, grpc.MaxRecvMsgSize(int(cfg.MaxBodyBytes)))
+	}
+	options = append(options, grpc.ChainUnaryInterceptor(unaryRecovery), grpc.ChainStreamInterceptor(streamRecovery))
+	server := grpc.NewServer(options...)
+
+	rendererpb.RegisterTableRendererServer(server, &rendererServer{limits: cfg.RequestLimits()})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(rendererpb.TableRenderer_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server, healthServer
+}
+
+// unaryRecovery returns an internal error for a request whose handler panics, rather than letting the panic stop the service
+func unaryRecovery(ctx context.Context, request interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+	defer recoverPanic(ctx, &err)
+	return handler(ctx, request)
+}
+
+// streamRecovery returns an internal error for a stream whose handler panics, rather than letting the panic stop the service
+func streamRecovery(server interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer recoverPanic(stream.Context(), &err)
+	return handler(server, stream)
+}
+
+// recoverPanic is deferred by a handler to convert a panic into a codes.Internal status error
+func recoverPanic(ctx context.Context, err *error) {
+	if r := recover(); r != nil {
+		log.Error(ctx, "recovered from a panic in a grpc handler", fmt.Errorf("%v", r), log.Data{"stack": string(debug.Stack())})
+		*err = statusError(ctx, fmt.Errorf("panic: %v", r))
+	}
+}
+
+// Close reports the server as not serving, then stops it once the requests in progress have completed, or the context is done
+func Close(ctx context.Context) error {
+	if grpcServer == nil {
+		return nil
+	}
+	healthServer.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+		return ctx.Err()
+	}
+
+	log.Info(ctx, "graceful shutdown of grpc server complete")
+	return nil
+}
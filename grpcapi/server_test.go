@@ -0,0 +1,415 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/config"
+	"github.com/ONSdigital/dp-table-renderer/grpcapi/rendererpb"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/testdata"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var table = &rendererpb.RenderRequest{
+	Filename:    "table1",
+	Title:       "A table",
+	Data:        []*rendererpb.Row{{Cells: []string{"a", "b"}}, {Cells: []string{"1", "2"}}},
+	CellFormats: []*rendererpb.CellFormat{{Row: 0, Col: 0, Colspan: 2}},
+}
+
+// newConnection starts a server with the given config on an in-memory listener, returning a connection to it
+func newConnection(t *testing.T, cfg *config.Config) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	server, _ := NewServer(cfg)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receiveAll reads every chunk of a rendered table, returning the content type and the data
+func receiveAll(stream rendererpb.TableRenderer_RenderClient) (string, []byte, error) {
+	var contentType string
+	var data bytes.Buffer
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return contentType, data.Bytes(), nil
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		if len(chunk.ContentType) > 0 {
+			contentType = chunk.ContentType
+		}
+		data.Write(chunk.Data)
+	}
+}
+
+// errorReason returns the error code reported in the ErrorInfo of a status error
+func errorReason(err error) *errdetails.ErrorInfo {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
+
+func TestRender(t *testing.T) {
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Given a connection to the grpc server", t, func() {
+		client := rendererpb.NewTableRendererClient(newConnection(t, cfg))
+		ctx := context.Background()
+
+		Convey("A table is rendered in the requested format", func() {
+			stream, err := client.Render(ctx, &rendererpb.RenderTableRequest{Format: "csv", Table: table})
+			So(err, ShouldBeNil)
+			contentType, data, err := receiveAll(stream)
+			So(err, ShouldBeNil)
+			So(contentType, ShouldEqual, "text/csv")
+			So(string(data), ShouldContainSubstring, "1,2\n")
+		})
+
+		Convey("A table is drawn as a chart as chosen by its chart spec", func() {
+			category := int32(0)
+			chart := &rendererpb.RenderRequest{
+				Filename: "chart",
+				Chart:    &rendererpb.ChartSpec{Type: "line", CategoryColumn: &category, SeriesColumns: []int32{1}, Width: 300},
+				Data:     []*rendererpb.Row{{Cells: []string{"2019", "1"}}, {Cells: []string{"2020", "2"}}},
+			}
+			stream, err := client.Render(ctx, &rendererpb.RenderTableRequest{Format: "svg-chart", Table: chart})
+			So(err, ShouldBeNil)
+			contentType, data, err := receiveAll(stream)
+			So(err, ShouldBeNil)
+			So(contentType, ShouldEqual, "image/svg+xml")
+			So(string(data), ShouldContainSubstring, `width="300"`)
+			So(string(data), ShouldContainSubstring, "<title>Column 2, 2020: 2</title>")
+		})
+
+		Convey("The alt text of an image is sent with the first chunk", func() {
+			stream, err := client.Render(ctx, &rendererpb.RenderTableRequest{Format: "png", Table: table})
+			So(err, ShouldBeNil)
+			chunk, err := stream.Recv()
+			So(err, ShouldBeNil)
+			So(chunk.ContentType, ShouldEqual, "image/png")
+			So(chunk.AltText, ShouldEqual, "Table: A table. 2 rows and 2 columns.")
+		})
+
+		Convey("A large table is streamed in chunks", func() {
+			large := &rendererpb.RenderRequest{Filename: "large"}
+			for i := 0; i < 5000; i++ {
+				large.Data = append(large.Data, &rendererpb.Row{Cells: []string{strings.Repeat("x", 50)}})
+			}
+			stream, err := client.Render(ctx, &rendererpb.RenderTableRequest{Format: "html", Table: large})
+			So(err, ShouldBeNil)
+
+			chunks := 0
+			for {
+				chunk, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				So(err, ShouldBeNil)
+				So(len(chunk.Data), ShouldBeLessThanOrEqualTo, chunkSize)
+				chunks++
+			}
+			So(chunks, ShouldBeGreaterThan, 1)
+		})
+
+		Convey("An unknown format is reported as not found, with the error code", func() {
+			stream, err := client.Render(ctx, &rendererpb.RenderTableRequest{Format: "pdf", Table: table})
+			So(err, ShouldBeNil)
+			_, _, err = receiveAll(stream)
+			So(status.Code(err), ShouldEqual, codes.NotFound)
+			So(errorReason(err).Reason, ShouldEqual, models.CodeUnknownRenderType)
+		})
+	})
+
+	Convey("A table that exceeds the limits is rejected, reporting the limit", t, func() {
+		limited := *cfg
+		limited.MaxRows = 1
+		client := rendererpb.NewTableRendererClient(newConnection(t, &limited))
+
+		stream, err := client.Render(context.Background(), &rendererpb.RenderTableRequest{Format: "html", Table: table})
+		So(err, ShouldBeNil)
+		_, _, err = receiveAll(stream)
+		So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		info := errorReason(err)
+		So(info.Reason, ShouldEqual, models.CodeTableTooLarge)
+		So(info.Metadata, ShouldResemble, map[string]string{"limit": "max_rows", "max": "1"})
+	})
+}
+
+func TestParse(t *testing.T) {
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Given a connection to the grpc server", t, func() {
+		client := rendererpb.NewTableRendererClient(newConnection(t, cfg))
+
+		Convey("An html table is parsed", func() {
+			response, err := client.Parse(context.Background(), &rendererpb.ParseRequest{
+				Filename:   "table1",
+				TableHtml:  "<table><tr><th>Year</th></tr><tr><td>2020</td></tr></table>",
+				HeaderRows: 1,
+			})
+			So(err, ShouldBeNil)
+			So(response.Table.Data, ShouldHaveLength, 2)
+			So(response.Table.Data[1].Cells, ShouldResemble, []string{"2020"})
+			So(response.Table.RowFormats[0].Heading, ShouldBeTrue)
+			So(response.PreviewHtml, ShouldContainSubstring, "<table")
+			So(response.CellLayout, ShouldEqual, models.CellLayoutStandard)
+		})
+
+		Convey("The requested cell layout is used", func() {
+			response, err := client.Parse(context.Background(), &rendererpb.ParseRequest{
+				Filename:   "table1",
+				TableHtml:  "<table><tr><td rowspan=\"2\">a</td><td>b</td></tr><tr><td>c</td></tr></table>",
+				CellLayout: models.CellLayoutHandsontable,
+			})
+			So(err, ShouldBeNil)
+			So(response.CellLayout, ShouldEqual, models.CellLayoutHandsontable)
+			So(response.Table.Data[1].Cells, ShouldResemble, []string{"c", ""})
+		})
+
+		Convey("The structure of the table is inferred", func() {
+			response, err := client.Parse(context.Background(), &rendererpb.ParseRequest{
+				Filename:       "table1",
+				TableHtml:      "<table><caption>Years</caption><thead><tr><th>Year</th></tr></thead><tfoot><tr><td>All</td></tr></tfoot><tbody><tr><td>2020</td></tr></tbody></table>",
+				InferStructure: true,
+			})
+			So(err, ShouldBeNil)
+			So(response.Table.Title, ShouldEqual, "Years")
+			So(response.Inferred.HeaderRows, ShouldEqual, 1)
+			So(response.Inferred.TotalRows, ShouldResemble, []int32{1})
+			So(response.Inferred.Title, ShouldEqual, "Years")
+		})
+
+		Convey("Markers of footnotes are converted, and unused footnotes reported", func() {
+			response, err := client.Parse(context.Background(), &rendererpb.ParseRequest{
+				Filename:  "table1",
+				TableHtml: "<table><tr><td>Wales*</td></tr></table>",
+				Footnotes: []string{"Estimated", "Unused"},
+			})
+			So(err, ShouldBeNil)
+			So(response.Table.Data[0].Cells, ShouldResemble, []string{"Wales[1]"})
+			So(response.Warnings, ShouldHaveLength, 1)
+			So(response.Warnings[0].Code, ShouldEqual, models.WarningUnusedFootnote)
+			So(response.Warnings[0].Location, ShouldEqual, "footnotes[1]")
+		})
+
+		Convey("A table is chosen from a page", func() {
+			response, err := client.Parse(context.Background(), &rendererpb.ParseRequest{
+				Filename:      "table1",
+				TableHtml:     "<table><tr><td>a</td></tr></table><h2>Years</h2><table class=\"data\"><tr><td>2020</td></tr></table>",
+				TableSelector: "table.data",
+			})
+			So(err, ShouldBeNil)
+			So(response.Table.Title, ShouldEqual, "Years")
+			So(response.Table.Data[0].Cells, ShouldResemble, []string{"2020"})
+
+			_, err = client.Parse(context.Background(), &rendererpb.ParseRequest{
+				Filename:      "table1",
+				TableHtml:     "<table><tr><td>a</td></tr></table>",
+				TableSelector: "#missing",
+			})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			So(errorReason(err).Reason, ShouldEqual, models.CodeTableNotFound)
+		})
+
+		Convey("The tables in a page are listed", func() {
+			response, err := client.ListTables(context.Background(), &rendererpb.ParseRequest{
+				Filename:  "table1",
+				TableHtml: "<table id=\"one\"><caption>One</caption><tr><td>a</td><td>b</td></tr></table>",
+			})
+			So(err, ShouldBeNil)
+			So(response.Tables, ShouldHaveLength, 1)
+			So(response.Tables[0].Id, ShouldEqual, "one")
+			So(response.Tables[0].Title, ShouldEqual, "One")
+			So(response.Tables[0].Columns, ShouldEqual, 2)
+		})
+
+		Convey("The state of a Handsontable editor is parsed", func() {
+			response, err := client.ParseHandsontable(context.Background(), &rendererpb.ParseRequest{
+				Filename: "table1",
+				Handsontable: &rendererpb.HandsontableState{
+					Data:       []*rendererpb.Row{{Cells: []string{"Sales", ""}}, {Cells: []string{"10", "11"}}},
+					MergeCells: []*rendererpb.HandsontableMerge{{Row: 0, Col: 0, Rowspan: 1, Colspan: 2}},
+					Cell:       []*rendererpb.HandsontableCell{{Row: 1, Col: 1, ClassName: "htRight"}},
+					ColWidths:  []float64{80},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(response.Table.Data[1].Cells, ShouldResemble, []string{"10", "11"})
+			So(response.Table.CellFormats, ShouldHaveLength, 1)
+			So(response.Table.CellFormats[0].Colspan, ShouldEqual, 2)
+			So(response.CellLayout, ShouldEqual, models.CellLayoutHandsontable)
+
+			_, err = client.ParseHandsontable(context.Background(), &rendererpb.ParseRequest{Filename: "table1"})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			So(errorReason(err).Metadata["missing_fields"], ShouldEqual, "[handsontable.data]")
+		})
+
+		Convey("Markdown and fixed-width text tables are parsed", func() {
+			response, err := client.ParseMarkdown(context.Background(), &rendererpb.ParseRequest{
+				Filename:      "table1",
+				TableMarkdown: "| Year | Count |\n|:--|--:|\n| 2020 | 5 |",
+			})
+			So(err, ShouldBeNil)
+			So(response.Table.Data[1].Cells, ShouldResemble, []string{"2020", "5"})
+			So(response.Inferred.HeaderRows, ShouldEqual, 1)
+
+			response, err = client.ParseText(context.Background(), &rendererpb.ParseRequest{
+				Filename:         "table1",
+				TableText:        "Year Count\n2020 5",
+				ColumnBoundaries: []int32{5},
+			})
+			So(err, ShouldBeNil)
+			So(response.Table.Data[1].Cells, ShouldResemble, []string{"2020", "5"})
+		})
+
+		Convey("The tables of a Word document are listed and parsed", func() {
+			request := &rendererpb.ParseRequest{Filename: "document1", Docx: testdata.LoadExampleDocx(t)}
+			tables, err := client.ListDocxTables(context.Background(), request)
+			So(err, ShouldBeNil)
+			So(tables.Tables, ShouldHaveLength, 1)
+			So(tables.Tables[0].Columns, ShouldEqual, 2)
+
+			response, err := client.ParseDocx(context.Background(), request)
+			So(err, ShouldBeNil)
+			So(response.Table.Data[0].Cells, ShouldResemble, []string{"Year", "Count"})
+
+			_, err = client.ParseDocx(context.Background(), &rendererpb.ParseRequest{Filename: "document1", Docx: []byte("not a docx")})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			So(errorReason(err).Reason, ShouldEqual, models.CodeInvalidDocument)
+		})
+
+		Convey("A range of an OpenDocument spreadsheet is parsed", func() {
+			response, err := client.ParseOds(context.Background(), &rendererpb.ParseRequest{Filename: "spreadsheet1", Ods: testdata.LoadExampleOds(t), Range: "B1:B1"})
+			So(err, ShouldBeNil)
+			So(response.Table.Data[0].Cells, ShouldResemble, []string{"Count"})
+
+			_, err = client.ParseOds(context.Background(), &rendererpb.ParseRequest{Filename: "spreadsheet1"})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			So(errorReason(err).Metadata["missing_fields"], ShouldEqual, "[ods]")
+		})
+
+		Convey("Missing fields are reported", func() {
+			_, err := client.Parse(context.Background(), &rendererpb.ParseRequest{Filename: "table1"})
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			So(errorReason(err).Metadata["missing_fields"], ShouldEqual, "[table_html]")
+		})
+	})
+}
+
+func TestValidate(t *testing.T) {
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("The size of a valid table is returned", t, func() {
+		client := rendererpb.NewTableRendererClient(newConnection(t, cfg))
+		response, err := client.Validate(context.Background(), table)
+		So(err, ShouldBeNil)
+		So(response.Rows, ShouldEqual, 2)
+		So(response.Columns, ShouldEqual, 2)
+		So(response.Cells, ShouldEqual, 4)
+		So(response.Merges, ShouldEqual, 1)
+	})
+}
+
+func TestHealthAndReflection(t *testing.T) {
+	cfg, err := config.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Given a connection to the grpc server", t, func() {
+		conn := newConnection(t, cfg)
+
+		Convey("The renderer service is reported as serving", func() {
+			response, err := healthpb.NewHealthClient(conn).Check(context.Background(),
+				&healthpb.HealthCheckRequest{Service: rendererpb.TableRenderer_ServiceDesc.ServiceName})
+			So(err, ShouldBeNil)
+			So(response.Status, ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+		})
+
+		Convey("The services can be listed by reflection", func() {
+			stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+			So(err, ShouldBeNil)
+			So(stream.Send(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+			}), ShouldBeNil)
+			response, err := stream.Recv()
+			So(err, ShouldBeNil)
+			var services []string
+			for _, service := range response.GetListServicesResponse().GetService() {
+				services = append(services, service.Name)
+			}
+			So(services, ShouldContain, "dp.tablerenderer.v1.TableRenderer")
+			So(services, ShouldContain, "grpc.health.v1.Health")
+		})
+	})
+}
+
+func TestStatusError(t *testing.T) {
+	Convey("The details of an internal error are not reported", t, func() {
+		err := statusError(context.Background(), errors.New("secret"))
+		So(status.Code(err), ShouldEqual, codes.Internal)
+		So(status.Convert(err).Message(), ShouldEqual, "Internal server error")
+		So(errorReason(err).Reason, ShouldEqual, models.CodeInternal)
+	})
+}
+
+// contextStream is a server stream that provides only its context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestRecovery(t *testing.T) {
+	Convey("A handler that panics returns an internal error, rather than stopping the service", t, func() {
+		panics := func(context.Context, interface{}) (interface{}, error) { panic("index out of range") }
+		response, err := unaryRecovery(context.Background(), nil, &grpc.UnaryServerInfo{}, panics)
+		So(response, ShouldBeNil)
+		So(status.Code(err), ShouldEqual, codes.Internal)
+		So(errorReason(err).Reason, ShouldEqual, models.CodeInternal)
+
+		streamPanics := func(interface{}, grpc.ServerStream) error { panic("index out of range") }
+		err = streamRecovery(nil, contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, streamPanics)
+		So(status.Code(err), ShouldEqual, codes.Internal)
+	})
+}
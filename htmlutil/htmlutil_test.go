@@ -1,6 +1,7 @@
 package htmlutil_test
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/ONSdigital/dp-table-renderer/htmlutil"
@@ -325,3 +326,67 @@ func TestGetText(t *testing.T) {
 		So(result, ShouldEqual, "this paragraph includes <a href=\"http://www.ons.gov.uk\">a link</a> and more text")
 	})
 }
+
+func TestInlineMarkup(t *testing.T) {
+	Convey("InlineMarkup should keep the allowed elements and the text of others", t, func() {
+		cell := parseCell(`<span class="x"><b>Total</b> <i>est.</i> <strong id="s">a</strong><em>b</em> x<sub>2</sub>` +
+			` <a href="http://www.ons.gov.uk" class="link">link</a> <a href="javascript:alert(1)">js</a><script>x</script></span>`)
+		So(InlineMarkup(cell, 0), ShouldEqual, `<strong>Total</strong> <em>est.</em> <strong>a</strong><em>b</em> x<sub>2</sub>`+
+			` <a href="http://www.ons.gov.uk">link</a> js`)
+	})
+
+	Convey("InlineMarkup should convert line breaks and paragraphs to new lines", t, func() {
+		So(InlineMarkup(parseCell("line 1<br>line 2<br/><br>line 4"), 0), ShouldEqual, "line 1\nline 2\n\nline 4")
+		So(InlineMarkup(parseCell("<p>para 1</p>\n  <p>para 2</p><div>para 3<br></div>"), 0), ShouldEqual, "para 1\npara 2\npara 3")
+	})
+
+	Convey("InlineMarkup should normalise whitespace and non-breaking spaces", t, func() {
+		So(InlineMarkup(parseCell("\n  1,200&nbsp;000\t\n  people  "), 0), ShouldEqual, "1,200 000 people")
+		So(InlineMarkup(parseCell("a <br> b"), 0), ShouldEqual, "a\nb")
+	})
+
+	Convey("InlineMarkup should escape the text", t, func() {
+		So(InlineMarkup(parseCell("R&amp;D &lt; 5 &quot;x&quot;"), 0), ShouldEqual, `R&amp;D &lt; 5 "x"`)
+	})
+
+	Convey("InlineMarkup should convert references to footnotes", t, func() {
+		So(InlineMarkup(parseCell(`Wales<sup>1</sup>`), 2), ShouldEqual, "Wales[1]")
+		So(InlineMarkup(parseCell(`Wales<sup>1</sup>`), 0), ShouldEqual, "Wales<sup>1</sup>")
+		So(InlineMarkup(parseCell(`km<sup>2</sup>`), 1), ShouldEqual, "km<sup>2</sup>")
+		So(InlineMarkup(parseCell(`Wales<sup>[3]</sup>`), 0), ShouldEqual, "Wales[3]")
+		So(InlineMarkup(parseCell(`Wales<sup><a href="#fn4">4</a></sup>`), 0), ShouldEqual, "Wales[4]")
+		So(InlineMarkup(parseCell(`Wales<a href="#note-5" class="footnote__link"><span class="visuallyhidden">Footnote </span>5</a>`), 0), ShouldEqual, "Wales[5]")
+		So(InlineMarkup(parseCell(`<a href="#top">6 pages</a>`), 0), ShouldEqual, `<a href="#top">6 pages</a>`)
+	})
+}
+
+func TestSanitiseInline(t *testing.T) {
+	Convey("SanitiseInline should keep the allowed elements and the text of others, leaving whitespace alone", t, func() {
+		value, err := SanitiseInline(`<div onclick="x()">a  <b>b</b></div><img src="x.png"><sup>1</sup>[2]` + "\n<script>alert(1)</script>")
+		So(err, ShouldBeNil)
+		So(value, ShouldEqual, "a  <strong>b</strong>\n<sup>1</sup>[2]\n")
+	})
+}
+
+func TestInlineText(t *testing.T) {
+	Convey("InlineText should remove the markup, keeping line breaks and references to footnotes", t, func() {
+		So(InlineText(`<strong>R&amp;D</strong> <a href="http://www.ons.gov.uk">link</a>[1]<br>line 2`), ShouldEqual, "R&D link[1]\nline 2")
+		So(InlineText("1,200"), ShouldEqual, "1,200")
+	})
+}
+
+func TestPlainText(t *testing.T) {
+	Convey("PlainText should remove only the tags of the canonical inline markup, keeping any other < or > as text", t, func() {
+		So(PlainText(`<strong>R&amp;D</strong> <a href="http://www.ons.gov.uk?a=1&amp;b=2">link</a><sup>[1]</sup><br>line 2<br/>`), ShouldEqual, "R&D link[1]\nline 2\n")
+		So(PlainText("<LOD"), ShouldEqual, "<LOD")
+		So(PlainText("a<b and c>d"), ShouldEqual, "a<b and c>d")
+		So(PlainText("<em>&lt;5</em> <a title=\"x\">y</a> <div>z</div>"), ShouldEqual, "<5 <a title=\"x\">y <div>z</div>")
+	})
+}
+
+// parseCell returns the td parsed from the html of its content
+func parseCell(content string) *html.Node {
+	nodes, err := html.ParseFragment(strings.NewReader("<td>"+content+"</td>"), &html.Node{Type: html.ElementNode, Data: "tr", DataAtom: atom.Tr})
+	So(err, ShouldBeNil)
+	return nodes[0]
+}
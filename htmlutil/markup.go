@@ -0,0 +1,222 @@
+package htmlutil
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// InlineElements are the elements allowed in the canonical markup of a cell value. A line break is written as \n and a
+// reference to a footnote as [n], where n is the number of the footnote. Links keep only their href attribute.
+var InlineElements = map[atom.Atom]bool{
+	atom.A:      true,
+	atom.Strong: true,
+	atom.Em:     true,
+	atom.Sup:    true,
+	atom.Sub:    true,
+}
+
+var (
+	// inlineAliases maps presentational elements to the allowed element with the same meaning
+	inlineAliases = map[atom.Atom]atom.Atom{atom.B: atom.Strong, atom.I: atom.Em}
+	// blockElements start and end a line of the value
+	blockElements = map[atom.Atom]bool{
+		atom.P: true, atom.Div: true, atom.Li: true, atom.Ul: true, atom.Ol: true, atom.Blockquote: true, atom.Pre: true,
+		atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	}
+	// hiddenElements are dropped together with their content
+	hiddenElements = map[atom.Atom]bool{atom.Script: true, atom.Style: true, atom.Template: true, atom.Noscript: true}
+	// linkSchemes are the schemes allowed in the href of a link, as well as relative links
+	linkSchemes = map[string]bool{"": true, "http": true, "https": true, "mailto": true}
+
+	textEscaper          = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	whitespacePattern    = regexp.MustCompile(`[\s\x{00a0}\x{2007}\x{202f}]+`)
+	spacesPattern        = regexp.MustCompile(` {2,}`)
+	lineSpacePattern     = regexp.MustCompile(` *\n *`)
+	paragraphPattern     = regexp.MustCompile(paragraphRun)
+	edgeParagraphPattern = regexp.MustCompile(`^` + paragraphRun + `|` + paragraphRun + `$`)
+	superscriptPattern   = regexp.MustCompile(`^(\[|\()?([0-9]+)[\])]?$`)
+	footnoteLinkPattern  = regexp.MustCompile(`(?i)^(?:(?:foot)?note\s*)?[\[(]?([0-9]+)[\])]?$`)
+	// inlineTagPattern matches a tag of the canonical inline markup: a link with only an href, the start or end of one
+	// of the other InlineElements, or a line break
+	inlineTagPattern = regexp.MustCompile(`(?i)<(?:a\s+href\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'<>]+)\s*|/?(?:a|strong|em|sup|sub)\s*|br\s*/?)>`)
+)
+
+// paragraphBreak marks the start or end of a paragraph, until the breaks between paragraphs are merged into a single \n
+const paragraphBreak = "\u2029"
+
+// paragraphRun matches a run of spaces and line breaks that includes a paragraph break
+const paragraphRun = `[ \n` + paragraphBreak + `]*` + paragraphBreak + `[ \n` + paragraphBreak + `]*`
+
+// inlineWriter converts html to the canonical inline markup
+type inlineWriter struct {
+	normalise bool // true to collapse whitespace and convert references to footnotes to [n]
+	footnotes int  // the number of footnotes: a superscript number no greater than this is a reference to a footnote
+}
+
+// InlineMarkup returns the content of the node in the canonical inline markup of a cell value: <br> and the boundaries
+// of paragraphs become \n, the InlineElements are kept (with <b> and <i> as <strong> and <em>), other elements are
+// replaced by their text, and runs of whitespace and non-breaking spaces become a single space.
+// References to footnotes become [n]: a link within the page or a superscript whose text is a number in brackets, a
+// superscript containing such a link, or a superscript number no greater than the number of footnotes.
+func InlineMarkup(n *html.Node, footnotes int) string {
+	w := &inlineWriter{normalise: true, footnotes: footnotes}
+	value := spacesPattern.ReplaceAllLiteralString(mergeParagraphs(w.children(n)), " ")
+	return strings.Trim(lineSpacePattern.ReplaceAllLiteralString(value, "\n"), " \n")
+}
+
+// SanitiseInline reduces an html fragment to the canonical inline markup, keeping the text of any other elements.
+// Unlike InlineMarkup, whitespace and references to footnotes are left as they are.
+func SanitiseInline(value string) (string, error) {
+	nodes, err := ParseInline(value)
+	if err != nil {
+		return "", err
+	}
+	w := &inlineWriter{}
+	var b strings.Builder
+	for _, node := range nodes {
+		b.WriteString(w.node(node))
+	}
+	return mergeParagraphs(b.String()), nil
+}
+
+// InlineText returns the text of a value in the canonical inline markup, without any markup. Line breaks are kept as
+// \n, and references to footnotes as [n].
+func InlineText(value string) string {
+	nodes, err := ParseInline(value)
+	if err != nil {
+		return value
+	}
+	var b strings.Builder
+	for _, node := range nodes {
+		writeInlineText(&b, node)
+	}
+	return mergeParagraphs(b.String())
+}
+
+// PlainText returns the text of a value in the canonical inline markup, removing only the tags of the InlineElements and
+// <br>, which becomes \n, and unescaping character references. Any other < or >, as in a value such as <5 or a<b, is text.
+func PlainText(value string) string {
+	value = inlineTagPattern.ReplaceAllStringFunc(value, func(tag string) string {
+		if strings.HasPrefix(strings.ToLower(tag), "<br") {
+			return "\n"
+		}
+		return ""
+	})
+	return html.UnescapeString(value)
+}
+
+// ParseInline parses an html fragment as the content of a body element
+func ParseInline(value string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(value), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+}
+
+// children returns the markup of the content of the node
+func (w *inlineWriter) children(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(w.node(c))
+	}
+	return b.String()
+}
+
+// node returns the markup of the node and its content
+func (w *inlineWriter) node(n *html.Node) string {
+	switch {
+	case n.Type == html.TextNode && w.normalise:
+		return textEscaper.Replace(whitespacePattern.ReplaceAllLiteralString(n.Data, " "))
+	case n.Type == html.TextNode:
+		return textEscaper.Replace(n.Data)
+	case n.Type != html.ElementNode || hiddenElements[n.DataAtom]:
+		return ""
+	case n.DataAtom == atom.Br:
+		return "\n"
+	case blockElements[n.DataAtom]:
+		return paragraphBreak + w.children(n) + paragraphBreak
+	}
+
+	if number, ok := w.footnoteReference(n); ok {
+		return "[" + number + "]"
+	}
+	content := w.children(n)
+	element := n.DataAtom
+	if alias, ok := inlineAliases[element]; ok {
+		element = alias
+	}
+	if !InlineElements[element] || len(content) == 0 {
+		return content
+	}
+	start := "<" + element.String() + ">"
+	if element == atom.A {
+		href := strings.TrimSpace(GetAttribute(n, "href"))
+		if link, err := url.Parse(href); len(href) == 0 || err != nil || !linkSchemes[strings.ToLower(link.Scheme)] {
+			return content
+		}
+		start = `<a href="` + html.EscapeString(href) + `">`
+	}
+	return start + content + "</" + element.String() + ">"
+}
+
+// footnoteReference returns the number of the footnote if the node is a reference to a footnote
+func (w *inlineWriter) footnoteReference(n *html.Node) (string, bool) {
+	if !w.normalise {
+		return "", false
+	}
+	text := strings.TrimSpace(whitespacePattern.ReplaceAllLiteralString(InlineText(w.children(n)), " "))
+	switch n.DataAtom {
+	case atom.A:
+		match := footnoteLinkPattern.FindStringSubmatch(text)
+		if match != nil && strings.HasPrefix(GetAttribute(n, "href"), "#") {
+			return match[1], true
+		}
+	case atom.Sup:
+		match := superscriptPattern.FindStringSubmatch(text)
+		if match == nil {
+			return "", false
+		}
+		number, _ := strconv.Atoi(match[2])
+		if len(match[1]) > 0 || FindNode(n, atom.A) != nil || (number > 0 && number <= w.footnotes) {
+			return match[2], true
+		}
+	}
+	return "", false
+}
+
+// writeInlineText writes the text of the node and its content, with \n for each <br> and around each paragraph
+func writeInlineText(b *strings.Builder, n *html.Node) {
+	switch {
+	case n.Type == html.TextNode:
+		b.WriteString(n.Data)
+		return
+	case n.Type != html.ElementNode || hiddenElements[n.DataAtom]:
+		return
+	case n.DataAtom == atom.Br:
+		b.WriteString("\n")
+		return
+	}
+	block := blockElements[n.DataAtom]
+	if block {
+		b.WriteString(paragraphBreak)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeInlineText(b, c)
+	}
+	if block {
+		b.WriteString(paragraphBreak)
+	}
+}
+
+// mergeParagraphs replaces the breaks between paragraphs, and any spaces and line breaks next to them, with a single
+// \n, and removes those at the start and end of the value
+func mergeParagraphs(value string) string {
+	value = edgeParagraphPattern.ReplaceAllLiteralString(value, "")
+	return paragraphPattern.ReplaceAllLiteralString(value, "\n")
+}
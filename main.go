@@ -12,6 +12,8 @@ import (
 	dpotelgo "github.com/ONSdigital/dp-otel-go"
 	"github.com/ONSdigital/dp-table-renderer/api"
 	"github.com/ONSdigital/dp-table-renderer/config"
+	"github.com/ONSdigital/dp-table-renderer/grpcapi"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
@@ -61,12 +63,18 @@ func run(ctx context.Context) error {
 		defer func() {
 			err = errors.Join(err, otelShutdown(context.Background()))
 		}()
-
-		otelShutdown(ctx)
 	}
 
 	log.Info(ctx, "got service configuration", log.Data{"config": cfg})
 
+	if len(cfg.ThemesDir) > 0 {
+		if err = renderer.LoadThemes(cfg.ThemesDir); err != nil {
+			log.Fatal(ctx, "unable to load themes", err, log.Data{"themes_dir": cfg.ThemesDir})
+			return err
+		}
+		log.Info(ctx, "loaded themes", log.Data{"themes": renderer.ThemeNames()})
+	}
+
 	// Create healthcheck
 	versionInfo, err := healthcheck.NewVersionInfo(BuildTime, GitCommit, Version)
 	if err != nil {
@@ -78,6 +86,9 @@ func run(ctx context.Context) error {
 	apiErrors := make(chan error, 1)
 
 	api.CreateRendererAPI(ctx, cfg.BindAddr, cfg.CORSAllowedOrigins, apiErrors, &healthCheck)
+	if cfg.GRPCEnabled {
+		grpcapi.CreateGRPCServer(ctx, cfg.GRPCBindAddr, apiErrors)
+	}
 
 	// Gracefully shutdown the application closing any open resources.
 	gracefulShutdown := func() error {
@@ -91,6 +102,11 @@ func run(ctx context.Context) error {
 			return err
 		}
 
+		if err = grpcapi.Close(ctx); err != nil {
+			log.Error(ctx, "error with graceful shutdown of grpc server", err)
+			return err
+		}
+
 		log.Info(ctx, "Shutdown complete")
 		return nil
 	}
@@ -0,0 +1,209 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ONSdigital/dp-table-renderer/cache"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "table_renderer"
+
+// Operations that are instrumented
+const (
+	OperationRender = "render"
+	OperationParse  = "parse"
+)
+
+// Metrics holds the prometheus collectors that record the work done by the service.
+// All methods are safe to call on a nil *Metrics, in which case nothing is recorded.
+type Metrics struct {
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	inFlight      *prometheus.GaugeVec
+	outputBytes   *prometheus.HistogramVec
+	tableRows     *prometheus.HistogramVec
+	tableColumns  *prometheus.HistogramVec
+	tableCells    *prometheus.HistogramVec
+	tableMerges   *prometheus.HistogramVec
+	parseOutcomes *prometheus.CounterVec
+	rejections    *prometheus.CounterVec
+	registerer    prometheus.Registerer
+}
+
+// New creates the collectors and registers them with the registerer
+func New(registerer prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "The number of requests handled, by operation, type and http status.",
+		}, []string{"operation", "type", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "The time taken to handle a request, by operation, type and http status.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2.5, 10),
+		}, []string{"operation", "type", "status"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "in_flight_requests",
+			Help:      "The number of requests currently being handled, by operation.",
+		}, []string{"operation"}),
+		outputBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "output_bytes",
+			Help:      "The size of successful responses, by operation and type.",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 10),
+		}, []string{"operation", "type"}),
+		tableRows: tableHistogram("table_rows", "The number of rows in rendered tables, by render type.",
+			prometheus.ExponentialBuckets(1, 4, 10)),
+		tableColumns: tableHistogram("table_columns", "The number of columns in rendered tables, by render type.",
+			prometheus.ExponentialBuckets(1, 2, 10)),
+		tableCells: tableHistogram("table_cells", "The number of cells in rendered tables, by render type.",
+			prometheus.ExponentialBuckets(1, 4, 12)),
+		tableMerges: tableHistogram("table_merges", "The number of merged cells in rendered tables, by render type.",
+			prometheus.ExponentialBuckets(1, 4, 8)),
+		parseOutcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "parse_outcomes_total",
+			Help:      "The outcome of parse requests - 'success' or the error code - by parse type.",
+		}, []string{"type", "outcome"}),
+		rejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rejections_total",
+			Help:      "The number of requests rejected as invalid, by operation and error code.",
+		}, []string{"operation", "code"}),
+		registerer: registerer,
+	}
+	registerer.MustRegister(m.requests, m.duration, m.inFlight, m.outputBytes, m.tableRows, m.tableColumns,
+		m.tableCells, m.tableMerges, m.parseOutcomes, m.rejections)
+	return m
+}
+
+// tableHistogram creates a histogram of a dimension of a rendered table
+func tableHistogram(name string, help string, buckets []float64) *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+		Buckets:   buckets,
+	}, []string{"type"})
+}
+
+// RegisterCache adds metrics that report the state of the render cache
+func (m *Metrics) RegisterCache(c cache.Cache) {
+	if m == nil || c == nil {
+		return
+	}
+	opts := func(name string, help string) prometheus.Opts {
+		return prometheus.Opts{Namespace: namespace, Subsystem: "render_cache", Name: name, Help: help}
+	}
+	m.registerer.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts(opts("hits_total", "The number of renders served from the cache.")),
+			func() float64 { return float64(c.Stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts(opts("misses_total", "The number of renders that were not in the cache.")),
+			func() float64 { return float64(c.Stats().Misses) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts(opts("evictions_total", "The number of tables removed from the cache to make space.")),
+			func() float64 { return float64(c.Stats().Evictions) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts(opts("entries", "The number of tables in the cache.")),
+			func() float64 { return float64(c.Stats().Entries) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts(opts("bytes", "The current size of the cache in bytes.")),
+			func() float64 { return float64(c.Stats().Bytes) }),
+	)
+}
+
+// ObserveTable records the dimensions of a table that is about to be rendered
+func (m *Metrics) ObserveTable(renderType string, request *models.RenderRequest) {
+	if m == nil {
+		return
+	}
+	size := request.Size()
+	m.tableRows.WithLabelValues(renderType).Observe(float64(size.Rows))
+	m.tableColumns.WithLabelValues(renderType).Observe(float64(size.Columns))
+	m.tableCells.WithLabelValues(renderType).Observe(float64(size.Cells))
+	m.tableMerges.WithLabelValues(renderType).Observe(float64(size.Merges))
+}
+
+// Instrument wraps a handler, recording the number, duration, outcome and size of the responses it produces.
+// typeLabel returns the render or parse type of a request, and must only return a small, fixed set of values.
+func (m *Metrics) Instrument(operation string, typeLabel func(*http.Request) string, next http.HandlerFunc) http.HandlerFunc {
+	if m == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		inFlight := m.inFlight.WithLabelValues(operation)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		recorder := &responseRecorder{ResponseWriter: w}
+		next(recorder, r)
+
+		tableType := typeLabel(r)
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		statusLabel := strconv.Itoa(status)
+		m.requests.WithLabelValues(operation, tableType, statusLabel).Inc()
+		m.duration.WithLabelValues(operation, tableType, statusLabel).Observe(time.Since(start).Seconds())
+
+		if status == http.StatusOK {
+			m.outputBytes.WithLabelValues(operation, tableType).Observe(float64(recorder.bytes))
+		}
+		if status >= 400 && status < 500 {
+			m.rejections.WithLabelValues(operation, recorder.code).Inc()
+		}
+		if operation == OperationParse {
+			outcome := "success"
+			if status >= 400 {
+				outcome = recorder.code
+			}
+			m.parseOutcomes.WithLabelValues(tableType, outcome).Inc()
+		}
+	}
+}
+
+// ErrorCodeRecorder is implemented by the http.ResponseWriter passed to instrumented handlers,
+// so that the code of an error response can be recorded
+type ErrorCodeRecorder interface {
+	RecordErrorCode(code string)
+}
+
+// responseRecorder captures the status, size and error code of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+	code   string
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += n
+	return n, err
+}
+
+// RecordErrorCode records the code of an error response
+func (r *responseRecorder) RecordErrorCode(code string) {
+	r.code = code
+}
+
+// Unwrap allows http.ResponseController to reach the underlying ResponseWriter
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,154 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/cache"
+	"github.com/ONSdigital/dp-table-renderer/metrics"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func typeLabel(*http.Request) string {
+	return "html"
+}
+
+func TestInstrument(t *testing.T) {
+	Convey("Given an instrumented handler", t, func() {
+		registry := prometheus.NewRegistry()
+		m := metrics.New(registry)
+
+		handle := func(operation string, handler http.HandlerFunc) {
+			w := httptest.NewRecorder()
+			m.Instrument(operation, typeLabel, handler)(w, httptest.NewRequest("POST", "/", nil))
+		}
+
+		Convey("A successful response is counted and its size recorded", func() {
+			handle(metrics.OperationRender, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("<table></table>"))
+			})
+
+			expected := `
+# HELP table_renderer_requests_total The number of requests handled, by operation, type and http status.
+# TYPE table_renderer_requests_total counter
+table_renderer_requests_total{operation="render",status="200",type="html"} 1
+`
+			So(testutil.GatherAndCompare(registry, strings.NewReader(expected), "table_renderer_requests_total"), ShouldBeNil)
+			So(testutil.CollectAndCount(registry, "table_renderer_output_bytes"), ShouldEqual, 1)
+			So(testutil.CollectAndCount(registry, "table_renderer_rejections_total"), ShouldEqual, 0)
+			So(testutil.CollectAndCount(registry, "table_renderer_parse_outcomes_total"), ShouldEqual, 0)
+		})
+
+		Convey("A rejected request is counted with its error code", func() {
+			handle(metrics.OperationParse, func(w http.ResponseWriter, r *http.Request) {
+				w.(metrics.ErrorCodeRecorder).RecordErrorCode(models.CodeInvalidTableHTML)
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			})
+
+			expected := `
+# HELP table_renderer_parse_outcomes_total The outcome of parse requests - 'success' or the error code - by parse type.
+# TYPE table_renderer_parse_outcomes_total counter
+table_renderer_parse_outcomes_total{outcome="invalid_table_html",type="html"} 1
+# HELP table_renderer_rejections_total The number of requests rejected as invalid, by operation and error code.
+# TYPE table_renderer_rejections_total counter
+table_renderer_rejections_total{code="invalid_table_html",operation="parse"} 1
+`
+			So(testutil.GatherAndCompare(registry, strings.NewReader(expected),
+				"table_renderer_parse_outcomes_total", "table_renderer_rejections_total"), ShouldBeNil)
+			So(testutil.CollectAndCount(registry, "table_renderer_output_bytes"), ShouldEqual, 0)
+		})
+
+		Convey("Requests are counted as in flight while they are being handled", func() {
+			var inFlight float64
+			handle(metrics.OperationRender, func(w http.ResponseWriter, r *http.Request) {
+				inFlight, _ = gaugeValue(registry, "table_renderer_in_flight_requests")
+			})
+			So(inFlight, ShouldEqual, 1)
+			after, _ := gaugeValue(registry, "table_renderer_in_flight_requests")
+			So(after, ShouldEqual, 0)
+		})
+	})
+
+	Convey("A nil Metrics returns the handler unchanged and records nothing", t, func() {
+		var m *metrics.Metrics
+		called := false
+		handler := m.Instrument(metrics.OperationRender, typeLabel, func(w http.ResponseWriter, r *http.Request) { called = true })
+		handler(httptest.NewRecorder(), httptest.NewRequest("POST", "/", nil))
+		So(called, ShouldBeTrue)
+		m.ObserveTable("html", &models.RenderRequest{})
+		m.RegisterCache(cache.NewLRU(10))
+	})
+}
+
+func TestObserveTable(t *testing.T) {
+	Convey("The dimensions of a table are recorded", t, func() {
+		registry := prometheus.NewRegistry()
+		m := metrics.New(registry)
+
+		m.ObserveTable("csv", &models.RenderRequest{
+			Data:        [][]string{{"a", "b", "c"}, {"d", "e"}},
+			CellFormats: []models.CellFormat{{Row: 0, Column: 0, Colspan: 2}, {Row: 1, Column: 0, Align: models.AlignLeft}},
+		})
+
+		families, err := registry.Gather()
+		So(err, ShouldBeNil)
+		sums := make(map[string]float64)
+		for _, family := range families {
+			for _, metric := range family.GetMetric() {
+				if metric.GetHistogram() != nil {
+					sums[family.GetName()] = metric.GetHistogram().GetSampleSum()
+				}
+			}
+		}
+		So(sums["table_renderer_table_rows"], ShouldEqual, 2)
+		So(sums["table_renderer_table_columns"], ShouldEqual, 3)
+		So(sums["table_renderer_table_cells"], ShouldEqual, 5)
+		So(sums["table_renderer_table_merges"], ShouldEqual, 1)
+	})
+}
+
+func TestRegisterCache(t *testing.T) {
+	Convey("The state of the render cache is reported", t, func() {
+		registry := prometheus.NewRegistry()
+		m := metrics.New(registry)
+		c := cache.NewLRU(1024)
+		m.RegisterCache(c)
+
+		c.Get("a")
+		c.Set("a", []byte("abc"))
+		c.Get("a")
+
+		expected := `
+# HELP table_renderer_render_cache_hits_total The number of renders served from the cache.
+# TYPE table_renderer_render_cache_hits_total counter
+table_renderer_render_cache_hits_total 1
+# HELP table_renderer_render_cache_misses_total The number of renders that were not in the cache.
+# TYPE table_renderer_render_cache_misses_total counter
+table_renderer_render_cache_misses_total 1
+# HELP table_renderer_render_cache_entries The number of tables in the cache.
+# TYPE table_renderer_render_cache_entries gauge
+table_renderer_render_cache_entries 1
+`
+		So(testutil.GatherAndCompare(registry, strings.NewReader(expected), "table_renderer_render_cache_hits_total",
+			"table_renderer_render_cache_misses_total", "table_renderer_render_cache_entries"), ShouldBeNil)
+	})
+}
+
+// gaugeValue returns the value of the first gauge in the named metric family
+func gaugeValue(registry *prometheus.Registry, name string) (float64, bool) {
+	families, err := registry.Gather()
+	if err != nil {
+		return 0, false
+	}
+	for _, family := range families {
+		if family.GetName() == name && len(family.GetMetric()) > 0 {
+			return family.GetMetric()[0].GetGauge().GetValue(), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,19 @@
+package models
+
+// valid values for the type of a chart
+var (
+	ChartLine       = "line"
+	ChartBar        = "bar"
+	ChartStackedBar = "stacked-bar"
+)
+
+// ChartSpec chooses how the data of a table is drawn as a chart. Each category of the chart is a row of the table, and
+// each series a column. Every field is optional.
+type ChartSpec struct {
+	Type           string `json:"type,omitempty"`            // line, bar or stacked-bar. Defaults to bar.
+	CategoryColumn *int   `json:"category_column,omitempty"` // the column that labels the categories. Defaults to the heading columns.
+	SeriesColumns  []int  `json:"series_columns,omitempty"`  // the columns drawn as series. Defaults to every column of numbers that isn't a heading.
+	Rows           []int  `json:"rows,omitempty"`            // the rows drawn as categories. Defaults to every row below the heading rows, except rows of totals.
+	Width          int    `json:"width,omitempty"`           // the width of the chart in pixels. Defaults to 640.
+	Height         int    `json:"height,omitempty"`          // the height of the chart in pixels. Defaults to 400.
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error codes are stable identifiers returned to clients so that they can react to a failure programmatically.
+// The message accompanying an error may change, the code should not.
+const (
+	CodeReadingBody          = "reading_body"
+	CodeInvalidJSON          = "invalid_json"
+	CodeMissingData          = "missing_data"
+	CodeMissingFields        = "missing_fields"
+	CodeRequestTooLarge      = "request_too_large"
+	CodeTableTooLarge        = "table_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeUnknownRenderType    = "unknown_render_type"
+	CodeUnknownTheme         = "unknown_theme"
+	CodeInvalidTableHTML     = "invalid_table_html"
+	CodeTableNotFound        = "table_not_found"
+	CodeInvalidDocument      = "invalid_document"
+	CodeInvalidCellFormat    = "invalid_cell_format"
+	CodeInvalidChart         = "invalid_chart"
+	CodeInvalidImage         = "invalid_image"
+	CodeRenderFailed         = "render_failed"
+	CodeInternal             = "internal_error"
+)
+
+// Error is an error with a stable code and optional details that can be reported to the client
+type Error struct {
+	Code    string                 // one of the Code* constants
+	Message string                 // a human readable description of the error
+	Details map[string]interface{} // additional information about the error, e.g. the fields that are missing
+	Err     error                  // the underlying cause of the error, if any. Never reported to the client.
+}
+
+// A list of errors returned from package
+var (
+	ErrorReadingBody = &Error{Code: CodeReadingBody, Message: "Failed to read message body"}
+	ErrorParsingBody = &Error{Code: CodeInvalidJSON, Message: "Failed to parse json body"}
+	ErrorNoData      = &Error{Code: CodeMissingData, Message: "Bad request - Missing data in body"}
+)
+
+// NewError creates an Error with the given code and message, wrapping the (optional) cause
+func NewError(code string, message string, cause error) *Error {
+	return &Error{Code: code, Message: message, Err: cause}
+}
+
+// NewMissingFieldsError creates an Error listing the mandatory fields that were not provided
+func NewMissingFieldsError(fields []string) *Error {
+	return &Error{
+		Code:    CodeMissingFields,
+		Message: fmt.Sprintf("Missing mandatory fields: %v", fields),
+		Details: map[string]interface{}{"missing_fields": fields},
+	}
+}
+
+// Error returns the message of the error, including the cause if there is one
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target is an Error with the same code, so that errors.Is can be used to test for a kind of error
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// ErrorCode returns the code of the first Error in the chain of err, or CodeInternal if there isn't one
+func ErrorCode(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeInternal
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestError(t *testing.T) {
+	Convey("An Error matches any other Error with the same code", t, func() {
+		err := NewError(CodeInvalidJSON, "a different message", errors.New("cause"))
+		So(errors.Is(err, ErrorParsingBody), ShouldBeTrue)
+		So(errors.Is(err, ErrorNoData), ShouldBeFalse)
+	})
+
+	Convey("The message of an Error includes its cause", t, func() {
+		err := NewError(CodeRenderFailed, "Failed to render", errors.New("disk full"))
+		So(err.Error(), ShouldEqual, "Failed to render: disk full")
+		So(errors.Unwrap(err).Error(), ShouldEqual, "disk full")
+	})
+
+	Convey("ErrorCode finds the code of a wrapped Error", t, func() {
+		So(ErrorCode(fmt.Errorf("wrapped: %w", ErrorReadingBody)), ShouldEqual, CodeReadingBody)
+		So(ErrorCode(errors.New("plain error")), ShouldEqual, CodeInternal)
+	})
+
+	Convey("A missing fields error lists the fields in its details", t, func() {
+		err := NewMissingFieldsError([]string{"table_html"})
+		So(err.Code, ShouldEqual, CodeMissingFields)
+		So(err.Details["missing_fields"], ShouldResemble, []string{"table_html"})
+		So(err.Error(), ShouldContainSubstring, "table_html")
+	})
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// the codes of warnings about the footnotes of a table
+var (
+	WarningUnusedFootnote    = "unused_footnote"    // a footnote that isn't referenced from the table
+	WarningDanglingReference = "dangling_reference" // a reference to a footnote that doesn't exist
+)
+
+// FootnoteReference matches a reference to a footnote, e.g. [1], capturing the number of the footnote
+var FootnoteReference = regexp.MustCompile(`\[([0-9]+)]`)
+
+// Warning describes a problem with a table that doesn't prevent it being rendered
+type Warning struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Location string `json:"location,omitempty"` // where the problem was found, e.g. title, data[2][3] or footnotes[0]
+}
+
+// CheckFootnotes returns a warning for each footnote that isn't referenced from the title, subtitle, cells or other
+// footnotes, and for each reference to a footnote that doesn't exist
+func (rr *RenderRequest) CheckFootnotes() []Warning {
+	var warnings []Warning
+	used := make([]bool, len(rr.Footnotes))
+	rr.forEachText(func(location string, text *string) {
+		for _, match := range FootnoteReference.FindAllStringSubmatch(*text, -1) {
+			n, _ := strconv.Atoi(match[1])
+			if n < 1 || n > len(rr.Footnotes) {
+				warnings = append(warnings, Warning{
+					Code:     WarningDanglingReference,
+					Message:  fmt.Sprintf("%s refers to footnote %d, which doesn't exist", match[0], n),
+					Location: location,
+				})
+			} else if location != footnoteLocation(n-1) {
+				used[n-1] = true
+			}
+		}
+	})
+	for i, isUsed := range used {
+		if !isUsed {
+			warnings = append(warnings, Warning{
+				Code:     WarningUnusedFootnote,
+				Message:  fmt.Sprintf("footnote %d isn't referenced from the table", i+1),
+				Location: footnoteLocation(i),
+			})
+		}
+	}
+	return warnings
+}
+
+// RenumberFootnotes numbers the footnotes in the order in which they are first referenced - from the title, the
+// subtitle, the cells row by row, then the other footnotes - and rewrites every reference to match. Footnotes that
+// aren't referenced follow in their existing order. An empty footnote is deleted, along with any references to it.
+// References to footnotes that don't exist are left as they are. It returns the warnings of CheckFootnotes for the
+// renumbered table.
+func (rr *RenderRequest) RenumberFootnotes() []Warning {
+	order := make([]int, 0, len(rr.Footnotes))
+	numbers := make(map[int]int, len(rr.Footnotes)) // the new number of each footnote, by its index, or 0 if deleted
+	add := func(i int) {
+		if _, found := numbers[i]; found {
+			return
+		}
+		if len(rr.Footnotes[i]) == 0 {
+			numbers[i] = 0
+			return
+		}
+		order = append(order, i)
+		numbers[i] = len(order)
+	}
+	rr.forEachText(func(location string, text *string) {
+		for _, match := range FootnoteReference.FindAllStringSubmatch(*text, -1) {
+			if n, _ := strconv.Atoi(match[1]); n >= 1 && n <= len(rr.Footnotes) {
+				add(n - 1)
+			}
+		}
+	})
+	for i := range rr.Footnotes {
+		add(i)
+	}
+
+	rr.forEachText(func(location string, text *string) {
+		*text = FootnoteReference.ReplaceAllStringFunc(*text, func(reference string) string {
+			n, _ := strconv.Atoi(reference[1 : len(reference)-1])
+			if n < 1 || n > len(rr.Footnotes) {
+				return reference
+			}
+			if number := numbers[n-1]; number > 0 {
+				return "[" + strconv.Itoa(number) + "]"
+			}
+			return ""
+		})
+	})
+	footnotes := make([]string, len(order))
+	for number, i := range order {
+		footnotes[number] = rr.Footnotes[i]
+	}
+	rr.Footnotes = footnotes
+	return rr.CheckFootnotes()
+}
+
+// forEachText calls visit with each of the texts that may refer to a footnote - the title, the subtitle, each cell row
+// by row, then each footnote - and its location
+func (rr *RenderRequest) forEachText(visit func(location string, text *string)) {
+	visit("title", &rr.Title)
+	visit("subtitle", &rr.Subtitle)
+	for r := range rr.Data {
+		for c := range rr.Data[r] {
+			visit(fmt.Sprintf("data[%d][%d]", r, c), &rr.Data[r][c])
+		}
+	}
+	for i := range rr.Footnotes {
+		visit(footnoteLocation(i), &rr.Footnotes[i])
+	}
+}
+
+// footnoteLocation returns the location of the footnote with the given index
+func footnoteLocation(i int) string {
+	return fmt.Sprintf("footnotes[%d]", i)
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCheckFootnotes(t *testing.T) {
+	Convey("CheckFootnotes warns of unused footnotes and dangling references", t, func() {
+		request := &RenderRequest{
+			Title:     "Population[2]",
+			Data:      [][]string{{"Wales[4]", "3.1"}, {"Scotland", "5.4[0]"}},
+			Footnotes: []string{"Estimated", "Mid-year", "Refers to itself[3]"},
+		}
+		So(request.CheckFootnotes(), ShouldResemble, []Warning{
+			{Code: WarningDanglingReference, Message: "[4] refers to footnote 4, which doesn't exist", Location: "data[0][0]"},
+			{Code: WarningDanglingReference, Message: "[0] refers to footnote 0, which doesn't exist", Location: "data[1][1]"},
+			{Code: WarningUnusedFootnote, Message: "footnote 1 isn't referenced from the table", Location: "footnotes[0]"},
+			{Code: WarningUnusedFootnote, Message: "footnote 3 isn't referenced from the table", Location: "footnotes[2]"},
+		})
+	})
+
+	Convey("CheckFootnotes returns nothing when every footnote is referenced", t, func() {
+		request := &RenderRequest{Data: [][]string{{"a[1]", "b[2][1]"}}, Footnotes: []string{"one", "two"}}
+		So(request.CheckFootnotes(), ShouldBeEmpty)
+	})
+}
+
+func TestRenumberFootnotes(t *testing.T) {
+	Convey("RenumberFootnotes orders the footnotes by their first reference and rewrites every reference", t, func() {
+		request := &RenderRequest{
+			Title:     "Population[3]",
+			Subtitle:  "Mid-year",
+			Data:      [][]string{{"Wales[1]", "3.1[3]"}, {"Scotland[9]", "5.4[4]"}},
+			Footnotes: []string{"Estimated", "Not referenced", "Provisional", "See note [1]"},
+		}
+		warnings := request.RenumberFootnotes()
+
+		So(request.Title, ShouldEqual, "Population[1]")
+		So(request.Data, ShouldResemble, [][]string{{"Wales[2]", "3.1[1]"}, {"Scotland[9]", "5.4[3]"}})
+		So(request.Footnotes, ShouldResemble, []string{"Provisional", "Estimated", "See note [2]", "Not referenced"})
+		So(warnings, ShouldResemble, []Warning{
+			{Code: WarningDanglingReference, Message: "[9] refers to footnote 9, which doesn't exist", Location: "data[1][0]"},
+			{Code: WarningUnusedFootnote, Message: "footnote 4 isn't referenced from the table", Location: "footnotes[3]"},
+		})
+	})
+
+	Convey("RenumberFootnotes deletes empty footnotes and the references to them", t, func() {
+		request := &RenderRequest{Data: [][]string{{"a[1]", "b[2]", "c[3]"}}, Footnotes: []string{"one", "", "three"}}
+		So(request.RenumberFootnotes(), ShouldBeEmpty)
+		So(request.Data, ShouldResemble, [][]string{{"a[1]", "b", "c[2]"}})
+		So(request.Footnotes, ShouldResemble, []string{"one", "three"})
+	})
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// HandsontableState is the native state of a Handsontable editor, as returned by its api, from which a table can be
+// parsed without scraping the html that the editor renders
+type HandsontableState struct {
+	Data       [][]HandsontableValue `json:"data"`       // the values of the cells, from getData()
+	MergeCells HandsontableMerges    `json:"mergeCells"` // the merged cells, from the mergeCells setting
+	Cell       []HandsontableCell    `json:"cell"`       // the meta of individual cells, from the cell setting
+	ColWidths  HandsontableSizes     `json:"colWidths"`  // the width of the columns in pixels, from the colWidths setting
+	RowHeights HandsontableSizes     `json:"rowHeights"` // the height of the rows in pixels, from the rowHeights setting
+}
+
+// HandsontableValue is the value of a cell, which Handsontable may hold as a string, number, boolean or null
+type HandsontableValue string
+
+// HandsontableMerges are the merged cells of a table. The mergeCells setting may also be true, for no merged cells.
+type HandsontableMerges []HandsontableMerge
+
+// HandsontableMerge is a merged cell, as defined by the mergeCells setting
+type HandsontableMerge struct {
+	Row     int `json:"row"`
+	Col     int `json:"col"`
+	Rowspan int `json:"rowspan"`
+	Colspan int `json:"colspan"`
+}
+
+// HandsontableCell is the meta of a cell, as defined by the cell setting. The className may contain the alignment
+// classes of Handsontable, such as htRight and htTop.
+type HandsontableCell struct {
+	Row       int    `json:"row"`
+	Col       int    `json:"col"`
+	ClassName string `json:"className"`
+}
+
+// HandsontableSizes are the widths of the columns or heights of the rows, given either as a number for every column or
+// row, or as an array with a size (or null) for each
+type HandsontableSizes struct {
+	All  float64   // the size of every column or row, if the setting is a number
+	Each []float64 // the size of each column or row, if the setting is an array. A null size is 0.
+}
+
+// UnmarshalJSON accepts a string, number, boolean or null, keeping the json of a number or boolean as its text
+func (v *HandsontableValue) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*v = ""
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*v = HandsontableValue(s)
+	default:
+		*v = HandsontableValue(data)
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts an array of merged cells, or a boolean
+func (m *HandsontableMerges) UnmarshalJSON(data []byte) error {
+	var enabled bool
+	if json.Unmarshal(data, &enabled) == nil {
+		*m = nil
+		return nil
+	}
+	var merges []HandsontableMerge
+	if err := json.Unmarshal(data, &merges); err != nil {
+		return err
+	}
+	*m = merges
+	return nil
+}
+
+// UnmarshalJSON accepts a number, or an array of numbers and nulls
+func (s *HandsontableSizes) UnmarshalJSON(data []byte) error {
+	*s = HandsontableSizes{}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	if err := json.Unmarshal(data, &s.All); err == nil {
+		return nil
+	}
+	var each []*float64
+	if err := json.Unmarshal(data, &each); err != nil {
+		return err
+	}
+	for _, size := range each {
+		if size == nil {
+			s.Each = append(s.Each, 0)
+		} else {
+			s.Each = append(s.Each, *size)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON writes the sizes as a number, or an array
+func (s HandsontableSizes) MarshalJSON() ([]byte, error) {
+	if s.Each == nil {
+		return []byte(strconv.FormatFloat(s.All, 'f', -1, 64)), nil
+	}
+	return json.Marshal(s.Each)
+}
+
+// Size returns the size of the column or row with the given index, or 0 if it has no size
+func (s HandsontableSizes) Size(i int) float64 {
+	if s.Each == nil {
+		return s.All
+	}
+	if i < len(s.Each) {
+		return s.Each[i]
+	}
+	return 0
+}
+
+// ValidateHandsontableRequest checks the content of a request to parse the state of a Handsontable editor
+func (pr *ParseRequest) ValidateHandsontableRequest(ctx context.Context) error {
+	pr.validateSizes(ctx)
+	if pr.Handsontable == nil || len(pr.Handsontable.Data) == 0 {
+		return NewMissingFieldsError([]string{"handsontable.data"})
+	}
+	return nil
+}
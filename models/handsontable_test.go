@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHandsontableState(t *testing.T) {
+	Convey("The values of cells may be strings, numbers, booleans or null", t, func() {
+		var state HandsontableState
+		So(json.Unmarshal([]byte(`{"data":[["a",1.50,true,null]]}`), &state), ShouldBeNil)
+		So(state.Data, ShouldResemble, [][]HandsontableValue{{"a", "1.50", "true", ""}})
+	})
+
+	Convey("The mergeCells setting may be an array or a boolean", t, func() {
+		var state HandsontableState
+		So(json.Unmarshal([]byte(`{"mergeCells":[{"row":1,"col":2,"rowspan":3,"colspan":4}]}`), &state), ShouldBeNil)
+		So(state.MergeCells, ShouldResemble, HandsontableMerges{{Row: 1, Col: 2, Rowspan: 3, Colspan: 4}})
+
+		So(json.Unmarshal([]byte(`{"mergeCells":true}`), &state), ShouldBeNil)
+		So(state.MergeCells, ShouldBeNil)
+	})
+
+	Convey("Sizes may be a number for every column or row, or an array with nulls", t, func() {
+		var state HandsontableState
+		So(json.Unmarshal([]byte(`{"colWidths":80,"rowHeights":[20,null,30.5]}`), &state), ShouldBeNil)
+		So(state.ColWidths.Size(5), ShouldEqual, 80)
+		So(state.RowHeights.Size(0), ShouldEqual, 20)
+		So(state.RowHeights.Size(1), ShouldEqual, 0)
+		So(state.RowHeights.Size(2), ShouldEqual, 30.5)
+		So(state.RowHeights.Size(3), ShouldEqual, 0)
+
+		b, err := json.Marshal(state.RowHeights)
+		So(err, ShouldBeNil)
+		So(string(b), ShouldEqual, `[20,0,30.5]`)
+	})
+
+	Convey("A request without data is invalid", t, func() {
+		request := ParseRequest{Handsontable: &HandsontableState{}}
+		err := request.ValidateHandsontableRequest(mockContext)
+		So(ErrorCode(err), ShouldEqual, CodeMissingFields)
+	})
+}
@@ -0,0 +1,8 @@
+package models
+
+// ImageSpec chooses the size and colours of a png image of a table. Every field is optional.
+type ImageSpec struct {
+	Width int     `json:"width,omitempty"` // the width of the image in pixels, before scaling. Defaults to 1200.
+	Scale float64 `json:"scale,omitempty"` // multiplies the size of the image and everything in it, e.g. 2 for high density screens. Defaults to 1.
+	Theme string  `json:"theme,omitempty"` // the colour theme of the image. Defaults to the theme of the request if it is also a colour theme, else ons.
+}
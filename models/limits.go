@@ -0,0 +1,380 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Limits defines the maximum size of a request that will be accepted. A zero value means there is no limit.
+type Limits struct {
+	BodyBytes  int64 `json:"max_body_bytes"`  // the size of the request body
+	Rows       int   `json:"max_rows"`        // the number of rows in data
+	Columns    int   `json:"max_columns"`     // the number of cells in any one row of data
+	Cells      int   `json:"max_cells"`       // the total number of cells in data
+	CellLength int   `json:"max_cell_length"` // the length, in bytes, of any one cell in data
+	Merges     int   `json:"max_merges"`      // the number of cell formats with a rowspan or colspan
+	Footnotes  int   `json:"max_footnotes"`   // the number of footnotes
+}
+
+// errBodyTooLarge is returned by a limitedBody when the body exceeds the limit
+var errBodyTooLarge = errors.New("request body exceeds the limit")
+
+// newLimitError creates an Error reporting the limit that was exceeded
+func newLimitError(code string, limit string, max interface{}) *Error {
+	return &Error{
+		Code:    code,
+		Message: fmt.Sprintf("Request exceeds the limit %s (%v)", limit, max),
+		Details: map[string]interface{}{"limit": limit, "max": max},
+	}
+}
+
+// exceeds returns true if the value is greater than a non-zero limit
+func exceeds(value int, limit int) bool {
+	return limit > 0 && value > limit
+}
+
+// limitedBody wraps a request body, counting the bytes read and failing once the limit is exceeded.
+// Any error returned by the underlying reader is recorded so that it can be distinguished from a json syntax error.
+type limitedBody struct {
+	reader io.Reader
+	limit  int64
+	read   int64
+	err    error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.limit > 0 && int64(len(p)) > b.limit-b.read+1 {
+		// read at most one byte more than the limit, so we know it has been exceeded
+		p = p[:b.limit-b.read+1]
+	}
+	n, err := b.reader.Read(p)
+	b.read += int64(n)
+	if b.limit > 0 && b.read > b.limit {
+		b.err = errBodyTooLarge
+		return n, b.err
+	}
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
+}
+
+// decodeError converts an error returned while decoding the body into the appropriate Error
+func (b *limitedBody) decodeError(err error, limits Limits) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	if b.err == errBodyTooLarge {
+		return newLimitError(CodeRequestTooLarge, "max_body_bytes", limits.BodyBytes)
+	}
+	if b.err != nil {
+		return ErrorReadingBody
+	}
+	return ErrorParsingBody
+}
+
+// renderRequestDecoder decodes a RenderRequest from json, enforcing the Limits as each row and cell format is decoded
+// so that an oversized table is rejected before it has been read into memory
+type renderRequestDecoder struct {
+	decoder     *json.Decoder
+	limits      Limits
+	request     *RenderRequest
+	cellCount   int
+	mergeCount  int
+	mergedCells int // the number of cells covered by cell formats with a rowspan or colspan
+}
+
+// decode reads the whole of the json object into the request, returning ErrorNoData if the object is empty
+func (d *renderRequestDecoder) decode() error {
+	if err := d.expectDelim('{'); err != nil {
+		return err
+	}
+	// fields other than the potentially large arrays are collected and decoded in the usual way
+	fields := make(map[string]json.RawMessage)
+	hasData := false
+	for d.decoder.More() {
+		token, err := d.decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+		hasData = true
+		// as with encoding/json, only the last value of a repeated key is kept
+		switch strings.ToLower(key) {
+		case "data":
+			d.request.Data, d.cellCount = nil, 0
+			err = d.decodeArray(d.decodeRow)
+		case "cell_formats":
+			d.request.CellFormats, d.mergeCount, d.mergedCells = nil, 0, 0
+			err = d.decodeArray(d.decodeCellFormat)
+		case "footnotes":
+			d.request.Footnotes = nil
+			err = d.decodeArray(d.decodeFootnote)
+		default:
+			var raw json.RawMessage
+			err = d.decoder.Decode(&raw)
+			fields[key] = raw
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := d.expectDelim('}'); err != nil {
+		return err
+	}
+	if _, err := d.decoder.Token(); err != io.EOF {
+		return ErrorParsingBody
+	}
+	if len(fields) > 0 {
+		b, err := json.Marshal(fields)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(b, d.request); err != nil {
+			return err
+		}
+	}
+	if !hasData {
+		return ErrorNoData
+	}
+	return checkCellFormatsInTable(d.request)
+}
+
+// expectDelim reads the next token, returning an error if it isn't the given delimiter
+func (d *renderRequestDecoder) expectDelim(delim json.Delim) error {
+	token, err := d.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return ErrorParsingBody
+	}
+	return nil
+}
+
+// decodeArray calls decodeElement for each element of a json array. A null value is treated as an empty array.
+func (d *renderRequestDecoder) decodeArray(decodeElement func() error) error {
+	token, err := d.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if token != json.Delim('[') {
+		return ErrorParsingBody
+	}
+	for d.decoder.More() {
+		if err = decodeElement(); err != nil {
+			return err
+		}
+	}
+	return d.expectDelim(']')
+}
+
+// decodeRow decodes a single row of data, checking the number and size of its cells
+func (d *renderRequestDecoder) decodeRow() error {
+	var row []string
+	if err := d.decoder.Decode(&row); err != nil {
+		return err
+	}
+	request := d.request
+	if exceeds(len(request.Data)+1, d.limits.Rows) {
+		return newLimitError(CodeTableTooLarge, "max_rows", d.limits.Rows)
+	}
+	if exceeds(len(row), d.limits.Columns) {
+		return newLimitError(CodeTableTooLarge, "max_columns", d.limits.Columns)
+	}
+	d.cellCount += len(row)
+	if exceeds(d.cellCount, d.limits.Cells) {
+		return newLimitError(CodeTableTooLarge, "max_cells", d.limits.Cells)
+	}
+	for _, cell := range row {
+		if exceeds(len(cell), d.limits.CellLength) {
+			return newLimitError(CodeTableTooLarge, "max_cell_length", d.limits.CellLength)
+		}
+	}
+	request.Data = append(request.Data, row)
+	return nil
+}
+
+// decodeCellFormat decodes a single cell format, checking its position and span, and the number of merged cells
+func (d *renderRequestDecoder) decodeCellFormat() error {
+	var format CellFormat
+	if err := d.decoder.Decode(&format); err != nil {
+		return err
+	}
+	if err := d.limits.checkCellFormat(format); err != nil {
+		return err
+	}
+	if format.Rowspan > 1 || format.Colspan > 1 {
+		d.mergeCount++
+		if exceeds(d.mergeCount, d.limits.Merges) {
+			return newLimitError(CodeTableTooLarge, "max_merges", d.limits.Merges)
+		}
+		d.mergedCells += max(format.Rowspan, 1) * max(format.Colspan, 1)
+		if exceeds(d.mergedCells, d.limits.Cells) {
+			return newLimitError(CodeTableTooLarge, "max_cells", d.limits.Cells)
+		}
+	}
+	d.request.CellFormats = append(d.request.CellFormats, format)
+	return nil
+}
+
+// checkCellFormat returns an error if a cell format has a negative position or span, or covers a row or column
+// beyond the limits
+func (l Limits) checkCellFormat(format CellFormat) error {
+	if format.Row < 0 || format.Column < 0 || format.Rowspan < 0 || format.Colspan < 0 {
+		return invalidCellFormat(format, "cell_formats must not have a negative row, col, rowspan or colspan")
+	}
+	if exceeds(format.Row+max(format.Rowspan, 1), l.Rows) {
+		return newLimitError(CodeTableTooLarge, "max_rows", l.Rows)
+	}
+	if exceeds(format.Column+max(format.Colspan, 1), l.Columns) {
+		return newLimitError(CodeTableTooLarge, "max_columns", l.Columns)
+	}
+	return nil
+}
+
+// checkCellFormatsInTable returns an error if a cell format with a rowspan or colspan covers cells beyond the rows of
+// data, or the longest row
+func checkCellFormatsInTable(request *RenderRequest) error {
+	size := request.Size()
+	for _, format := range request.CellFormats {
+		if format.Rowspan <= 1 && format.Colspan <= 1 {
+			continue
+		}
+		// compared by subtraction, so that a huge span can't overflow
+		if format.Row >= size.Rows || max(format.Rowspan, 1) > size.Rows-format.Row ||
+			format.Column >= size.Columns || max(format.Colspan, 1) > size.Columns-format.Column {
+			return invalidCellFormat(format, "cell_formats must not merge cells beyond the table")
+		}
+	}
+	return nil
+}
+
+// invalidCellFormat creates the Error for a cell format that can't be applied to the table
+func invalidCellFormat(format CellFormat, message string) *Error {
+	return &Error{
+		Code:    CodeInvalidCellFormat,
+		Message: message,
+		Details: map[string]interface{}{"row": format.Row, "col": format.Column, "rowspan": format.Rowspan, "colspan": format.Colspan},
+	}
+}
+
+// decodeFootnote decodes a single footnote, checking the number of footnotes
+func (d *renderRequestDecoder) decodeFootnote() error {
+	var note string
+	if err := d.decoder.Decode(&note); err != nil {
+		return err
+	}
+	if exceeds(len(d.request.Footnotes)+1, d.limits.Footnotes) {
+		return newLimitError(CodeTableTooLarge, "max_footnotes", d.limits.Footnotes)
+	}
+	d.request.Footnotes = append(d.request.Footnotes, note)
+	return nil
+}
+
+// CheckRenderRequest returns an error if a request that has already been decoded exceeds the limits (other than
+// BodyBytes), e.g. one received from the grpc api. Requests decoded from json are checked as they are decoded.
+func (l Limits) CheckRenderRequest(request *RenderRequest) error {
+	if exceeds(len(request.Data), l.Rows) {
+		return newLimitError(CodeTableTooLarge, "max_rows", l.Rows)
+	}
+	cells := 0
+	for _, row := range request.Data {
+		if exceeds(len(row), l.Columns) {
+			return newLimitError(CodeTableTooLarge, "max_columns", l.Columns)
+		}
+		cells += len(row)
+		for _, cell := range row {
+			if exceeds(len(cell), l.CellLength) {
+				return newLimitError(CodeTableTooLarge, "max_cell_length", l.CellLength)
+			}
+		}
+	}
+	if exceeds(cells, l.Cells) {
+		return newLimitError(CodeTableTooLarge, "max_cells", l.Cells)
+	}
+	if exceeds(request.Size().Merges, l.Merges) {
+		return newLimitError(CodeTableTooLarge, "max_merges", l.Merges)
+	}
+	mergedCells := 0
+	for _, format := range request.CellFormats {
+		if err := l.checkCellFormat(format); err != nil {
+			return err
+		}
+		if format.Rowspan > 1 || format.Colspan > 1 {
+			mergedCells += max(format.Rowspan, 1) * max(format.Colspan, 1)
+			if exceeds(mergedCells, l.Cells) {
+				return newLimitError(CodeTableTooLarge, "max_cells", l.Cells)
+			}
+		}
+	}
+	if err := checkCellFormatsInTable(request); err != nil {
+		return err
+	}
+	if exceeds(len(request.Footnotes), l.Footnotes) {
+		return newLimitError(CodeTableTooLarge, "max_footnotes", l.Footnotes)
+	}
+	return nil
+}
+
+// CheckParseRequest returns an error if a request that has already been decoded exceeds the limits (other than BodyBytes)
+func (l Limits) CheckParseRequest(request *ParseRequest) error {
+	if exceeds(len(request.Footnotes), l.Footnotes) {
+		return newLimitError(CodeTableTooLarge, "max_footnotes", l.Footnotes)
+	}
+	return l.checkHandsontable(request.Handsontable)
+}
+
+// CheckTableSize returns an error if a table with the given number of rows and columns, such as one found by a parser,
+// exceeds the limits. The number of cells is checked without multiplying, so that it can't overflow.
+func (l Limits) CheckTableSize(rows int, columns int) error {
+	if exceeds(rows, l.Rows) {
+		return newLimitError(CodeTableTooLarge, "max_rows", l.Rows)
+	}
+	if exceeds(columns, l.Columns) {
+		return newLimitError(CodeTableTooLarge, "max_columns", l.Columns)
+	}
+	if l.Cells > 0 && rows > 0 && columns > l.Cells/rows {
+		return newLimitError(CodeTableTooLarge, "max_cells", l.Cells)
+	}
+	return nil
+}
+
+// checkHandsontable returns an error if the state of a Handsontable editor exceeds the limits
+func (l Limits) checkHandsontable(state *HandsontableState) error {
+	if state == nil {
+		return nil
+	}
+	if exceeds(len(state.Data), l.Rows) {
+		return newLimitError(CodeTableTooLarge, "max_rows", l.Rows)
+	}
+	cells := 0
+	for _, row := range state.Data {
+		if exceeds(len(row), l.Columns) {
+			return newLimitError(CodeTableTooLarge, "max_columns", l.Columns)
+		}
+		cells += len(row)
+		for _, cell := range row {
+			if exceeds(len(cell), l.CellLength) {
+				return newLimitError(CodeTableTooLarge, "max_cell_length", l.CellLength)
+			}
+		}
+	}
+	if exceeds(cells, l.Cells) {
+		return newLimitError(CodeTableTooLarge, "max_cells", l.Cells)
+	}
+	if exceeds(len(state.MergeCells), l.Merges) {
+		return newLimitError(CodeTableTooLarge, "max_merges", l.Merges)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCreateRenderRequestWithLimits(t *testing.T) {
+	body := `{"title":"table_title", "filename":"filename",
+		"data":[["a","b","c"],["d","e","f"]],
+		"cell_formats":[{"row":0,"col":0,"colspan":2},{"row":1,"col":0,"align":"Left"}],
+		"footnotes":["one","two"]}`
+
+	Convey("When a render request is within the limits, the request is decoded", t, func() {
+		limits := Limits{BodyBytes: int64(len(body)), Rows: 2, Columns: 3, Cells: 6, CellLength: 1, Merges: 1, Footnotes: 2}
+		request, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(body), limits)
+
+		So(err, ShouldBeNil)
+		So(request.Title, ShouldEqual, "table_title")
+		So(request.Filename, ShouldEqual, "filename")
+		So(request.Data, ShouldResemble, [][]string{{"a", "b", "c"}, {"d", "e", "f"}})
+		So(len(request.CellFormats), ShouldEqual, 2)
+		So(request.CellFormats[0].Colspan, ShouldEqual, 2)
+		So(request.Footnotes, ShouldResemble, []string{"one", "two"})
+	})
+
+	Convey("When a limit is zero, it is not applied", t, func() {
+		request, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(body), Limits{})
+		So(err, ShouldBeNil)
+		So(len(request.Data), ShouldEqual, 2)
+	})
+
+	Convey("When the body is too large, a request_too_large error is returned", t, func() {
+		_, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(body), Limits{BodyBytes: 20})
+		So(ErrorCode(err), ShouldEqual, CodeRequestTooLarge)
+		So(err.(*Error).Details["limit"], ShouldEqual, "max_body_bytes")
+		So(err.(*Error).Details["max"], ShouldEqual, int64(20))
+	})
+
+	Convey("When the table exceeds a limit, a table_too_large error identifies the limit", t, func() {
+		testCases := map[string]Limits{
+			"max_rows":      {Rows: 1},
+			"max_columns":   {Columns: 2},
+			"max_cells":     {Cells: 5},
+			"max_footnotes": {Footnotes: 1},
+		}
+		for limit, limits := range testCases {
+			_, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(body), limits)
+			So(ErrorCode(err), ShouldEqual, CodeTableTooLarge)
+			So(err.(*Error).Details["limit"], ShouldEqual, limit)
+		}
+
+		_, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(`{"data":[["long value"]]}`), Limits{CellLength: 5})
+		So(err.(*Error).Details["limit"], ShouldEqual, "max_cell_length")
+
+		_, err = CreateRenderRequestWithLimits(mockContext, strings.NewReader(`{"cell_formats":[{"rowspan":2},{"colspan":2}]}`), Limits{Merges: 1})
+		So(err.(*Error).Details["limit"], ShouldEqual, "max_merges")
+	})
+
+	Convey("When a cell format merges cells beyond the limits or the table, it is rejected before the table is rendered", t, func() {
+		huge := `{"data":[["a","b"]],"cell_formats":[{"row":0,"col":0,"colspan":1000000000,"rowspan":1000000000}]}`
+		_, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(huge), Limits{Rows: 10, Columns: 10})
+		So(ErrorCode(err), ShouldEqual, CodeTableTooLarge)
+		So(err.(*Error).Details["limit"], ShouldEqual, "max_rows")
+
+		_, err = CreateRenderRequestWithLimits(mockContext, strings.NewReader(huge), Limits{})
+		So(ErrorCode(err), ShouldEqual, CodeInvalidCellFormat)
+
+		_, err = CreateRenderRequestWithLimits(mockContext, strings.NewReader(`{"cell_formats":[{"row":0,"col":0,"colspan":4,"rowspan":4}]}`), Limits{Cells: 10})
+		So(err.(*Error).Details["limit"], ShouldEqual, "max_cells")
+
+		_, err = CreateRenderRequestWithLimits(mockContext, strings.NewReader(`{"data":[["a"]],"cell_formats":[{"row":0,"col":-1}]}`), Limits{})
+		So(ErrorCode(err), ShouldEqual, CodeInvalidCellFormat)
+
+		request, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(`{"data":[["a","b"],["c"]],"cell_formats":[{"row":0,"col":0,"colspan":2,"rowspan":2}]}`), Limits{})
+		So(err, ShouldBeNil)
+		So(Limits{}.CheckRenderRequest(request), ShouldBeNil)
+
+		request.CellFormats[0].Rowspan = 3
+		So(ErrorCode(Limits{}.CheckRenderRequest(request)), ShouldEqual, CodeInvalidCellFormat)
+	})
+
+	Convey("When the json is invalid, ErrorParsingBody is returned", t, func() {
+		for _, invalid := range []string{`{"data":"foo"}`, `{"data":[["a"]}`, `{"title":"foo"} {}`, `[]`, `{"row_formats":{}}`} {
+			_, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(invalid), Limits{})
+			So(err, ShouldEqual, ErrorParsingBody)
+		}
+	})
+
+	Convey("When data is null, the request is decoded without data", t, func() {
+		request, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(`{"data":null}`), Limits{})
+		So(err, ShouldBeNil)
+		So(request.Data, ShouldBeNil)
+	})
+
+	Convey("When a key is repeated, only its last value is kept, as encoding/json does", t, func() {
+		repeated := `{"data":[["a","b","c"]],"cell_formats":[{"row":0,"col":0,"colspan":3}],"footnotes":["one"],
+			"data":[["d","e"]],"cell_formats":[{"row":0,"col":1,"align":"Left"}],"footnotes":["two"]}`
+		request, err := CreateRenderRequestWithLimits(mockContext, strings.NewReader(repeated), Limits{Cells: 3, Merges: 1, Footnotes: 1})
+		So(err, ShouldBeNil)
+		So(request.Data, ShouldResemble, [][]string{{"d", "e"}})
+		So(len(request.CellFormats), ShouldEqual, 1)
+		So(request.CellFormats[0].Column, ShouldEqual, 1)
+		So(request.Footnotes, ShouldResemble, []string{"two"})
+
+		request, err = CreateRenderRequestWithLimits(mockContext, strings.NewReader(`{"data":[["a"]],"data":null}`), Limits{})
+		So(err, ShouldBeNil)
+		So(request.Data, ShouldBeNil)
+	})
+}
+
+func TestCreateParseRequestWithLimits(t *testing.T) {
+	Convey("When a parse request body is too large, a request_too_large error is returned", t, func() {
+		_, err := CreateParseRequestWithLimits(mockContext, strings.NewReader(`{"table_html":"<table></table>"}`), Limits{BodyBytes: 10})
+		So(ErrorCode(err), ShouldEqual, CodeRequestTooLarge)
+	})
+
+	Convey("When a parse request has too many footnotes, a table_too_large error is returned", t, func() {
+		_, err := CreateParseRequestWithLimits(mockContext, strings.NewReader(`{"table_html":"<table></table>","footnotes":["a","b"]}`), Limits{Footnotes: 1})
+		So(ErrorCode(err), ShouldEqual, CodeTableTooLarge)
+	})
+
+	Convey("When the state of a Handsontable editor exceeds a limit, the limit is reported", t, func() {
+		body := `{"handsontable":{"data":[["a","b"],["c","long"]],"mergeCells":[{"row":0,"col":0,"rowspan":1,"colspan":2}]}}`
+		for limit, limits := range map[string]Limits{
+			"max_rows":        {Rows: 1},
+			"max_columns":     {Columns: 1},
+			"max_cells":       {Cells: 3},
+			"max_cell_length": {CellLength: 3},
+		} {
+			_, err := CreateParseRequestWithLimits(mockContext, strings.NewReader(body), limits)
+			So(ErrorCode(err), ShouldEqual, CodeTableTooLarge)
+			So(err.(*Error).Details["limit"], ShouldEqual, limit)
+		}
+		request, err := CreateParseRequestWithLimits(mockContext, strings.NewReader(body), Limits{Merges: 1})
+		So(err, ShouldBeNil)
+		So(request.Limits, ShouldResemble, Limits{Merges: 1})
+	})
+}
+
+func TestCheckTableSize(t *testing.T) {
+	Convey("When a table found by a parser exceeds a limit, the limit is reported", t, func() {
+		limits := Limits{Rows: 100, Columns: 100, Cells: 1000}
+		So(limits.CheckTableSize(10, 100), ShouldBeNil)
+		So(Limits{}.CheckTableSize(1<<40, 1<<40), ShouldBeNil)
+		for limit, size := range map[string][2]int{
+			"max_rows":    {101, 1},
+			"max_columns": {1, 101},
+			"max_cells":   {11, 100},
+		} {
+			err := limits.CheckTableSize(size[0], size[1])
+			So(ErrorCode(err), ShouldEqual, CodeTableTooLarge)
+			So(err.(*Error).Details["limit"], ShouldEqual, limit)
+		}
+		So(ErrorCode(Limits{Cells: 10}.CheckTableSize(20, 1)), ShouldEqual, CodeTableTooLarge)
+	})
+}
+
+func TestCheckRenderRequest(t *testing.T) {
+	request := &RenderRequest{
+		Data:        [][]string{{"a", "b", "c"}, {"d", "e", "f"}},
+		CellFormats: []CellFormat{{Row: 0, Column: 0, Colspan: 2}, {Row: 1, Column: 0, Align: "Left"}},
+		Footnotes:   []string{"one", "two"},
+	}
+
+	Convey("When a decoded request is within the limits, no error is returned", t, func() {
+		limits := Limits{Rows: 2, Columns: 3, Cells: 6, CellLength: 1, Merges: 1, Footnotes: 2}
+		So(limits.CheckRenderRequest(request), ShouldBeNil)
+		So(Limits{}.CheckRenderRequest(request), ShouldBeNil)
+	})
+
+	Convey("When a decoded request exceeds a limit, the limit is reported", t, func() {
+		request.Data[1][2] = "long"
+		request.CellFormats = append(request.CellFormats, CellFormat{Row: 0, Column: 2, Rowspan: 2})
+		for limit, limits := range map[string]Limits{
+			"max_rows":        {Rows: 1},
+			"max_columns":     {Columns: 2},
+			"max_cells":       {Cells: 5},
+			"max_cell_length": {CellLength: 3},
+			"max_merges":      {Merges: 1},
+			"max_footnotes":   {Footnotes: 1},
+		} {
+			err := limits.CheckRenderRequest(request)
+			So(ErrorCode(err), ShouldEqual, CodeTableTooLarge)
+			So(err.(*Error).Details["limit"], ShouldEqual, limit)
+		}
+	})
+}
@@ -3,21 +3,13 @@ package models
 import (
 	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"io"
 	"io/ioutil"
+	"strings"
 
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
-// A list of errors returned from package
-var (
-	ErrorReadingBody = errors.New("Failed to read message body")
-	ErrorParsingBody = errors.New("Failed to parse json body")
-	ErrorNoData      = errors.New("Bad request - Missing data in body")
-)
-
 // valid values for alignments in the various formats
 var (
 	AlignTop     = "Top"
@@ -29,6 +21,19 @@ var (
 	AlignJustify = "Justify"
 )
 
+// valid values for the layout of an html table on narrow screens
+var (
+	LayoutStacked = "stacked" // each row is stacked into a card, with each cell labelled by its column headings
+	LayoutScroll  = "scroll"  // the table scrolls horizontally, with the heading columns frozen
+)
+
+// valid values for the cell layout of a parse request
+var (
+	CellLayoutAuto         = "auto"         // detect the layout from the html
+	CellLayoutHandsontable = "handsontable" // each row includes every cell, including those hidden by a merged cell
+	CellLayoutStandard     = "standard"     // the cells covered by a merged cell are omitted, as in any html table
+)
+
 // RenderRequest represents a structure for a table render job
 type RenderRequest struct {
 	Title               string         `json:"title,omitempty"`
@@ -44,28 +49,46 @@ type RenderRequest struct {
 	CellFormats         []CellFormat   `json:"cell_formats"`
 	Data                [][]string     `json:"data"`
 	Footnotes           []string       `json:"footnotes"`
+	Theme               string         `json:"theme,omitempty"`         // the name of the theme used to render html. The default theme is used if empty.
+	Language            string         `json:"language,omitempty"`      // the language of the table, e.g. en or cy, used as the lang of an html document
+	InlineStyles        bool           `json:"inline_styles,omitempty"` // if true, the styles of an html document are applied to each element, for email clients that ignore stylesheets
+	Layout              string         `json:"layout,omitempty"`        // the responsive layout of html on narrow screens, stacked or scroll. Any other value is ignored.
+	Chart               *ChartSpec     `json:"chart,omitempty"`         // how the table is drawn as a chart. The chart is derived from the headings of the table if nil.
+	Image               *ImageSpec     `json:"image,omitempty"`         // the size and colours of a png image of the table. The defaults are used if nil.
 }
 
 // ParseRequest represents a request to convert an html table (plus supporting data) into the correct RenderRequest format
 type ParseRequest struct {
-	Title               string          `json:"title"`
-	Subtitle            string          `json:"subtitle"`
-	Source              string          `json:"source"`
-	Filename            string          `json:"filename"`
-	Units               string          `json:"units"`
-	KeepHeadersTogether bool            `json:"keep_headers_together"`
-	Footnotes           []string        `json:"footnotes"`
-	TableHTML           string          `json:"table_html"`
-	IgnoreFirstRow      bool            `json:"ignore_first_row"`       // if true, the first row is ignored
-	IgnoreFirstColumn   bool            `json:"ignore_first_column"`    // if true, the first cell of each row is ignored
-	HeaderRows          int             `json:"header_rows"`            // the number of header rows (th cells) in the output, after ignoring the first row (if applicable)
-	HeaderCols          int             `json:"header_cols"`            // the number of header columns (th cells) in each row of the output, after ignoring the first column (if applicable)
-	CurrentTableWidth   int             `json:"current_table_width"`    // used to convert column width from pixels to %
-	CurrentTableHeight  int             `json:"current_table_height"`   // used to convert row height from pixels to %
-	SingleEmHeight      float32         `json:"single_em_height"`       // used to convert height/width from pixels to em. The height of the following: <div style="display: none; font-size: 1em; margin: 0; padding:0; height: auto; line-height: 1; border:0;">m</div>
-	CellSizeUnits       string          `json:"cell_size_units"`        // 'em', '%' or 'auto' - the desired unit for widths/heights. Auto causes no widths/heights to be specified
-	ColumnWidthToIgnore string          `json:"column_width_to_ignore"` // if the source html applies a default column width that shouldn't be included in the output, specify it here. e.g. '50px'
-	AlignmentClasses    ParseAlignments `json:"alignment_classes"`      // The names of classes that should be interpreted as defining alignment of cells
+	Title               string             `json:"title"`
+	Subtitle            string             `json:"subtitle"`
+	Source              string             `json:"source"`
+	Filename            string             `json:"filename"`
+	Units               string             `json:"units"`
+	KeepHeadersTogether bool               `json:"keep_headers_together"`
+	Footnotes           []string           `json:"footnotes"`
+	TableHTML           string             `json:"table_html"`
+	IgnoreFirstRow      bool               `json:"ignore_first_row"`            // if true, the first row is ignored
+	IgnoreFirstColumn   bool               `json:"ignore_first_column"`         // if true, the first cell of each row is ignored
+	HeaderRows          int                `json:"header_rows"`                 // the number of header rows (th cells) in the output, after ignoring the first row (if applicable)
+	HeaderCols          int                `json:"header_cols"`                 // the number of header columns (th cells) in each row of the output, after ignoring the first column (if applicable)
+	CurrentTableWidth   int                `json:"current_table_width"`         // used to convert column width from pixels to %
+	CurrentTableHeight  int                `json:"current_table_height"`        // used to convert row height from pixels to %
+	SingleEmHeight      float32            `json:"single_em_height"`            // used to convert height/width from pixels to em. The height of the following: <div style="display: none; font-size: 1em; margin: 0; padding:0; height: auto; line-height: 1; border:0;">m</div>
+	CellSizeUnits       string             `json:"cell_size_units"`             // 'em', '%' or 'auto' - the desired unit for widths/heights. Auto causes no widths/heights to be specified
+	ColumnWidthToIgnore string             `json:"column_width_to_ignore"`      // if the source html applies a default column width that shouldn't be included in the output, specify it here. e.g. '50px'
+	AlignmentClasses    ParseAlignments    `json:"alignment_classes"`           // The names of classes that should be interpreted as defining alignment of cells
+	CellLayout          string             `json:"cell_layout,omitempty"`       // handsontable, standard or auto (the default) - whether each row of the html includes the cells hidden by merged cells
+	InferStructure      bool               `json:"infer_structure"`             // if true, headings and totals are inferred from the html where not given above
+	TableSelector       string             `json:"table_selector"`              // chooses the table in table_html: its index, or a css selector - or the index of the table in docx. Defaults to the first table
+	Handsontable        *HandsontableState `json:"handsontable,omitempty"`      // the state of a Handsontable editor, parsed instead of table_html by /parse/handsontable
+	TableMarkdown       string             `json:"table_markdown,omitempty"`    // markdown containing a pipe table, parsed instead of table_html by /parse/markdown
+	TableText           string             `json:"table_text,omitempty"`        // a table of fixed-width columns, parsed instead of table_html by /parse/text
+	ColumnBoundaries    []int              `json:"column_boundaries,omitempty"` // the character positions at which the columns of table_text after the first begin. Detected from the text if not given
+	Docx                []byte             `json:"docx,omitempty"`              // a Word document (.docx), base64 encoded in json, whose tables are parsed instead of table_html by /parse/docx
+	Ods                 []byte             `json:"ods,omitempty"`               // an OpenDocument spreadsheet (.ods), base64 encoded in json, parsed instead of table_html by /parse/ods
+	Sheet               string             `json:"sheet,omitempty"`             // the name of the sheet of ods to parse, or its index. Defaults to the first sheet
+	Range               string             `json:"range,omitempty"`             // the range of cells of the sheet to parse, e.g. A1:D10. Defaults to the cells that aren't empty
+	Limits              Limits             `json:"-"`                           // the limits of the size of the table found by the parser, set by the service rather than the request
 }
 
 // ParseAlignments defines the css classes that should be interpreted as defining the alignment of cells in a table
@@ -84,6 +107,7 @@ type RowFormat struct {
 	Row           int    `json:"row"`                      // the index of the row the format applies to
 	VerticalAlign string `json:"vertical_align,omitempty"` // must be Top, Middle or Bottom to be applied
 	Heading       bool   `json:"heading,omitempty"`
+	Total         bool   `json:"total,omitempty"` // true for a row of totals, which is emphasised and left out of charts by default
 	Height        string `json:"height,omitempty"`
 }
 
@@ -107,25 +131,29 @@ type CellFormat struct {
 
 // CreateRenderRequest manages the creation of a RenderRequest from a reader
 func CreateRenderRequest(ctx context.Context, reader io.Reader) (*RenderRequest, error) {
-	bytes, err := ioutil.ReadAll(reader)
-	if err != nil {
-		log.Error(ctx, "error reading request body", err)
-		return nil, ErrorReadingBody
-	}
+	return CreateRenderRequestWithLimits(ctx, reader, Limits{})
+}
 
-	var request RenderRequest
-	err = json.Unmarshal(bytes, &request)
-	if err != nil {
-		log.Error(ctx, "error unmarshalling JSON", err)
-		return nil, ErrorParsingBody
+// CreateRenderRequestWithLimits manages the creation of a RenderRequest from a reader, returning an error as soon as
+// the body or the table it contains is found to exceed the given limits
+func CreateRenderRequestWithLimits(ctx context.Context, reader io.Reader, limits Limits) (*RenderRequest, error) {
+	body := &limitedBody{reader: reader, limit: limits.BodyBytes}
+	decoder := &renderRequestDecoder{
+		decoder: json.NewDecoder(body),
+		limits:  limits,
+		request: &RenderRequest{},
 	}
 
-	// This should be the last check before returning RenderRequest
-	if len(bytes) == 2 {
-		return &request, ErrorNoData
+	if err := decoder.decode(); err != nil {
+		if err == ErrorNoData {
+			// This should be the last check before returning RenderRequest
+			return decoder.request, ErrorNoData
+		}
+		log.Error(ctx, "error decoding render request", err)
+		return nil, body.decodeError(err, limits)
 	}
 
-	return &request, nil
+	return decoder.request, nil
 }
 
 // ValidateRenderRequest checks the content of the request structure
@@ -134,18 +162,50 @@ func (rr *RenderRequest) ValidateRenderRequest() error {
 	var missingFields []string
 
 	if missingFields != nil {
-		return fmt.Errorf("Missing mandatory fields: %v", missingFields)
+		return NewMissingFieldsError(missingFields)
 	}
 
 	return nil
 }
 
+// TableSize describes the dimensions of the table in a RenderRequest
+type TableSize struct {
+	Rows    int // the number of rows in data
+	Columns int // the number of cells in the longest row of data
+	Cells   int // the total number of cells in data
+	Merges  int // the number of cell formats with a rowspan or colspan
+}
+
+// Size calculates the dimensions of the table
+func (rr *RenderRequest) Size() TableSize {
+	size := TableSize{Rows: len(rr.Data)}
+	for _, row := range rr.Data {
+		size.Cells += len(row)
+		if len(row) > size.Columns {
+			size.Columns = len(row)
+		}
+	}
+	for _, format := range rr.CellFormats {
+		if format.Rowspan > 1 || format.Colspan > 1 {
+			size.Merges++
+		}
+	}
+	return size
+}
+
 // CreateParseRequest manages the creation of a ParseRequest from a reader
 func CreateParseRequest(ctx context.Context, reader io.Reader) (*ParseRequest, error) {
-	bytes, err := ioutil.ReadAll(reader)
+	return CreateParseRequestWithLimits(ctx, reader, Limits{})
+}
+
+// CreateParseRequestWithLimits manages the creation of a ParseRequest from a reader, returning an error if the body
+// exceeds the size limit or contains too many footnotes
+func CreateParseRequestWithLimits(ctx context.Context, reader io.Reader, limits Limits) (*ParseRequest, error) {
+	body := &limitedBody{reader: reader, limit: limits.BodyBytes}
+	bytes, err := ioutil.ReadAll(body)
 	if err != nil {
 		log.Error(ctx, "error reading body", err)
-		return nil, ErrorReadingBody
+		return nil, body.decodeError(err, limits)
 	}
 
 	var request ParseRequest
@@ -155,6 +215,11 @@ func CreateParseRequest(ctx context.Context, reader io.Reader) (*ParseRequest, e
 		return nil, ErrorParsingBody
 	}
 
+	if err = limits.CheckParseRequest(&request); err != nil {
+		return nil, err
+	}
+	request.Limits = limits
+
 	// This should be the last check before returning filter
 	if len(bytes) == 2 {
 		return &request, ErrorNoData
@@ -172,6 +237,64 @@ func (pr *ParseRequest) ValidateParseRequest(ctx context.Context) error {
 		missingFields = append(missingFields, "table_html")
 	}
 
+	pr.validateSizes(ctx)
+
+	switch layout := pr.CellLayout; layout {
+	case CellLayoutAuto, CellLayoutHandsontable, CellLayoutStandard, "":
+		// nothing to do
+	default:
+		log.Info(ctx, "unknown cell layout - the layout will be detected", log.Data{"file_name": pr.Filename, "cell_layout": layout})
+	}
+
+	if missingFields != nil {
+		return NewMissingFieldsError(missingFields)
+	}
+
+	return nil
+}
+
+// ValidateMarkdownRequest checks the content of a request to parse a markdown table
+func (pr *ParseRequest) ValidateMarkdownRequest(ctx context.Context) error {
+	pr.validateSizes(ctx)
+	if len(strings.TrimSpace(pr.TableMarkdown)) == 0 {
+		return NewMissingFieldsError([]string{"table_markdown"})
+	}
+	return nil
+}
+
+// ValidateTextRequest checks the content of a request to parse a table of fixed-width text
+func (pr *ParseRequest) ValidateTextRequest(ctx context.Context) error {
+	pr.validateSizes(ctx)
+	if len(strings.TrimSpace(pr.TableText)) == 0 {
+		return NewMissingFieldsError([]string{"table_text"})
+	}
+	for i, boundary := range pr.ColumnBoundaries {
+		if boundary <= 0 || (i > 0 && boundary <= pr.ColumnBoundaries[i-1]) {
+			log.Info(ctx, "column_boundaries should be positive and ascending - they will be sorted, and others ignored", log.Data{"file_name": pr.Filename})
+			break
+		}
+	}
+	return nil
+}
+
+// ValidateDocxRequest checks the content of a request to parse a table in a Word document
+func (pr *ParseRequest) ValidateDocxRequest(ctx context.Context) error {
+	if len(pr.Docx) == 0 {
+		return NewMissingFieldsError([]string{"docx"})
+	}
+	return nil
+}
+
+// ValidateOdsRequest checks the content of a request to parse a range of an OpenDocument spreadsheet
+func (pr *ParseRequest) ValidateOdsRequest(ctx context.Context) error {
+	if len(pr.Ods) == 0 {
+		return NewMissingFieldsError([]string{"ods"})
+	}
+	return nil
+}
+
+// validateSizes logs a warning if the sizes of cells can't be converted to the requested units
+func (pr *ParseRequest) validateSizes(ctx context.Context) {
 	switch units := pr.CellSizeUnits; units {
 	case "%":
 		if pr.CurrentTableWidth <= 0 {
@@ -186,10 +309,4 @@ func (pr *ParseRequest) ValidateParseRequest(ctx context.Context) error {
 	default:
 		log.Info(ctx, "unknown size unit specified for width", log.Data{"file_name": pr.Filename, "unit": units})
 	}
-
-	if missingFields != nil {
-		return fmt.Errorf("Missing mandatory fields: %v", missingFields)
-	}
-
-	return nil
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -128,4 +129,44 @@ func TestCreateParseRequestWithInvalidJSON(t *testing.T) {
 		So(err, ShouldNotBeNil)
 		So(err.Error(), ShouldContainSubstring, "table_html")
 	})
+	Convey("A request to parse markdown or text requires the markdown or text, rather than html", t, func() {
+		request, err := CreateParseRequest(mockContext, strings.NewReader(`{"title":"foo","table_markdown":"| a |\n|---|"}`))
+		So(err, ShouldBeNil)
+		So(request.ValidateMarkdownRequest(mockContext), ShouldBeNil)
+		err = request.ValidateTextRequest(mockContext)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "table_text")
+	})
+
+	Convey("A request to parse a Word document decodes the base64 document", t, func() {
+		request, err := CreateParseRequest(mockContext, strings.NewReader(`{"title":"foo","docx":"UEsDBA=="}`))
+		So(err, ShouldBeNil)
+		So(request.Docx, ShouldResemble, []byte("PK\x03\x04"))
+		So(request.ValidateDocxRequest(mockContext), ShouldBeNil)
+		So(request.ValidateParseRequest(mockContext).Error(), ShouldContainSubstring, "table_html")
+
+		_, err = CreateParseRequest(mockContext, strings.NewReader(`{"title":"foo","docx":"not base64!"}`))
+		So(errors.Is(err, ErrorParsingBody), ShouldBeTrue)
+	})
+
+	Convey("A request to parse an OpenDocument spreadsheet requires the spreadsheet", t, func() {
+		request, err := CreateParseRequest(mockContext, strings.NewReader(`{"title":"foo","ods":"UEsDBA==","sheet":"Sheet1","range":"A1:B2"}`))
+		So(err, ShouldBeNil)
+		So(request.ValidateOdsRequest(mockContext), ShouldBeNil)
+		So(request.Sheet, ShouldEqual, "Sheet1")
+		So(request.Range, ShouldEqual, "A1:B2")
+
+		request.Ods = nil
+		So(request.ValidateOdsRequest(mockContext).Error(), ShouldContainSubstring, "ods")
+	})
+}
+
+func TestRenderRequestSize(t *testing.T) {
+	Convey("The size of a table is calculated from its data and cell formats", t, func() {
+		request := &RenderRequest{
+			Data:        [][]string{{"a", "b", "c"}, {"d", "e"}},
+			CellFormats: []CellFormat{{Row: 0, Column: 0, Colspan: 2}, {Row: 1, Column: 0, Rowspan: 1, Align: AlignLeft}},
+		}
+		So(request.Size(), ShouldResemble, TableSize{Rows: 2, Columns: 3, Cells: 5, Merges: 1})
+	})
 }
@@ -0,0 +1,582 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxDocxColumns is the largest number of columns of a table in Word, and so the largest span of a cell
+	maxDocxColumns = 63
+	// maxDocxCells is the largest number of cells in a table of a Word document that will be parsed
+	maxDocxCells = 1 << 20
+)
+
+var (
+	// docxAlignments maps the justification of a paragraph to the value of an align attribute
+	docxAlignments = map[string]string{
+		"left":       "left",
+		"start":      "left",
+		"center":     "center",
+		"right":      "right",
+		"end":        "right",
+		"both":       "justify",
+		"distribute": "justify",
+	}
+	// docxVerticalAlignments maps the vertical alignment of a table cell to the value of a valign attribute
+	docxVerticalAlignments = map[string]string{
+		"top":    "top",
+		"center": "middle",
+		"bottom": "bottom",
+	}
+	captionPattern    = regexp.MustCompile(`(?i)^table\s+[0-9]+\b`)
+	notesLabelPattern = regexp.MustCompile(`(?i)^(?:foot)?notes?(?:\s*:\s*(.*))?$`)
+	errNoDocument     = errors.New("word/document.xml is missing")
+)
+
+// docxFile is a Word document: the paragraphs and tables of its body, and the styles and hyperlinks they use
+type docxFile struct {
+	blocks       []*xmlNode            // the paragraphs (p) and tables (tbl) of the body, in order
+	tables       []int                 // the index in blocks of each table
+	styles       map[string]*docxStyle // the paragraph styles, by id
+	defaultStyle string                // the id of the default paragraph style
+	defaultAlign string                // the justification of paragraphs given by the document defaults
+	links        map[string]string     // the target of each relationship, by id
+}
+
+// docxStyle is a paragraph style of a Word document
+type docxStyle struct {
+	name    string // the name of the style, e.g. caption or heading 1
+	basedOn string // the id of the style it inherits from
+	align   string // the justification of paragraphs, if the style gives one
+}
+
+// docxGrid is a table of a Word document laid out on its grid of columns
+type docxGrid struct {
+	cells  [][]*xmlNode // the tc element at the top left of each merged cell, with nil for a covered or empty slot
+	merges [][]merge    // the colspan and rowspan of each cell, where more than 1
+	filled [][]bool     // true for each slot occupied by a cell, including those covered by a merged cell
+}
+
+// ParseDocx converts the table of a Word document chosen by the table selector into the json used to render the table,
+// along with the preview html. The table is chosen by its index, and defaults to the first table.
+func ParseDocx(ctx context.Context, request *models.ParseRequest) ([]byte, error) {
+	doc, err := openDocx(request.Docx, request.Limits)
+	if err != nil {
+		log.Error(ctx, "Unable to read docx", err)
+		return nil, err
+	}
+	index, err := selectTableIndex(request.TableSelector, len(doc.tables), "docx")
+	if err != nil {
+		return nil, err
+	}
+	return createResponse(ctx, func() (*parseModel, error) { return doc.createModel(request, index) })
+}
+
+// ListDocxTables finds every table in a Word document, so that one can be chosen to parse
+func ListDocxTables(ctx context.Context, request *models.ParseRequest) ([]byte, error) {
+	doc, err := openDocx(request.Docx, request.Limits)
+	if err != nil {
+		log.Error(ctx, "Unable to read docx", err)
+		return nil, err
+	}
+	response := TablesResponse{Tables: []TableSummary{}}
+	for i, block := range doc.tables {
+		grid, err := newDocxGrid(doc.blocks[block], request.Limits)
+		if err != nil {
+			log.Error(ctx, "Unable to lay out a table of docx", err, log.Data{"index": i})
+			return nil, err
+		}
+		caption, _ := doc.caption(block)
+		summary := TableSummary{Index: i, Title: doc.paragraphText(caption), Rows: len(grid.cells)}
+		if len(grid.cells) > 0 {
+			summary.Columns = len(grid.cells[0])
+		}
+		response.Tables = append(response.Tables, summary)
+	}
+	return json.Marshal(response)
+}
+
+// selectTableIndex returns the index of the table chosen by the selector from the tables of a document, which must be
+// a number if given. Without a selector, the first table is chosen.
+func selectTableIndex(selector string, count int, field string) (int, error) {
+	if count == 0 {
+		return 0, models.NewError(models.CodeTableNotFound, field+" does not contain a table", nil)
+	}
+	selector = strings.TrimSpace(selector)
+	if len(selector) == 0 {
+		return 0, nil
+	}
+	index, err := strconv.Atoi(selector)
+	if err != nil || index < 0 || index >= count {
+		return 0, &models.Error{
+			Code:    models.CodeTableNotFound,
+			Message: "table_selector is not the index of a table in " + field,
+			Details: map[string]interface{}{"table_selector": selector},
+			Err:     err,
+		}
+	}
+	return index, nil
+}
+
+// openDocx reads the body of a Word document, along with its styles and the targets of its hyperlinks. The number of
+// nodes read from each part is limited by the cells allowed by the limits.
+func openDocx(content []byte, limits models.Limits) (*docxFile, error) {
+	archive, err := openZipDocument(content, "docx", limits)
+	if err != nil {
+		return nil, err
+	}
+	document, err := archive.part("word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, invalidDocument("docx", errNoDocument)
+	}
+	styles, err := archive.part("word/styles.xml")
+	if err != nil {
+		return nil, err
+	}
+	rels, err := archive.part("word/_rels/document.xml.rels")
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &docxFile{styles: make(map[string]*docxStyle), links: make(map[string]string)}
+	doc.addBlocks(document.child("body"))
+	for i, block := range doc.blocks {
+		if block.name == "tbl" {
+			doc.tables = append(doc.tables, i)
+		}
+	}
+	doc.defaultAlign = styles.path("docDefaults", "pPrDefault", "pPr", "jc").attr("val")
+	for _, style := range styles.childrenNamed("style") {
+		if style.attr("type") != "paragraph" {
+			continue
+		}
+		id := style.attr("styleId")
+		doc.styles[id] = &docxStyle{
+			name:    strings.ToLower(style.child("name").attr("val")),
+			basedOn: style.child("basedOn").attr("val"),
+			align:   style.path("pPr", "jc").attr("val"),
+		}
+		if value := style.attr("default"); value == "1" || value == "true" {
+			doc.defaultStyle = id
+		}
+	}
+	for _, rel := range rels.childrenNamed("Relationship") {
+		doc.links[rel.attr("Id")] = rel.attr("Target")
+	}
+	return doc, nil
+}
+
+// addBlocks adds the paragraphs and tables of the body, including those inside content controls
+func (d *docxFile) addBlocks(body *xmlNode) {
+	if body == nil {
+		return
+	}
+	for _, node := range body.children {
+		switch node.name {
+		case "p", "tbl":
+			d.blocks = append(d.blocks, node)
+		case "sdt":
+			d.addBlocks(node.child("sdtContent"))
+		case "customXml":
+			d.addBlocks(node)
+		}
+	}
+}
+
+// createModel creates a model from a table of the document. Repeating header rows are headings, unless the request
+// gives the number of heading rows. The caption above (or below) the table is the title, and the notes following the
+// table are the footnotes, unless they are given in the request. An error is returned if the table is too large.
+func (d *docxFile) createModel(request *models.ParseRequest, index int) (*parseModel, error) {
+	block := d.tables[index]
+	table := d.blocks[block]
+	grid, err := newDocxGrid(table, request.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	// widths are converted to % of the width of the table, unless no widths are wanted
+	sized := *request
+	if len(sized.CellSizeUnits) == 0 {
+		sized.CellSizeUnits = "%"
+	}
+	model := parseModel{
+		request:    &sized,
+		cellLayout: models.CellLayoutStandard,
+		headerRows: request.HeaderRows,
+		headerCols: request.HeaderCols,
+		title:      request.Title,
+		footnotes:  parseFootnotes(request.Footnotes),
+		merges:     grid.merges,
+		inferred:   &Inferred{},
+	}
+
+	caption, after := d.caption(block)
+	if len(model.footnotes) == 0 {
+		model.footnotes = d.notes(after)
+		model.inferred.Footnotes = model.footnotes
+	}
+	model.markers, model.footnotes = newFootnoteMarkers(model.footnotes)
+	if len(model.title) == 0 {
+		model.title = model.markers.convert(d.paragraphText(caption))
+		model.inferred.Title = model.title
+	}
+	if model.headerRows == 0 {
+		for _, row := range table.childrenNamed("tr") {
+			if !isOn(row.path("trPr", "tblHeader"), "val") {
+				break
+			}
+			model.headerRows++
+		}
+		model.inferred.HeaderRows = model.headerRows
+	}
+
+	model.cells = make([][]*html.Node, len(grid.cells))
+	for r, row := range grid.cells {
+		model.cells[r] = make([]*html.Node, len(row))
+		for c, tc := range row {
+			switch {
+			case tc != nil:
+				model.cells[r][c] = d.cell(tc)
+			case !grid.filled[r][c]:
+				model.cells[r][c] = newCell("")
+			}
+		}
+	}
+	model.alignments = parseAlignments(&model)
+	model.widths = gridWidths(table)
+	return &model, nil
+}
+
+// newDocxGrid lays out the cells of a table on its grid. A cell spans the columns given by its gridSpan, and a cell
+// that continues a vertical merge (vMerge) extends the rowspan of the cell above it. Rows may skip columns before and
+// after their cells (gridBefore and gridAfter). Each of these is at most maxDocxColumns, and an error is returned before
+// the grid is laid out if it would have more than maxDocxCells cells or exceed the limits.
+func newDocxGrid(table *xmlNode, limits models.Limits) (*docxGrid, error) {
+	type position struct{ r, c int }
+	rows := table.childrenNamed("tr")
+	numColumns := len(table.path("tblGrid").childrenNamed("gridCol"))
+	for _, row := range rows {
+		width := gridValue(row.path("trPr", "gridBefore"), 0) + gridValue(row.path("trPr", "gridAfter"), 0)
+		for _, tc := range tableCells(row) {
+			width += gridValue(tc.path("tcPr", "gridSpan"), 1)
+		}
+		numColumns = max(numColumns, width)
+	}
+	if len(rows) > 0 && numColumns > maxDocxCells/len(rows) {
+		return nil, &models.Error{
+			Code:    models.CodeTableTooLarge,
+			Message: fmt.Sprintf("The table is larger than the limit (%d cells)", maxDocxCells),
+			Details: map[string]interface{}{"rows": len(rows), "columns": numColumns, "max": maxDocxCells},
+		}
+	}
+	if err := limits.CheckTableSize(len(rows), numColumns); err != nil {
+		return nil, err
+	}
+
+	grid := &docxGrid{
+		cells:  make([][]*xmlNode, len(rows)),
+		merges: make([][]merge, len(rows)),
+		filled: make([][]bool, len(rows)),
+	}
+	origins := make([][]*position, len(rows))
+	for r, row := range rows {
+		grid.cells[r] = make([]*xmlNode, numColumns)
+		grid.merges[r] = make([]merge, numColumns)
+		grid.filled[r] = make([]bool, numColumns)
+		origins[r] = make([]*position, numColumns)
+		c := gridValue(row.path("trPr", "gridBefore"), 0)
+		for _, tc := range tableCells(row) {
+			if c >= numColumns {
+				break
+			}
+			span := min(gridValue(tc.path("tcPr", "gridSpan"), 1), numColumns-c)
+			origin := &position{r, c}
+			vMerge, hMerge := tc.path("tcPr", "vMerge"), tc.path("tcPr", "hMerge")
+			switch {
+			case vMerge != nil && vMerge.attr("val") != "restart" && r > 0 && origins[r-1][c] != nil:
+				origin = origins[r-1][c]
+				grid.merges[origin.r][origin.c].rowspan = r - origin.r + 1
+			case hMerge != nil && hMerge.attr("val") != "restart" && c > 0 && origins[r][c-1] != nil && origins[r][c-1].r == r:
+				origin = origins[r][c-1]
+				grid.merges[r][origin.c].colspan = c + span - origin.c
+			default:
+				grid.cells[r][c] = tc
+				if span > 1 {
+					grid.merges[r][c].colspan = span
+				}
+			}
+			for i := c; i < c+span; i++ {
+				origins[r][i] = origin
+				grid.filled[r][i] = true
+			}
+			c += span
+		}
+	}
+	return grid, nil
+}
+
+// gridValue returns the number of columns given by the val of a gridSpan, gridBefore or gridAfter, or the default if
+// there is none. It is at least the default and at most maxDocxColumns.
+func gridValue(node *xmlNode, defaultValue int) int {
+	return min(max(attrInt(node, "val", defaultValue), defaultValue), maxDocxColumns)
+}
+
+// tableCells returns the cells of a row, including those inside content controls
+func tableCells(row *xmlNode) []*xmlNode {
+	var cells []*xmlNode
+	for _, node := range row.children {
+		switch node.name {
+		case "tc":
+			cells = append(cells, node)
+		case "sdt":
+			cells = append(cells, tableCells(node.child("sdtContent"))...)
+		case "customXml":
+			cells = append(cells, tableCells(node)...)
+		}
+	}
+	return cells
+}
+
+// gridWidths returns the width of each column of the table's grid as a % of the width of the table
+func gridWidths(table *xmlNode) [][]string {
+	columns := table.path("tblGrid").childrenNamed("gridCol")
+	total := 0
+	for _, column := range columns {
+		total += attrInt(column, "w", 0)
+	}
+	if total <= 0 {
+		return nil
+	}
+	widths := make([][]string, len(columns))
+	for c, column := range columns {
+		if width := attrInt(column, "w", 0); width > 0 {
+			length := fmt.Sprintf("%.1f%%", float64(width)*100/float64(total))
+			widths[c] = []string{widthTrailingZeroesPattern.ReplaceAllString(length, "$1")}
+		}
+	}
+	return widths
+}
+
+// cell creates a td element from a table cell, with the text of its paragraphs separated by line breaks, the
+// justification of its first paragraph containing text, and its vertical alignment
+func (d *docxFile) cell(tc *xmlNode) *html.Node {
+	var values []string
+	align := ""
+	for _, p := range tc.childrenNamed("p") {
+		value := strings.TrimSpace(runsMarkup(d.runs(p, "")))
+		if len(value) == 0 {
+			continue
+		}
+		if len(values) == 0 {
+			align = docxAlignments[d.paragraphAlignment(p)]
+		}
+		values = append(values, value)
+	}
+	cell := newCell(strings.Join(values, "\n"))
+	if len(align) > 0 {
+		h.AddAttribute(cell, "align", align)
+	}
+	if valign := docxVerticalAlignments[tc.path("tcPr", "vAlign").attr("val")]; len(valign) > 0 {
+		h.AddAttribute(cell, "valign", valign)
+	}
+	return cell
+}
+
+// caption returns the caption of the table at the given index of the blocks - the paragraph before the table, or else
+// after it, that has the caption style or begins with 'Table' and a number - and the index of the block following the
+// table and any caption below it
+func (d *docxFile) caption(block int) (*xmlNode, int) {
+	after := block + 1
+	if block > 0 && d.isCaption(d.blocks[block-1]) {
+		return d.blocks[block-1], after
+	}
+	if after < len(d.blocks) && d.isCaption(d.blocks[after]) {
+		return d.blocks[after], after + 1
+	}
+	return nil, after
+}
+
+// isCaption returns true if the block is a paragraph with the caption style, or that begins with 'Table' and a number
+func (d *docxFile) isCaption(block *xmlNode) bool {
+	if block.name != "p" {
+		return false
+	}
+	return d.styleName(block) == "caption" || captionPattern.MatchString(d.paragraphText(block))
+}
+
+// notes returns the text of the notes that begin at the given index of the blocks: paragraphs with a note style, list
+// items, or paragraphs that begin with the marker of a footnote, or any paragraphs following a label such as 'Notes:'.
+// The notes end at a blank paragraph, a heading, or a table.
+func (d *docxFile) notes(start int) []string {
+	var notes []string
+	label := false
+	for _, block := range d.blocks[min(start, len(d.blocks)):] {
+		if block.name != "p" || d.isHeading(block) {
+			break
+		}
+		text := d.paragraphText(block)
+		if len(text) == 0 {
+			// blank paragraphs may separate the table from its notes
+			if len(notes) > 0 || label {
+				break
+			}
+			continue
+		}
+		if match := notesLabelPattern.FindStringSubmatch(text); match != nil {
+			label = true
+			if len(match[1]) > 0 {
+				notes = append(notes, match[1])
+			}
+			continue
+		}
+		if !label && !d.isNote(block, text) {
+			break
+		}
+		notes = append(notes, text)
+	}
+	return notes
+}
+
+// isNote returns true if the paragraph has a note style, is a list item, or begins with the marker of a footnote
+func (d *docxFile) isNote(p *xmlNode, text string) bool {
+	return strings.Contains(d.styleName(p), "note") || p.path("pPr", "numPr") != nil || notePrefixPattern.MatchString(text)
+}
+
+// isHeading returns true if the paragraph has a heading or title style
+func (d *docxFile) isHeading(p *xmlNode) bool {
+	name := d.styleName(p)
+	return strings.HasPrefix(name, "heading") || name == "title"
+}
+
+// styleName returns the name of the style of a paragraph, in lower case
+func (d *docxFile) styleName(p *xmlNode) string {
+	id := p.path("pPr", "pStyle").attr("val")
+	if len(id) == 0 {
+		id = d.defaultStyle
+	}
+	if style, found := d.styles[id]; found && len(style.name) > 0 {
+		return style.name
+	}
+	return strings.ToLower(id)
+}
+
+// paragraphAlignment returns the justification of a paragraph, from its properties, or else its style or the styles it
+// is based on, or else the document defaults
+func (d *docxFile) paragraphAlignment(p *xmlNode) string {
+	if align := p.path("pPr", "jc").attr("val"); len(align) > 0 {
+		return align
+	}
+	id := p.path("pPr", "pStyle").attr("val")
+	if len(id) == 0 {
+		id = d.defaultStyle
+	}
+	for i := 0; i < len(d.styles); i++ {
+		style, found := d.styles[id]
+		if !found {
+			break
+		}
+		if len(style.align) > 0 {
+			return style.align
+		}
+		id = style.basedOn
+	}
+	return d.defaultAlign
+}
+
+// paragraphText returns the text of a paragraph, with runs of whitespace collapsed to a single space
+func (d *docxFile) paragraphText(p *xmlNode) string {
+	var b strings.Builder
+	for _, run := range d.runs(p, "") {
+		b.WriteString(run.text)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// runs returns the runs of text in a paragraph, or an element within it, including those in hyperlinks, insertions and
+// fields. Deleted text is left out.
+func (d *docxFile) runs(node *xmlNode, link string) []textRun {
+	var runs []textRun
+	if node == nil {
+		return runs
+	}
+	for _, child := range node.children {
+		switch child.name {
+		case "r":
+			runs = append(runs, d.run(child, link))
+		case "hyperlink":
+			target := link
+			if id := child.attr("id"); len(id) > 0 {
+				target = d.links[id]
+			}
+			runs = append(runs, d.runs(child, target)...)
+		case "ins", "smartTag", "customXml", "fldSimple", "moveTo":
+			runs = append(runs, d.runs(child, link)...)
+		case "sdt":
+			runs = append(runs, d.runs(child.child("sdtContent"), link)...)
+		}
+	}
+	return runs
+}
+
+// run returns the text and formatting of a run. Tabs are replaced by a space, and breaks by a new line.
+func (d *docxFile) run(r *xmlNode, link string) textRun {
+	properties := r.child("rPr")
+	vertAlign := properties.child("vertAlign").attr("val")
+	run := textRun{
+		bold:        isOn(properties.child("b"), "val"),
+		italic:      isOn(properties.child("i"), "val"),
+		superscript: vertAlign == "superscript",
+		subscript:   vertAlign == "subscript",
+		link:        link,
+	}
+	var b strings.Builder
+	for _, child := range r.children {
+		switch child.name {
+		case "t":
+			b.WriteString(child.textContent())
+		case "tab", "ptab":
+			b.WriteString(" ")
+		case "br", "cr":
+			b.WriteString("\n")
+		case "noBreakHyphen":
+			b.WriteString("-")
+		}
+	}
+	run.text = b.String()
+	return run
+}
+
+// isOn returns true if an element of the form <w:b/> is present and the given attribute doesn't turn it off
+func isOn(node *xmlNode, attr string) bool {
+	if node == nil {
+		return false
+	}
+	switch strings.ToLower(node.attr(attr)) {
+	case "0", "false", "off":
+		return false
+	}
+	return true
+}
+
+// attrInt returns the value of an attribute that is a whole number, or the default value
+func attrInt(node *xmlNode, attr string, defaultValue int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(node.attr(attr)))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,230 @@
+package parser_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	docxNamespaces = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+	docxBody = `
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Population</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="Caption"/></w:pPr><w:r><w:t xml:space="preserve">Table 1: Population </w:t></w:r><w:r><w:rPr><w:i/></w:rPr><w:t>by region</w:t></w:r></w:p>
+<w:tbl>
+  <w:tblGrid><w:gridCol w:w="4000"/><w:gridCol w:w="2000"/><w:gridCol w:w="2000"/></w:tblGrid>
+  <w:tr>
+    <w:trPr><w:tblHeader/></w:trPr>
+    <w:tc><w:tcPr><w:vMerge w:val="restart"/><w:vAlign w:val="bottom"/></w:tcPr><w:p><w:r><w:t>Region</w:t></w:r></w:p></w:tc>
+    <w:tc><w:tcPr><w:gridSpan w:val="2"/></w:tcPr><w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t>Count</w:t></w:r></w:p></w:tc>
+  </w:tr>
+  <w:tr>
+    <w:trPr><w:tblHeader w:val="true"/></w:trPr>
+    <w:tc><w:tcPr><w:vMerge/></w:tcPr><w:p/></w:tc>
+    <w:tc><w:p><w:pPr><w:pStyle w:val="TableNumber"/></w:pPr><w:r><w:t>2020</w:t></w:r></w:p></w:tc>
+    <w:tc><w:p><w:pPr><w:pStyle w:val="TableNumber"/></w:pPr><w:r><w:t>2021</w:t></w:r></w:p></w:tc>
+  </w:tr>
+  <w:tr>
+    <w:tc><w:p><w:r><w:rPr><w:b/></w:rPr><w:t>North</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve"> East</w:t></w:r><w:r><w:rPr><w:vertAlign w:val="superscript"/></w:rPr><w:t>1</w:t></w:r></w:p></w:tc>
+    <w:tc><w:p><w:pPr><w:pStyle w:val="TableNumber"/></w:pPr><w:r><w:t>1,234</w:t></w:r></w:p></w:tc>
+    <w:tc><w:tcPr><w:vAlign w:val="center"/></w:tcPr><w:p><w:pPr><w:pStyle w:val="TableNumber"/></w:pPr><w:r><w:t>1,240</w:t></w:r></w:p></w:tc>
+  </w:tr>
+  <w:tr>
+    <w:tc><w:p><w:hyperlink r:id="rId9"><w:r><w:t>Wales</w:t></w:r></w:hyperlink></w:p><w:p><w:r><w:t>a &lt; b</w:t></w:r><w:del><w:r><w:delText>gone</w:delText></w:r></w:del></w:p></w:tc>
+    <w:tc><w:p><w:pPr><w:pStyle w:val="TableNumber"/></w:pPr><w:r><w:t>567</w:t></w:r></w:p></w:tc>
+    <w:tc><w:p><w:pPr><w:pStyle w:val="TableNumber"/></w:pPr><w:r><w:t>..</w:t></w:r></w:p></w:tc>
+  </w:tr>
+</w:tbl>
+<w:p/>
+<w:p><w:r><w:t>Notes:</w:t></w:r></w:p>
+<w:p><w:r><w:t>1. Includes estimates</w:t></w:r></w:p>
+<w:p><w:r><w:t>Figures are rounded</w:t></w:r></w:p>
+<w:p/>
+<w:p><w:r><w:t>Some text after the notes.</w:t></w:r></w:p>
+<w:tbl>
+  <w:tr><w:tc><w:p><w:r><w:t>a</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>b</w:t></w:r></w:p></w:tc></w:tr>
+</w:tbl>
+<w:p><w:pPr><w:pStyle w:val="Caption"/></w:pPr><w:r><w:t>Table 2: Below</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="TableNote"/></w:pPr><w:r><w:t>A note</w:t></w:r></w:p>
+`
+
+	docxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles ` + docxNamespaces + `>
+  <w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>
+  <w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/></w:style>
+  <w:style w:type="paragraph" w:styleId="Caption"><w:name w:val="caption"/><w:basedOn w:val="Normal"/></w:style>
+  <w:style w:type="paragraph" w:styleId="Right"><w:name w:val="Right"/><w:basedOn w:val="Normal"/><w:pPr><w:jc w:val="right"/></w:pPr></w:style>
+  <w:style w:type="paragraph" w:styleId="TableNumber"><w:name w:val="Table Number"/><w:basedOn w:val="Right"/></w:style>
+  <w:style w:type="paragraph" w:styleId="TableNote"><w:name w:val="Table Note"/><w:basedOn w:val="Normal"/></w:style>
+</w:styles>`
+
+	docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId9" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com/wales" TargetMode="External"/>
+</Relationships>`
+)
+
+func TestParseDocx(t *testing.T) {
+
+	Convey("ParseDocx should convert the first table of a Word document to the json used to render it", t, func() {
+		response := invokeParseDocx(&models.ParseRequest{Filename: "myFilename", Docx: createDocx(docxBody)})
+
+		So(response.JSON.Data, ShouldResemble, [][]string{
+			{"Region", "Count", ""},
+			{"", "2020", "2021"},
+			{"<strong>North East</strong>[1]", "1,234", "1,240"},
+			{"<a href=\"https://example.com/wales\">Wales</a>\na &lt; b", "567", ".."},
+		})
+		So(response.JSON.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}, {Row: 1, Heading: true}})
+		So(response.JSON.CellFormats, ShouldResemble, []models.CellFormat{
+			{Row: 0, Column: 0, Rowspan: 2, VerticalAlign: models.AlignBottom},
+			{Row: 0, Column: 1, Colspan: 2, Align: models.AlignCenter},
+			{Row: 1, Column: 1, Align: models.AlignRight},
+			{Row: 2, Column: 1, Align: models.AlignRight},
+			{Row: 2, Column: 2, VerticalAlign: models.AlignMiddle},
+			{Row: 3, Column: 1, Align: models.AlignRight},
+		})
+		So(response.JSON.ColumnFormats, ShouldResemble, []models.ColumnFormat{
+			{Column: 0, Width: "50%"},
+			{Column: 1, Width: "25%"},
+			{Column: 2, Align: models.AlignRight, Width: "25%"},
+		})
+		So(response.PreviewHTML, ShouldContainSubstring, "<table")
+	})
+
+	Convey("ParseDocx should take the title from the caption, and the footnotes from the notes after the table", t, func() {
+		response := invokeParseDocx(&models.ParseRequest{Filename: "myFilename", Docx: createDocx(docxBody)})
+
+		So(response.JSON.Title, ShouldEqual, "Table 1: Population by region")
+		So(response.JSON.Footnotes, ShouldResemble, []string{"Includes estimates", "Figures are rounded"})
+		So(response.Inferred, ShouldResemble, &parser.Inferred{
+			HeaderRows: 2,
+			Title:      "Table 1: Population by region",
+			Footnotes:  []string{"1. Includes estimates", "Figures are rounded"},
+		})
+	})
+
+	Convey("ParseDocx should parse the chosen table, with a caption below it", t, func() {
+		request := &models.ParseRequest{Filename: "myFilename", Docx: createDocx(docxBody), TableSelector: "1", CellSizeUnits: "auto"}
+		response := invokeParseDocx(request)
+
+		So(response.JSON.Data, ShouldResemble, [][]string{{"a", "b"}})
+		So(response.JSON.Title, ShouldEqual, "Table 2: Below")
+		So(response.JSON.Footnotes, ShouldResemble, []string{"A note"})
+		So(response.JSON.RowFormats, ShouldBeEmpty)
+	})
+
+	Convey("ParseDocx should prefer the headings, title and footnotes of the request", t, func() {
+		request := &models.ParseRequest{
+			Filename:   "myFilename",
+			Docx:       createDocx(docxBody),
+			Title:      "myTitle",
+			HeaderRows: 1,
+			Footnotes:  []string{"myNote"},
+		}
+		response := invokeParseDocx(request)
+
+		So(response.JSON.Title, ShouldEqual, "myTitle")
+		So(response.JSON.Footnotes, ShouldResemble, []string{"myNote"})
+		So(response.JSON.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}})
+		So(response.Inferred, ShouldResemble, &parser.Inferred{})
+	})
+
+	Convey("ParseDocx should return an error for a document that can't be read, or a table that doesn't exist", t, func() {
+		_, err := parser.ParseDocx(mockContext, &models.ParseRequest{Docx: []byte("not a zip")})
+		So(errors.Is(err, &models.Error{Code: models.CodeInvalidDocument}), ShouldBeTrue)
+
+		_, err = parser.ParseDocx(mockContext, &models.ParseRequest{Docx: createDocx(docxBody), TableSelector: "2"})
+		So(errors.Is(err, &models.Error{Code: models.CodeTableNotFound}), ShouldBeTrue)
+
+		_, err = parser.ParseDocx(mockContext, &models.ParseRequest{Docx: createDocx(`<w:p/>`)})
+		So(errors.Is(err, &models.Error{Code: models.CodeTableNotFound}), ShouldBeTrue)
+	})
+
+	Convey("ParseDocx should limit the spans of cells to the columns of a table in Word, and reject a table that is too large", t, func() {
+		table := `<w:tbl>` +
+			`<w:tr><w:tc><w:tcPr><w:gridSpan w:val="400000000"/></w:tcPr><w:p><w:r><w:t>a</w:t></w:r></w:p></w:tc></w:tr>` +
+			`<w:tr><w:trPr><w:gridBefore w:val="-5"/><w:gridAfter w:val="400000000"/></w:trPr><w:tc><w:p><w:r><w:t>b</w:t></w:r></w:p></w:tc></w:tr>` +
+			`</w:tbl>`
+		response := invokeParseDocx(&models.ParseRequest{Docx: createDocx(table)})
+		So(response.JSON.Data, ShouldHaveLength, 2)
+		So(response.JSON.Data[0], ShouldHaveLength, 64)
+		So(response.JSON.Data[1][0], ShouldEqual, "b")
+		So(response.JSON.CellFormats[0], ShouldResemble, models.CellFormat{Row: 0, Column: 0, Colspan: 63})
+
+		_, err := parser.ParseDocx(mockContext, &models.ParseRequest{Docx: createDocx(table), Limits: models.Limits{Cells: 100}})
+		So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+
+		row := `<w:tr><w:tc><w:tcPr><w:gridSpan w:val="63"/></w:tcPr></w:tc></w:tr>`
+		_, err = parser.ListDocxTables(mockContext, &models.ParseRequest{Docx: createDocx(`<w:tbl>` + strings.Repeat(row, 16645) + `</w:tbl>`)})
+		So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+	})
+
+	Convey("ParseDocx should stop reading a document with more elements than the limits allow", t, func() {
+		docx := createDocx(docxBody + strings.Repeat("<w:p/>", 70000))
+		So(len(docx), ShouldBeLessThan, 20000)
+
+		_, err := parser.ParseDocx(mockContext, &models.ParseRequest{Docx: docx, Limits: models.Limits{Cells: 10}})
+		So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+		So(err.(*models.Error).Details["part"], ShouldEqual, "word/document.xml")
+
+		_, err = parser.ListDocxTables(mockContext, &models.ParseRequest{Docx: docx, Limits: models.Limits{Cells: 10}})
+		So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+
+		response := invokeParseDocx(&models.ParseRequest{Docx: docx, Limits: models.Limits{Cells: 5000}})
+		So(response.JSON.Data, ShouldHaveLength, 4)
+	})
+}
+
+func TestListDocxTables(t *testing.T) {
+
+	Convey("ListDocxTables should list the tables of a Word document", t, func() {
+		resultBytes, err := parser.ListDocxTables(mockContext, &models.ParseRequest{Docx: createDocx(docxBody)})
+		So(err, ShouldBeNil)
+
+		result := parser.TablesResponse{}
+		So(json.Unmarshal(resultBytes, &result), ShouldBeNil)
+		So(result.Tables, ShouldResemble, []parser.TableSummary{
+			{Index: 0, Title: "Table 1: Population by region", Rows: 4, Columns: 3},
+			{Index: 1, Title: "Table 2: Below", Rows: 1, Columns: 2},
+		})
+	})
+}
+
+// createDocx creates a Word document with the given body
+func createDocx(body string) []byte {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	parts := map[string]string{
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<w:document ` + docxNamespaces + `><w:body>` + body + `</w:body></w:document>`,
+		"word/styles.xml":              docxStyles,
+		"word/_rels/document.xml.rels": docxRels,
+	}
+	for name, content := range parts {
+		f, err := w.Create(name)
+		So(err, ShouldBeNil)
+		_, err = f.Write([]byte(content))
+		So(err, ShouldBeNil)
+	}
+	So(w.Close(), ShouldBeNil)
+	return b.Bytes()
+}
+
+func invokeParseDocx(request *models.ParseRequest) *parser.ResponseModel {
+	resultBytes, err := parser.ParseDocx(mockContext, request)
+	So(err, ShouldBeNil)
+
+	result := parser.ResponseModel{}
+	So(json.Unmarshal(resultBytes, &result), ShouldBeNil)
+	return &result
+}
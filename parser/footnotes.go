@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// footnoteSymbols are the symbols used to mark footnotes, in their conventional order
+	footnoteSymbols = []string{"*", "†", "‡", "§", "‖", "¶"}
+
+	noteMarkerPattern        = regexp.MustCompile(`(?i)\[\s*(?:foot)?note\s*([0-9]+)\s*]`)
+	superscriptMarkerPattern = regexp.MustCompile(`<sup>\s*([^<]*?)\s*</sup>`)
+	superscriptNotePattern   = regexp.MustCompile(`(?i)^(?:(?:foot)?note\s*)?\(?([0-9]+)\)?$`)
+	textMarkerPattern        = regexp.MustCompile(`\*+|[†‡§‖¶]|\([0-9]+\)`)
+	symbolPattern            = regexp.MustCompile(`^(?:\*+|[†‡§‖¶])$`)
+	tagPattern               = regexp.MustCompile(`<[^>]*>`)
+	notePrefixPattern        = regexp.MustCompile(`(?i)^\s*(?:\[(?:(?:foot)?note\s*)?([0-9]+)]|\(([0-9]+)\)|([0-9]+)[.):]|(?:foot)?note\s*([0-9]+)\s*[.:)-]?|(\*+|[†‡§‖¶]))\s*`)
+)
+
+// footnoteMarkers converts the markers of footnotes in the values of a table - superscripts, numbers in brackets,
+// symbols such as * and †, and [note 1] - to references of the form [n]
+type footnoteMarkers struct {
+	count   int            // the number of footnotes
+	symbols map[string]int // the number of the footnote marked by each symbol, from the markers at the start of the footnotes
+}
+
+// newFootnoteMarkers removes the marker from the start of each footnote - a number that matches the position of the
+// footnote, or a symbol - and returns the markers along with the text of the footnotes
+func newFootnoteMarkers(notes []string) (*footnoteMarkers, []string) {
+	m := &footnoteMarkers{count: len(notes), symbols: make(map[string]int)}
+	result := make([]string, len(notes))
+	for i, note := range notes {
+		result[i] = note
+		match := notePrefixPattern.FindStringSubmatch(note)
+		if match == nil || len(match[0]) == len(note) {
+			continue
+		}
+		if symbol := match[5]; len(symbol) > 0 {
+			m.symbols[symbol] = i + 1
+			result[i] = note[len(match[0]):]
+			continue
+		}
+		if number, _ := strconv.Atoi(match[1] + match[2] + match[3] + match[4]); number == i+1 {
+			result[i] = note[len(match[0]):]
+		}
+	}
+	return m, result
+}
+
+// convert returns the value with each marker of a footnote replaced by a reference to the footnote. A symbol or number
+// in brackets is a marker only if it follows a word without a space, or ends the value, and refers to a footnote.
+func (m *footnoteMarkers) convert(value string) string {
+	value = noteMarkerPattern.ReplaceAllString(value, "[$1]")
+	value = superscriptMarkerPattern.ReplaceAllStringFunc(value, func(superscript string) string {
+		marker := superscriptMarkerPattern.FindStringSubmatch(superscript)[1]
+		if number, ok := m.number(marker); ok {
+			return "[" + strconv.Itoa(number) + "]"
+		}
+		if match := superscriptNotePattern.FindStringSubmatch(marker); match != nil && match[0] != match[1] {
+			if number, _ := strconv.Atoi(match[1]); number >= 1 && number <= m.count {
+				return "[" + match[1] + "]"
+			}
+		}
+		return superscript
+	})
+
+	tags := tagPattern.FindAllStringIndex(value, -1)
+	var b strings.Builder
+	last := 0
+	for _, match := range textMarkerPattern.FindAllStringIndex(value, -1) {
+		start, end := match[0], match[1]
+		number, ok := m.number(value[start:end])
+		if !ok || insideTag(tags, start) || !isMarkerPosition(value, start, end) {
+			continue
+		}
+		b.WriteString(value[last:start])
+		b.WriteString("[" + strconv.Itoa(number) + "]")
+		last = end
+	}
+	b.WriteString(value[last:])
+	return b.String()
+}
+
+// number returns the number of the footnote marked by a symbol or a number in brackets, if there is such a footnote
+func (m *footnoteMarkers) number(marker string) (int, bool) {
+	number := 0
+	switch {
+	case m.symbols[marker] > 0:
+		number = m.symbols[marker]
+	case strings.HasPrefix(marker, "(") && strings.HasSuffix(marker, ")"):
+		number, _ = strconv.Atoi(marker[1 : len(marker)-1])
+	case symbolPattern.MatchString(marker) && len(m.symbols) == 0:
+		// without symbols at the start of the footnotes, use the conventional order, or the number of asterisks
+		number = strings.Count(marker, "*")
+		for i, symbol := range footnoteSymbols {
+			if marker == symbol {
+				number = i + 1
+			}
+		}
+	}
+	return number, number >= 1 && number <= m.count
+}
+
+// isMarkerPosition returns true if the text at value[start:end] follows a word without a space, or ends the value,
+// and isn't followed by more of the word
+func isMarkerPosition(value string, start int, end int) bool {
+	next, _ := utf8.DecodeRuneInString(value[end:])
+	if end < len(value) && (unicode.IsLetter(next) || unicode.IsDigit(next)) {
+		return false
+	}
+	previous, _ := utf8.DecodeLastRuneInString(value[:start])
+	if start > 0 && !unicode.IsSpace(previous) {
+		return true
+	}
+	return start > 0 && len(strings.TrimSpace(tagPattern.ReplaceAllLiteralString(value[end:], ""))) == 0
+}
+
+// insideTag returns true if the position is within one of the tags
+func insideTag(tags [][]int, position int) bool {
+	for _, tag := range tags {
+		if position >= tag[0] && position < tag[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// handsontableClass is the class of the tables created by Handsontable
+const handsontableClass = "htCore"
+
+// maxColspan and maxRowspan are the largest spans of a cell, as in the html table processing model
+const (
+	maxColspan = 1000
+	maxRowspan = 65534
+)
+
+// cellGrid is the grid of slots of a table. Each cell covers the slots of the rows and columns it spans, and is held
+// by the slot at its top left corner.
+type cellGrid struct {
+	slots   [][]*html.Node
+	covered [][]bool // true for each slot covered by a cell, whether or not it holds the cell
+}
+
+// merge is the colspan and rowspan given for a cell, each 0 if not given
+type merge struct {
+	colspan int
+	rowspan int
+}
+
+// getCells returns the cells of each row of the table in the chosen layout, and the layout used - the same as the
+// requested layout unless that was auto. The first row and column are removed if requested. Every row has the same
+// number of cells: slots covered by a merged cell in the standard layout, and missing cells at the end of a short row,
+// are nil. An error is returned if the grid of cells would exceed the limits.
+func getCells(table *html.Node, layout string, ignoreFirstRow bool, ignoreFirstColumn bool, limits models.Limits) ([][]*html.Node, string, error) {
+	rows := h.FindAllNodes(table, atom.Tr)
+	if layout != models.CellLayoutHandsontable && layout != models.CellLayoutStandard {
+		var err error
+		if layout, err = detectCellLayout(table, rows, limits); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var slots [][]*html.Node
+	if layout == models.CellLayoutStandard {
+		grid, err := standardGrid(rows, limits)
+		if err != nil {
+			return nil, "", err
+		}
+		slots = grid.slots
+	} else {
+		var err error
+		if slots, err = handsontableGrid(rows, limits); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if ignoreFirstRow && len(slots) > 0 {
+		slots = slots[1:]
+	}
+	if ignoreFirstColumn {
+		for r, row := range slots {
+			if len(row) > 0 {
+				slots[r] = row[1:]
+			}
+		}
+	}
+	return slots, layout, nil
+}
+
+// handsontableGrid returns the cells of each row in the order they occur: each row contains every cell, including
+// those hidden by a merged cell, as in Handsontable. Short rows are filled with nil.
+func handsontableGrid(rows []*html.Node, limits models.Limits) ([][]*html.Node, error) {
+	slots := make([][]*html.Node, len(rows))
+	width := 0
+	for r, row := range rows {
+		slots[r] = h.FindAllNodes(row, atom.Td, atom.Th)
+		width = max(width, len(slots[r]))
+	}
+	if err := limits.CheckTableSize(len(rows), width); err != nil {
+		return nil, err
+	}
+	for r, row := range slots {
+		for len(row) < width {
+			row = append(row, nil)
+		}
+		slots[r] = row
+	}
+	return slots, nil
+}
+
+// standardGrid places the cells of each row in the grid as described by the html table processing model: a cell is
+// placed in the first slot of its row that isn't covered by a cell in a row above, and covers the slots of the rows
+// and columns it spans. Only the origin of each cell holds the cell - every other slot it covers is nil. An error is
+// returned as soon as the grid would exceed the limits, before it grows.
+func standardGrid(rows []*html.Node, limits models.Limits) (*cellGrid, error) {
+	grid := &cellGrid{slots: make([][]*html.Node, len(rows)), covered: make([][]bool, len(rows))}
+	covered := grid.covered
+	width := 0
+	for r, row := range rows {
+		c := 0
+		for _, cell := range h.FindAllNodes(row, atom.Td, atom.Th) {
+			for c < len(covered[r]) && covered[r][c] {
+				c++
+			}
+			colspan, rowspan := spans(cell, len(rows)-r)
+			if err := limits.CheckTableSize(len(rows), c+colspan); err != nil {
+				return nil, err
+			}
+			for y := r; y < r+rowspan; y++ {
+				for x := c; x < c+colspan; x++ {
+					covered[y] = growBools(covered[y], x+1)
+					covered[y][x] = true
+				}
+			}
+			grid.slots[r] = growNodes(grid.slots[r], c+1)
+			grid.slots[r][c] = cell
+			c += colspan
+			width = max(width, c)
+		}
+	}
+	for r := range grid.slots {
+		grid.slots[r] = growNodes(grid.slots[r], width)
+	}
+	return grid, nil
+}
+
+// detectCellLayout decides whether the rows of a table contain every cell (handsontable) or omit the cells covered by
+// merged cells (standard). The layout that gives every row the same number of cells is chosen. If both do, as when
+// nothing is merged, cells hidden with display: none or the class of a Handsontable table mark it as handsontable.
+func detectCellLayout(table *html.Node, rows []*html.Node, limits models.Limits) (string, error) {
+	handsontable, standard := true, true
+	handsontableWidth := -1
+	for _, row := range rows {
+		n := len(h.FindAllNodes(row, atom.Td, atom.Th))
+		if handsontableWidth >= 0 && n != handsontableWidth {
+			handsontable = false
+		}
+		handsontableWidth = n
+	}
+	grid, err := standardGrid(rows, limits)
+	if err != nil {
+		return "", err
+	}
+	standardWidth := -1
+	for r := range grid.slots {
+		n := grid.coveredSlots(r)
+		if standardWidth >= 0 && n != standardWidth {
+			standard = false
+		}
+		standardWidth = n
+	}
+
+	switch {
+	case handsontable && !standard:
+		return models.CellLayoutHandsontable, nil
+	case standard && !handsontable:
+		return models.CellLayoutStandard, nil
+	case hasClass(table, handsontableClass) || hasHiddenCell(table):
+		return models.CellLayoutHandsontable, nil
+	default:
+		return models.CellLayoutStandard, nil
+	}
+}
+
+// coveredSlots returns the number of slots of row r that are covered by a cell
+func (g *cellGrid) coveredSlots(r int) int {
+	count := 0
+	for _, covered := range g.covered[r] {
+		if covered {
+			count++
+		}
+	}
+	return count
+}
+
+// spans returns the colspan and rowspan of a cell, which are at least 1 and at most maxColspan and maxRowspan. A rowspan
+// of 0 spans the remaining rows, as does a rowspan greater than the remaining rows.
+func spans(cell *html.Node, remainingRows int) (int, int) {
+	colspan, err := strconv.Atoi(h.GetAttribute(cell, "colspan"))
+	if err != nil || colspan < 1 {
+		colspan = 1
+	}
+	colspan = min(colspan, maxColspan)
+	rowspan, err := strconv.Atoi(h.GetAttribute(cell, "rowspan"))
+	if err != nil || rowspan < 0 {
+		rowspan = 1
+	}
+	if rowspan == 0 || rowspan > remainingRows {
+		rowspan = remainingRows
+	}
+	rowspan = min(rowspan, maxRowspan)
+	return colspan, max(rowspan, 1)
+}
+
+// parseMerges returns the colspan and rowspan given by the attributes of each cell, where a rowspan of 0, or one
+// beyond the end of the table, spans the remaining rows
+func parseMerges(cells [][]*html.Node) [][]merge {
+	merges := make([][]merge, len(cells))
+	for r, row := range cells {
+		merges[r] = make([]merge, len(row))
+		for c, cell := range row {
+			if cell == nil {
+				continue
+			}
+			colspan, rowspan := spans(cell, len(cells)-r)
+			if n, _ := strconv.Atoi(h.GetAttribute(cell, "colspan")); n > 0 {
+				merges[r][c].colspan = colspan
+			}
+			if n, err := strconv.Atoi(h.GetAttribute(cell, "rowspan")); err == nil && n >= 0 {
+				merges[r][c].rowspan = rowspan
+			}
+		}
+	}
+	return merges
+}
+
+// hasClass returns true if the node has the class
+func hasClass(node *html.Node, class string) bool {
+	for _, c := range strings.Fields(h.GetAttribute(node, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// hasHiddenCell returns true if any cell of the table is hidden with display: none, as Handsontable hides the cells
+// covered by a merged cell
+func hasHiddenCell(table *html.Node) bool {
+	for _, cell := range h.FindAllNodes(table, atom.Td, atom.Th) {
+		style := strings.ReplaceAll(h.GetAttribute(cell, "style"), " ", "")
+		if strings.Contains(style, "display:none") {
+			return true
+		}
+	}
+	return false
+}
+
+// growNodes extends the slice with nil to at least length n
+func growNodes(nodes []*html.Node, n int) []*html.Node {
+	for len(nodes) < n {
+		nodes = append(nodes, nil)
+	}
+	return nodes
+}
+
+// growBools extends the slice with false to at least length n
+func growBools(values []bool, n int) []bool {
+	for len(values) < n {
+		values = append(values, false)
+	}
+	return values
+}
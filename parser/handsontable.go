@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"context"
+	"strconv"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"golang.org/x/net/html"
+)
+
+var (
+	// handsontableAlignments maps the alignment classes of Handsontable to an Alignment
+	handsontableAlignments = map[string]string{
+		"htLeft":    models.AlignLeft,
+		"htCenter":  models.AlignCenter,
+		"htRight":   models.AlignRight,
+		"htJustify": models.AlignJustify,
+	}
+	// handsontableVerticalAlignments maps the vertical alignment classes of Handsontable to an Alignment
+	handsontableVerticalAlignments = map[string]string{
+		"htTop":    models.AlignTop,
+		"htMiddle": models.AlignMiddle,
+		"htBottom": models.AlignBottom,
+	}
+)
+
+// ParseHandsontable converts the state of a Handsontable editor in the request into the json used to render the table,
+// along with the preview html
+func ParseHandsontable(ctx context.Context, request *models.ParseRequest) ([]byte, error) {
+	if request.Handsontable == nil || len(request.Handsontable.Data) == 0 {
+		return nil, models.NewMissingFieldsError([]string{"handsontable.data"})
+	}
+	return createResponse(ctx, func() (*parseModel, error) { return createHandsontableModel(request), nil })
+}
+
+// createHandsontableModel creates a model from the state of a Handsontable editor: its data, merged cells, the classes
+// of its cells and the sizes of its rows and columns
+func createHandsontableModel(request *models.ParseRequest) *parseModel {
+	state := request.Handsontable
+	model := parseModel{
+		request:    request,
+		cellLayout: models.CellLayoutHandsontable,
+		headerRows: request.HeaderRows,
+		headerCols: request.HeaderCols,
+		title:      request.Title,
+		footnotes:  parseFootnotes(request.Footnotes),
+		alignMap:   handsontableAlignments,
+		valignMap:  handsontableVerticalAlignments,
+	}
+	model.markers, model.footnotes = newFootnoteMarkers(model.footnotes)
+
+	classNames := make(map[[2]int]string)
+	for _, meta := range state.Cell {
+		classNames[[2]int{meta.Row, meta.Col}] = meta.ClassName
+	}
+	numColumns := 0
+	for _, row := range state.Data {
+		numColumns = max(numColumns, len(row))
+	}
+	model.cells = make([][]*html.Node, len(state.Data))
+	model.merges = make([][]merge, len(state.Data))
+	for r, row := range state.Data {
+		model.cells[r] = make([]*html.Node, numColumns)
+		model.merges[r] = make([]merge, numColumns)
+		for c := range model.cells[r] {
+			value := ""
+			if c < len(row) {
+				value = string(row[c])
+			}
+			model.cells[r][c] = handsontableCell(value, classNames[[2]int{r, c}])
+		}
+	}
+	model.mergeHandsontableCells(state.MergeCells)
+	model.alignments = parseAlignments(&model)
+
+	model.widths = make([][]string, max(numColumns, len(state.ColWidths.Each)))
+	for c := range model.widths {
+		model.widths[c] = pixels(state.ColWidths.Size(c))
+	}
+	model.heights = make([][]string, len(model.cells))
+	for r := range model.heights {
+		model.heights[r] = pixels(state.RowHeights.Size(r))
+	}
+	return &model
+}
+
+// mergeHandsontableCells sets the span of each merged cell, limited to the edges of the table, and removes the cells
+// that it covers. A merged cell whose origin is covered by another is ignored.
+func (model *parseModel) mergeHandsontableCells(merges []models.HandsontableMerge) {
+	for _, m := range merges {
+		if m.Row < 0 || m.Row >= len(model.cells) || m.Col < 0 || m.Col >= len(model.cells[m.Row]) || model.cells[m.Row][m.Col] == nil {
+			continue
+		}
+		rowspan := min(max(m.Rowspan, 1), len(model.cells)-m.Row)
+		colspan := min(max(m.Colspan, 1), len(model.cells[m.Row])-m.Col)
+		if rowspan == 1 && colspan == 1 {
+			continue
+		}
+		// as with the attributes of html, a span of 1 needn't be given
+		if colspan > 1 {
+			model.merges[m.Row][m.Col].colspan = colspan
+		}
+		if rowspan > 1 {
+			model.merges[m.Row][m.Col].rowspan = rowspan
+		}
+		for r := m.Row; r < m.Row+rowspan; r++ {
+			for c := m.Col; c < m.Col+colspan; c++ {
+				if r != m.Row || c != m.Col {
+					model.cells[r][c] = nil
+				}
+			}
+		}
+	}
+}
+
+// handsontableCell creates a td element with the class name and the value of a cell, in which \n is a line break
+func handsontableCell(value string, className string) *html.Node {
+	cell := newCell(value)
+	if len(className) > 0 {
+		h.AddAttribute(cell, "class", className)
+	}
+	return cell
+}
+
+// pixels returns a length in pixels, or nothing for a size of 0
+func pixels(size float64) []string {
+	if size <= 0 {
+		return nil
+	}
+	return []string{strconv.FormatFloat(size, 'f', -1, 64) + "px"}
+}
@@ -0,0 +1,122 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// handsontableState is the state of an editor showing the same table as handsontableHTML
+const handsontableState = `{
+	"data": [["", "Sales", null], ["Year", "Q1", "Q2"], [2016, 10.5, "11"], ["2017", "12", "line 1\nline 2"]],
+	"mergeCells": [{"row": 0, "col": 1, "rowspan": 1, "colspan": 2}],
+	"cell": [{"row": 2, "col": 1, "className": "htRight htTop"}, {"row": 2, "col": 2, "className": "htRight"}, {"row": 3, "col": 1, "className": "htRight"}, {"row": 3, "col": 2, "className": "htRight"}],
+	"colWidths": [80, null, 120],
+	"rowHeights": 40
+}`
+
+// handsontableHTML is the html that Handsontable renders for handsontableState, with the letter and number headers
+const handsontableHTML = `<table class="htCore">
+	<colgroup><col style="width: 50px"><col style="width: 80px"><col><col style="width: 120px"></colgroup>
+	<thead><tr><th></th><th>A</th><th>B</th><th>C</th></tr></thead>
+	<tbody>
+		<tr style="height: 40px"><th>1</th><td></td><td colspan="2">Sales</td><td style="display: none"></td></tr>
+		<tr style="height: 40px"><th>2</th><td>Year</td><td>Q1</td><td>Q2</td></tr>
+		<tr style="height: 40px"><th>3</th><td>2016</td><td class="htRight htTop">10.5</td><td class="htRight">11</td></tr>
+		<tr style="height: 40px"><th>4</th><td>2017</td><td class="htRight">12</td><td class="htRight">line 1<br>line 2</td></tr>
+	</tbody>
+</table>`
+
+func TestParseHandsontable(t *testing.T) {
+
+	Convey("ParseHandsontable should produce the same table and preview as parsing the html rendered by Handsontable", t, func() {
+		request := createHandsontableRequest(handsontableState)
+		response := invokeParseHandsontable(request)
+
+		htmlRequest := createHandsontableRequest(`{"data": [[""]]}`)
+		htmlRequest.Handsontable = nil
+		htmlRequest.TableHTML = handsontableHTML
+		htmlRequest.IgnoreFirstRow = true
+		htmlRequest.IgnoreFirstColumn = true
+		htmlRequest.AlignmentClasses = models.ParseAlignments{
+			Top: "htTop", Middle: "htMiddle", Bottom: "htBottom", Left: "htLeft", Right: "htRight", Center: "htCenter", Justify: "htJustify",
+		}
+		expected := invokeParseHTMLWithRequest(htmlRequest)
+
+		So(response.JSON.Data, ShouldResemble, expected.JSON.Data)
+		So(response.JSON.ColumnFormats, ShouldResemble, expected.JSON.ColumnFormats)
+		So(response.JSON.RowFormats, ShouldResemble, expected.JSON.RowFormats)
+		So(response.JSON.CellFormats, ShouldResemble, expected.JSON.CellFormats)
+		So(response.PreviewHTML, ShouldEqual, expected.PreviewHTML)
+		So(response.CellLayout, ShouldEqual, models.CellLayoutHandsontable)
+	})
+
+	Convey("ParseHandsontable should use the exact merges, alignments and sizes of the editor", t, func() {
+		response := invokeParseHandsontable(createHandsontableRequest(handsontableState))
+
+		So(response.JSON.Data, ShouldResemble, [][]string{
+			{"", "Sales", ""},
+			{"Year", "Q1", "Q2"},
+			{"2016", "10.5", "11"},
+			{"2017", "12", "line 1\nline 2"},
+		})
+		So(response.JSON.CellFormats, ShouldContain, models.CellFormat{Row: 0, Column: 1, Colspan: 2})
+		So(response.JSON.CellFormats, ShouldContain, models.CellFormat{Row: 2, Column: 1, Align: models.AlignRight, VerticalAlign: models.AlignTop})
+		So(response.JSON.ColumnFormats, ShouldContain, models.ColumnFormat{Column: 0, Width: "5em"})
+		So(response.JSON.ColumnFormats, ShouldContain, models.ColumnFormat{Column: 2, Width: "7.5em"})
+		So(response.JSON.RowFormats, ShouldHaveLength, 4)
+		So(response.JSON.RowFormats[0].Height, ShouldEqual, "2.5em")
+	})
+
+	Convey("ParseHandsontable should limit merged cells to the edges of the table", t, func() {
+		request := createHandsontableRequest(`{
+			"data": [["a", "b"], ["c", "d"]],
+			"mergeCells": [{"row": 0, "col": 1, "rowspan": 5, "colspan": 5}, {"row": 1, "col": 1, "rowspan": 1, "colspan": 1}, {"row": 9, "col": 0, "rowspan": 2, "colspan": 1}]
+		}`)
+		response := invokeParseHandsontable(request)
+
+		So(response.JSON.Data, ShouldResemble, [][]string{{"a", "b"}, {"c", ""}})
+		So(response.JSON.CellFormats, ShouldResemble, []models.CellFormat{{Row: 0, Column: 1, Rowspan: 2}})
+	})
+
+	Convey("ParseHandsontable should accept mergeCells set to true, and a single size for every column", t, func() {
+		request := createHandsontableRequest(`{"data": [["a", "b"]], "mergeCells": true, "colWidths": 160}`)
+		response := invokeParseHandsontable(request)
+
+		So(response.JSON.CellFormats, ShouldBeEmpty)
+		So(response.JSON.ColumnFormats, ShouldResemble, []models.ColumnFormat{{Column: 0, Width: "10em"}, {Column: 1, Width: "10em"}})
+	})
+
+	Convey("ParseHandsontable should return an error when there is no data", t, func() {
+		request := createHandsontableRequest(`{"data": []}`)
+		_, err := parser.ParseHandsontable(mockContext, request)
+
+		So(err, ShouldNotBeNil)
+		So(err.(*models.Error).Code, ShouldEqual, models.CodeMissingFields)
+	})
+}
+
+func createHandsontableRequest(state string) *models.ParseRequest {
+	request := models.ParseRequest{
+		Filename:       "myFilename",
+		Title:          "myTitle",
+		HeaderRows:     2,
+		CellSizeUnits:  "em",
+		SingleEmHeight: 16,
+		Footnotes:      []string{"Note0"},
+	}
+	So(json.Unmarshal([]byte(state), &request.Handsontable), ShouldBeNil)
+	return &request
+}
+
+func invokeParseHandsontable(request *models.ParseRequest) *parser.ResponseModel {
+	resultBytes, err := parser.ParseHandsontable(mockContext, request)
+	So(err, ShouldBeNil)
+
+	result := parser.ResponseModel{}
+	So(json.Unmarshal(resultBytes, &result), ShouldBeNil)
+	return &result
+}
@@ -6,7 +6,6 @@ import (
 
 	"bufio"
 	"bytes"
-	"errors"
 	"strings"
 
 	"fmt"
@@ -16,6 +15,7 @@ import (
 	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
 	"github.com/ONSdigital/dp-table-renderer/models"
 	"github.com/ONSdigital/dp-table-renderer/renderer"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
 	"github.com/ONSdigital/log.go/v2/log"
 
 	"sort"
@@ -26,23 +26,37 @@ import (
 
 // parseModel contains values calculated from the parse request that are used to create the ResponseModel
 type parseModel struct {
-	request       *models.ParseRequest
-	tableNode     *html.Node
-	cells         [][]*html.Node    // all cells we want to treat as data
-	rowClasses    []map[string]int  // all classes defined in a row, keyed by the row index, with a count of the number of cells having that class
-	columnClasses []map[string]int  // all classes defined in a column, keyed by the column index, with a count of the number of cells having that class
-	alignMap      map[string]string // a map of the classes used for alignment in the input html to the correct Alignment values
-	valignMap     map[string]string // a map of the classes used for vertical alignment in the input html to the correct Alignment values
+	request    *models.ParseRequest
+	tableNode  *html.Node
+	cells      [][]*html.Node    // all cells we want to treat as data, with nil for a slot covered by a merged cell
+	cellLayout string            // the layout of the cells in the html, handsontable or standard
+	rows       []*html.Node      // the tr element of each row of cells
+	headerRows int               // the number of heading rows, from the request or inferred from the html
+	headerCols int               // the number of heading columns, from the request or inferred from the html
+	totalRows  []int             // the indexes of rows of totals, inferred from the html
+	title      string            // the title, from the request or else the caption or neighbouring heading of the table
+	footnotes  []string          // the footnotes, from the request or else the list following the table
+	markers    *footnoteMarkers  // converts the markers of footnotes in the cells to references
+	inferred   *Inferred         // what was inferred from the html, if requested
+	cols       []*html.Node      // the col (or colgroup) element of each column
+	alignments [][]alignment     // the alignment of each cell, from its classes, style or attributes, or those of its row or column
+	merges     [][]merge         // the colspan and rowspan given for each cell
+	widths     [][]string        // the widths given for each column, in order of precedence
+	heights    [][]string        // the heights given for each row, in order of precedence
+	alignMap   map[string]string // a map of the classes used for alignment in the input html to the correct Alignment values
+	valignMap  map[string]string // a map of the classes used for vertical alignment in the input html to the correct Alignment values
 }
 
 // ResponseModel defines the format of the json response contained in the bytes returned from ParseHTML
 type ResponseModel struct {
 	JSON        models.RenderRequest `json:"render_json"`
 	PreviewHTML string               `json:"preview_html"`
+	CellLayout  string               `json:"cell_layout"`        // the layout of the cells in the html: handsontable or standard
+	Inferred    *Inferred            `json:"inferred,omitempty"` // the structure inferred from the html, if requested
+	Warnings    []models.Warning     `json:"warnings,omitempty"` // problems with the parsed table, such as unused footnotes
 }
 
 var (
-	widthStylePattern          = regexp.MustCompile(`width: *[0-9]+[^;]+`)
 	widthTrailingZeroesPattern = regexp.MustCompile(`\.?0+(%|em)`)
 	tableType                  = "table"
 	tableVersion               = "2"
@@ -51,16 +65,29 @@ var (
 // ParseHTML parses the html table in the request and generates correctly formatted JSON
 func ParseHTML(ctx context.Context, request *models.ParseRequest) ([]byte, error) {
 
-	sourceTable, err := parseTableToNode(request.TableHTML)
+	sourceTable, err := parseTableToNode(request.TableHTML, request.TableSelector)
 	if err != nil {
 		log.Error(ctx, "Unable to parse TableHTML to table element", err)
 		return nil, err
 	}
 
-	model := createParseModel(request, sourceTable)
+	return createResponse(ctx, func() (*parseModel, error) { return createParseModel(request, sourceTable) })
+}
+
+// createResponse creates a model with newModel, then the json used to render the table that it describes, and renders
+// the preview html
+func createResponse(ctx context.Context, newModel func() (*parseModel, error)) ([]byte, error) {
+	_, span := tracing.StartSpan(ctx, "createModel")
+	model, err := newModel()
+	if err != nil {
+		tracing.EndSpan(span, err)
+		log.Error(ctx, "Unable to create the model of the table", err)
+		return nil, err
+	}
+	request := model.request
 	requestJSON := &models.RenderRequest{
 		Filename:            request.Filename,
-		Title:               request.Title,
+		Title:               model.title,
 		Subtitle:            request.Subtitle,
 		Source:              request.Source,
 		Units:               request.Units,
@@ -69,7 +96,7 @@ func ParseHTML(ctx context.Context, request *models.ParseRequest) ([]byte, error
 		TableVersion:        tableVersion,
 	}
 
-	rowFormats := createRowFormats(model)
+	rowFormats := createRowFormats(ctx, model)
 	requestJSON.RowFormats = convertRowFormatsToSlice(rowFormats)
 
 	colFormats := createColumnFormats(ctx, model)
@@ -77,49 +104,73 @@ func ParseHTML(ctx context.Context, request *models.ParseRequest) ([]byte, error
 
 	requestJSON.CellFormats = createCellFormats(model, rowFormats, colFormats)
 
-	requestJSON.Data = parseData(model.cells)
+	requestJSON.Data = parseData(model)
 
-	requestJSON.Footnotes = parseFootnotes(request.Footnotes)
+	requestJSON.Footnotes = parseFootnotes(model.footnotes)
 
-	previewHTML, err := renderer.RenderHTML(ctx, requestJSON)
+	span.SetAttributes(tracing.TableAttributes(requestJSON)...)
+	span.End()
+
+	renderCtx, renderSpan := tracing.StartSpan(ctx, "render", tracing.Format.String("html"))
+	previewHTML, err := renderer.RenderHTML(renderCtx, requestJSON)
+	tracing.EndSpan(renderSpan, err)
 	if err != nil {
-		log.Error(ctx, "Unable to render preview HTMLt", err)
+		log.Error(ctx, "Unable to render preview HTML", err)
 		return nil, err
 	}
-	response := ResponseModel{JSON: *requestJSON, PreviewHTML: string(previewHTML)}
+	response := ResponseModel{JSON: *requestJSON, PreviewHTML: string(previewHTML), CellLayout: model.cellLayout, Inferred: model.inferred,
+		Warnings: requestJSON.CheckFootnotes()}
 
 	return marshalResponse(response)
 }
 
-// parseTableToNode parses a string of html and returns the single table node, or an error if the html doesn't contain a single table
-func parseTableToNode(tableHTML string) (*html.Node, error) {
-	nodes, err := html.ParseFragment(strings.NewReader(tableHTML), &html.Node{
-		Type:     html.ElementNode,
-		Data:     "body",
-		DataAtom: atom.Body,
-	})
+// parseTableToNode parses a string of html, which may be a complete document or a fragment, and returns the table node
+// chosen by the selector, or an error if there is no such table
+func parseTableToNode(tableHTML string, selector string) (*html.Node, error) {
+	doc, err := parseDocument(tableHTML)
 	if err != nil {
 		return nil, err
 	}
-	if len(nodes) != 1 {
-		return nil, errors.New("table_html could not be parsed into a single element")
-	}
-	if nodes[0].DataAtom != atom.Table {
-		return nil, errors.New("table_html could not be parsed into a table element")
-	}
-	return nodes[0], nil
+	return selectTable(doc, selector)
 }
 
-// createParseModel creates a model from the input request, extracting all properties need to define the output from the input html
-func createParseModel(request *models.ParseRequest, tableNode *html.Node) *parseModel {
+// createParseModel creates a model from the input request, extracting all properties need to define the output from the input html.
+// An error is returned if the table exceeds the limits of the request.
+func createParseModel(request *models.ParseRequest, tableNode *html.Node) (*parseModel, error) {
 	model := parseModel{
-		request:   request,
-		tableNode: tableNode,
+		request:    request,
+		tableNode:  tableNode,
+		headerRows: request.HeaderRows,
+		headerCols: request.HeaderCols,
+		title:      request.Title,
+		footnotes:  parseFootnotes(request.Footnotes),
+	}
+	if len(model.footnotes) == 0 {
+		model.footnotes = findNotes(tableNode)
+	}
+	model.markers, model.footnotes = newFootnoteMarkers(model.footnotes)
+	if len(model.title) == 0 {
+		model.title = model.markers.convert(findTitle(tableNode))
 	}
 
-	model.cells = getCells(tableNode, request.IgnoreFirstRow, request.IgnoreFirstColumn)
-
-	model.rowClasses, model.columnClasses = parseRowAndColumnClasses(model.cells)
+	var err error
+	model.cells, model.cellLayout, err = getCells(tableNode, request.CellLayout, request.IgnoreFirstRow, request.IgnoreFirstColumn, request.Limits)
+	if err != nil {
+		return nil, err
+	}
+	model.rows = h.FindAllNodes(tableNode, atom.Tr)
+	if request.IgnoreFirstRow && len(model.rows) > 0 {
+		model.rows = model.rows[1:]
+	}
+	if model.cols, err = getColumnElements(tableNode, len(model.cells), request.Limits); err != nil {
+		return nil, err
+	}
+	if request.IgnoreFirstColumn && len(model.cols) > 0 {
+		model.cols = model.cols[1:]
+	}
+	if request.InferStructure {
+		model.inferred = inferStructure(&model)
+	}
 
 	model.alignMap = map[string]string{
 		request.AlignmentClasses.Left:    models.AlignLeft,
@@ -138,63 +189,31 @@ func createParseModel(request *models.ParseRequest, tableNode *html.Node) *parse
 	delete(model.alignMap, "")
 	delete(model.valignMap, "")
 
-	return &model
-}
+	model.alignments = parseAlignments(&model)
+	model.merges = parseMerges(model.cells)
+	model.widths, model.heights = parseLengths(&model)
 
-// getCells returns all td and th elements in the given (table) Node, in a 2d array with one array for each row in the table
-func getCells(table *html.Node, ignoreFirstRow bool, ignoreFirstColumn bool) [][]*html.Node {
-	var cells [][]*html.Node
-	rows := h.FindAllNodes(table, atom.Tr)
-	if ignoreFirstRow {
-		rows = rows[1:]
-	}
-	for _, row := range rows {
-		columns := h.FindAllNodes(row, atom.Td, atom.Th)
-		if ignoreFirstColumn {
-			columns = columns[1:]
-		}
-		cells = append(cells, columns)
-	}
-	return cells
+	return &model, nil
 }
 
-// parseData extracts the text content of each cell in the given array
-func parseData(cells [][]*html.Node) [][]string {
+// parseData extracts the content of each cell in the canonical inline markup. Markers of footnotes, such as a
+// superscript number or a symbol, are converted to references to the footnote if there is a footnote with that number.
+func parseData(model *parseModel) [][]string {
 	var data [][]string
-	for _, row := range cells {
+	for _, row := range model.cells {
 		var rowData []string
 		for _, cell := range row {
-			rowData = append(rowData, h.GetText(cell))
+			if cell == nil {
+				rowData = append(rowData, "")
+			} else {
+				rowData = append(rowData, model.markers.convert(h.InlineMarkup(cell, len(model.footnotes))))
+			}
 		}
 		data = append(data, rowData)
 	}
 	return data
 }
 
-// parseRowAndColumnClasses iterates through the cells, recording which classes occur in which rows/columns, and how often
-func parseRowAndColumnClasses(cells [][]*html.Node) ([]map[string]int, []map[string]int) {
-	columnClasses := make([]map[string]int, 0)
-	rowClasses := make([]map[string]int, len(cells))
-	for r, row := range cells {
-		rowClasses[r] = make(map[string]int)
-		for c, cell := range row {
-			if len(columnClasses) < c+1 {
-				columnClasses = append(columnClasses, make(map[string]int))
-			}
-			// get all the classes of this cell
-			classes := strings.Split(h.GetAttribute(cell, "class"), " ")
-			for _, class := range classes {
-				if len(class) > 0 {
-					// increment the counter for this class in row r and column c
-					rowClasses[r][class]++
-					columnClasses[c][class]++
-				}
-			}
-		}
-	}
-	return rowClasses, columnClasses
-}
-
 // parseFootnotes copies the given array, removing any empty strings
 func parseFootnotes(notes []string) []string {
 	footnotes := []string{}
@@ -206,36 +225,36 @@ func parseFootnotes(notes []string) []string {
 	return footnotes
 }
 
-// createColumnFormats uses the colgroup element to determine widths, and columnClasses to determine alignment
+// createColumnFormats uses the widths given for each column, and the alignment of its cells, to determine the format
+// of each column
 func createColumnFormats(ctx context.Context, model *parseModel) map[int]models.ColumnFormat {
-	numRows := len(model.cells)
 	colFormats := make(map[int]models.ColumnFormat)
-	// extract alignment from the column classes
-	for i, classes := range model.columnClasses {
-		for class, count := range classes {
-			if count == numRows && len(model.alignMap[class]) > 0 {
-				format := colFormats[i]
-				format.Align = model.alignMap[class]
-				colFormats[i] = format
-			}
-		}
+	numColumns := 0
+	if len(model.cells) > 0 {
+		numColumns = len(model.cells[0])
 	}
-	// extract widths from col elements - assume that col elements do not have a colspan
-	// TODO handle cases where col elements have colspan
-	colgroup := h.FindNodes(model.tableNode, atom.Col)
-	if len(colgroup) > 0 && model.request.IgnoreFirstColumn {
-		colgroup = colgroup[1:]
-	}
-	for i, col := range colgroup {
-		width := extractWidth(ctx, model, col)
-		if len(width) > 0 {
+	for i := 0; i < max(numColumns, len(model.widths)); i++ {
+		// a column is aligned if all its cells have the same alignment
+		if align := model.columnAlignment(i); len(align) > 0 {
 			format := colFormats[i]
-			format.Width = width
+			format.Align = align
 			colFormats[i] = format
 		}
+		// use the first width that can be extracted, e.g. that of the col element, or else of a cell in the column
+		if i >= len(model.widths) {
+			continue
+		}
+		for _, length := range model.widths[i] {
+			if width := extractWidth(ctx, model, length); len(width) > 0 {
+				format := colFormats[i]
+				format.Width = width
+				colFormats[i] = format
+				break
+			}
+		}
 	}
 	// assign headings
-	for i := 0; i < model.request.HeaderCols; i++ {
+	for i := 0; i < model.headerCols; i++ {
 		format := colFormats[i]
 		format.Heading = true
 		colFormats[i] = format
@@ -249,26 +268,101 @@ func createColumnFormats(ctx context.Context, model *parseModel) map[int]models.
 	return colFormats
 }
 
-// createRowFormats uses rowClasses to determine row vertical alignment
-func createRowFormats(model *parseModel) map[int]models.RowFormat {
+// getColumnElements returns the element that defines each column: a col element, repeated for each column it spans,
+// or a colgroup without col elements, repeated for each column it spans. A span is at most maxColspan, and an error is
+// returned if the columns of a table of the given number of rows would exceed the limits.
+func getColumnElements(table *html.Node, rows int, limits models.Limits) ([]*html.Node, error) {
+	var columns []*html.Node
+	for _, colgroup := range h.FindNodes(table, atom.Colgroup) {
+		cols := h.FindNodes(colgroup, atom.Col)
+		if len(cols) == 0 {
+			cols = []*html.Node{colgroup}
+		}
+		for _, col := range cols {
+			span, err := strconv.Atoi(h.GetAttribute(col, "span"))
+			if err != nil || span < 1 {
+				span = 1
+			}
+			span = min(span, maxColspan)
+			if err = limits.CheckTableSize(rows, len(columns)+span); err != nil {
+				return nil, err
+			}
+			for i := 0; i < span; i++ {
+				columns = append(columns, col)
+			}
+		}
+	}
+	return columns, nil
+}
+
+// columnAlignment returns the alignment shared by every cell in column c, or an empty string
+func (model *parseModel) columnAlignment(c int) string {
+	shared := ""
+	for r, row := range model.cells {
+		if c >= len(row) || row[c] == nil {
+			continue
+		}
+		align := model.alignments[r][c].align
+		if len(align) == 0 || (len(shared) > 0 && align != shared) {
+			return ""
+		}
+		shared = align
+	}
+	return shared
+}
+
+// rowVerticalAlignment returns the vertical alignment shared by every cell in row r, or an empty string
+func (model *parseModel) rowVerticalAlignment(r int) string {
+	shared := ""
+	for c, cell := range model.cells[r] {
+		if cell == nil {
+			continue
+		}
+		valign := model.alignments[r][c].valign
+		if len(valign) == 0 || (len(shared) > 0 && valign != shared) {
+			return ""
+		}
+		shared = valign
+	}
+	return shared
+}
+
+// createRowFormats uses the heights given for each row, and the vertical alignment of its cells, to determine the
+// format of each row
+func createRowFormats(ctx context.Context, model *parseModel) map[int]models.RowFormat {
 	rowFormats := make(map[int]models.RowFormat)
-	// extract alignment from the row classes
-	for i, classes := range model.rowClasses {
-		numColumns := len(model.cells[i])
-		for class, count := range classes {
-			if count == numColumns && len(model.valignMap[class]) > 0 {
+	for i := range model.cells {
+		// a row is aligned if all its cells have the same vertical alignment
+		if valign := model.rowVerticalAlignment(i); len(valign) > 0 {
+			format := rowFormats[i]
+			format.VerticalAlign = valign
+			rowFormats[i] = format
+		}
+		// use the first height that can be extracted, e.g. that of the tr element, or else of a cell in the row
+		if i >= len(model.heights) {
+			continue
+		}
+		for _, length := range model.heights[i] {
+			if height := extractLength(ctx, model, length, model.request.CurrentTableHeight); len(height) > 0 {
 				format := rowFormats[i]
-				format.VerticalAlign = model.valignMap[class]
+				format.Height = height
 				rowFormats[i] = format
+				break
 			}
 		}
 	}
 	// assign headings
-	for i := 0; i < model.request.HeaderRows; i++ {
+	for i := 0; i < model.headerRows; i++ {
 		format := rowFormats[i]
 		format.Heading = true
 		rowFormats[i] = format
 	}
+	// and totals
+	for _, i := range model.totalRows {
+		format := rowFormats[i]
+		format.Total = true
+		rowFormats[i] = format
+	}
 	return rowFormats
 }
 
@@ -278,8 +372,9 @@ func convertRowFormatsToSlice(rowFormats map[int]models.RowFormat) []models.RowF
 	for k := range rowFormats {
 		keys = append(keys, k)
 	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
 	slice := []models.RowFormat{}
-	for key := range keys {
+	for _, key := range keys {
 		format := rowFormats[key]
 		format.Row = key
 		slice = append(slice, format)
@@ -307,32 +402,27 @@ func createCellFormats(model *parseModel, rowFormats map[int]models.RowFormat, c
 	cellFormats := []models.CellFormat{}
 	for r, row := range model.cells {
 		for c, cell := range row {
+			if cell == nil {
+				continue
+			}
 			format := models.CellFormat{}
 			hasData := false
-			colspan, _ := strconv.Atoi(h.GetAttribute(cell, "colspan"))
-			if colspan > 0 {
-				format.Colspan = colspan
+			if merge := model.merges[r][c]; merge.colspan > 0 || merge.rowspan > 0 {
+				format.Colspan = merge.colspan
+				format.Rowspan = merge.rowspan
 				hasData = true
 			}
-			rowspan, _ := strconv.Atoi(h.GetAttribute(cell, "rowspan"))
-			if colspan > 0 {
-				format.Rowspan = rowspan
+			// specify vertical align if the cell has an alignment different to that of the row
+			valign := model.alignments[r][c].valign
+			if len(valign) > 0 && valign != rowFormats[r].VerticalAlign {
+				format.VerticalAlign = valign
 				hasData = true
 			}
-			classes := strings.Split(h.GetAttribute(cell, "class"), " ")
-			for _, class := range classes {
-				// specify vertical align if the cell has an alignment different to that of the row
-				valign := model.valignMap[class]
-				if len(valign) > 0 && valign != rowFormats[r].VerticalAlign {
-					format.VerticalAlign = valign
-					hasData = true
-				}
-				// specify align if the cell has an alignment different to that of the column
-				align := model.alignMap[class]
-				if len(align) > 0 && align != colFormats[c].Align {
-					format.Align = align
-					hasData = true
-				}
+			// specify align if the cell has an alignment different to that of the column
+			align := model.alignments[r][c].align
+			if len(align) > 0 && align != colFormats[c].Align {
+				format.Align = align
+				hasData = true
 			}
 			if hasData {
 				format.Column = c
@@ -344,41 +434,46 @@ func createCellFormats(model *parseModel, rowFormats map[int]models.RowFormat, c
 	return cellFormats
 }
 
-// extractWidth extracts width from the style property of the node
-func extractWidth(ctx context.Context, model *parseModel, node *html.Node) string {
+// extractWidth converts the width of a column, ignoring the column width to ignore
+func extractWidth(ctx context.Context, model *parseModel, width string) string {
+	if len(model.request.ColumnWidthToIgnore) > 0 {
+		width = strings.Replace(width, model.request.ColumnWidthToIgnore, "", -1)
+	}
+	return extractLength(ctx, model, width, model.request.CurrentTableWidth)
+}
+
+// extractLength converts a width or height in pixels to % of the given table size, or em, as requested
+func extractLength(ctx context.Context, model *parseModel, length string, tableSize int) string {
 	if len(model.request.CellSizeUnits) == 0 || model.request.CellSizeUnits == "auto" {
 		return ""
 	}
-	width := widthStylePattern.FindString(h.GetAttribute(node, "style"))
-	width = strings.Trim(strings.Replace(width, "width:", "", -1), " ")
-	width = strings.Replace(width, model.request.ColumnWidthToIgnore, "", -1)
-	// replace pixel width with % or em
-	if strings.HasSuffix(width, "px") {
+	// replace pixel length with % or em
+	if strings.HasSuffix(length, "px") {
 		switch units := model.request.CellSizeUnits; units {
 		case "%":
-			if model.request.CurrentTableWidth > 0 {
-				intWidth, err := strconv.Atoi(strings.Trim(width, "px"))
+			if tableSize > 0 {
+				pixels, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(length, "px")), 32)
 				if err == nil {
-					proportion := float32(intWidth) / float32(model.request.CurrentTableWidth)
-					width = fmt.Sprintf("%.1f%%", proportion*100.0)
+					proportion := float32(pixels) / float32(tableSize)
+					length = fmt.Sprintf("%.1f%%", proportion*100.0)
 				} else {
-					log.Error(ctx, "width not parsable as an integer", err, log.Data{"file_name": model.request.Filename, "width": width})
+					log.Error(ctx, "length not parsable as a number", err, log.Data{"file_name": model.request.Filename, "length": length})
 				}
 			}
 		case "em":
 			if model.request.SingleEmHeight > 0 {
-				intWidth, err := strconv.Atoi(strings.Trim(width, "px"))
+				pixels, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(length, "px")), 32)
 				if err == nil {
-					width = fmt.Sprintf("%.2fem", (float32(intWidth))/model.request.SingleEmHeight)
+					length = fmt.Sprintf("%.2fem", float32(pixels)/model.request.SingleEmHeight)
 				} else {
-					log.Error(ctx, "width not parsable as an integer", err, log.Data{"file_name": model.request.Filename, "width": width})
+					log.Error(ctx, "length not parsable as a number", err, log.Data{"file_name": model.request.Filename, "length": length})
 				}
 			}
 		}
 		// strip unwanted trailing zeroes in decimal places
-		width = widthTrailingZeroesPattern.ReplaceAllString(width, "$1")
+		length = widthTrailingZeroesPattern.ReplaceAllString(length, "$1")
 	}
-	return width
+	return length
 }
 
 // marshalResponse marshals the ResponseModel to json, turning off escaping of html
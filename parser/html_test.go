@@ -200,6 +200,69 @@ func TestParseHTML_Data(t *testing.T) {
 
 }
 
+func TestParseHTML_InlineMarkup(t *testing.T) {
+
+	Convey("ParseHTML should convert the content of cells to the canonical inline markup", t, func() {
+		request := &models.ParseRequest{
+			Filename:  "markup",
+			Footnotes: []string{"Provisional"},
+			TableHTML: "<table><tr>" +
+				"<td><p>First&nbsp;line</p><p>Second <b>line</b></p></td>" +
+				"<td>\n  1,200<sup>1</sup>\n</td>" +
+				"<td><span class=\"x\">m<sup>2</sup></span><br>per <em>person</em></td>" +
+				"</tr></table>",
+		}
+		response := invokeParseHTMLWithRequest(request)
+		So(response.JSON.Data, ShouldResemble, [][]string{{"First line\nSecond <strong>line</strong>", "1,200[1]", "m<sup>2</sup>\nper <em>person</em>"}})
+	})
+}
+
+func TestParseHTML_FootnoteMarkers(t *testing.T) {
+
+	Convey("ParseHTML should convert the common styles of footnote markers to references", t, func() {
+		request := &models.ParseRequest{
+			Filename: "markers",
+			TableHTML: "<table><tr>" +
+				"<td>Wales*</td><td>3.1<sup>†</sup></td><td>Scotland (3)</td><td>5.4[note 4]</td><td>England<sup>(5)</sup></td>" +
+				"</tr><tr>" +
+				"<td>*</td><td>Total (2019)</td><td>a * b</td><td>x(9)</td><td>NI<sup>note 2</sup></td>" +
+				"</tr></table>" +
+				"<ol><li>1. Estimated</li><li>Provisional</li><li>(3) Rounded</li><li>Note 4: Revised</li><li>Mid-year</li></ol>",
+		}
+		response := invokeParseHTMLWithRequest(request)
+		So(response.JSON.Data, ShouldResemble, [][]string{
+			{"Wales[1]", "3.1[2]", "Scotland [3]", "5.4[4]", "England[5]"},
+			{"*", "Total (2019)", "a * b", "x(9)", "NI[2]"},
+		})
+		So(response.JSON.Footnotes, ShouldResemble, []string{"Estimated", "Provisional", "Rounded", "Revised", "Mid-year"})
+		So(response.Warnings, ShouldBeEmpty)
+	})
+
+	Convey("ParseHTML should map symbols to the footnotes that start with them", t, func() {
+		request := &models.ParseRequest{
+			Filename:  "symbols",
+			TableHTML: "<table><tr><td>Wales†</td><td>3.1*</td><td>5.4‡</td></tr></table>",
+			Footnotes: []string{"* Estimated", "† Provisional"},
+		}
+		response := invokeParseHTMLWithRequest(request)
+		So(response.JSON.Data, ShouldResemble, [][]string{{"Wales[2]", "3.1[1]", "5.4‡"}})
+		So(response.JSON.Footnotes, ShouldResemble, []string{"Estimated", "Provisional"})
+	})
+
+	Convey("ParseHTML should warn of unused footnotes and dangling references", t, func() {
+		request := &models.ParseRequest{
+			Filename:  "warnings",
+			TableHTML: "<table><tr><td>Wales[3]</td></tr></table>",
+			Footnotes: []string{"Estimated"},
+		}
+		response := invokeParseHTMLWithRequest(request)
+		So(response.Warnings, ShouldHaveLength, 2)
+		So(response.Warnings[0].Code, ShouldEqual, models.WarningDanglingReference)
+		So(response.Warnings[0].Location, ShouldEqual, "data[0][0]")
+		So(response.Warnings[1].Code, ShouldEqual, models.WarningUnusedFootnote)
+	})
+}
+
 func TestParseHTML_ColumnFormats(t *testing.T) {
 
 	Convey("ParseHTML should create column formats with alignment, heading flags, width", t, func() {
@@ -389,6 +452,75 @@ func TestParseHTML_ColumnAlignment(t *testing.T) {
 
 }
 
+func TestParseHTML_InlineStyles(t *testing.T) {
+
+	Convey("ParseHTML should read alignment from inline styles and legacy attributes", t, func() {
+		response := invokeParseHTML("<table>"+
+			"<colgroup><col/><col align=\"right\"/><col/></colgroup>"+
+			"<tbody valign=\"top\">"+
+			"<tr><td>r0c0</td><td>r0c1</td><td style=\"text-align: center\">r0c2</td></tr>"+
+			"<tr style=\"vertical-align: bottom\"><td>r1c0</td><td>r1c1</td><td align=\"center\">r1c2</td></tr>"+
+			"<tr><td valign=\"middle\" style=\"text-align:left\">r2c0</td><td>r2c1</td><td STYLE=\"TEXT-ALIGN: Center !important\">r2c2</td></tr>"+
+			"</tbody>"+
+			"</table>", false, 0, 0)
+
+		columns := response.JSON.ColumnFormats
+		So(columns, ShouldHaveLength, 2)
+		So(columns[0].Column, ShouldEqual, 1)
+		So(columns[0].Align, ShouldEqual, models.AlignRight)
+		So(columns[1].Column, ShouldEqual, 2)
+		So(columns[1].Align, ShouldEqual, models.AlignCenter)
+
+		rows := response.JSON.RowFormats
+		So(rows, ShouldHaveLength, 2)
+		So(rows[0].VerticalAlign, ShouldEqual, models.AlignTop)
+		So(rows[1].VerticalAlign, ShouldEqual, models.AlignBottom)
+
+		format := getCellFormat(response.JSON.CellFormats, 2, 0)
+		So(format, ShouldNotBeNil)
+		So(format.Align, ShouldEqual, models.AlignLeft)
+		So(format.VerticalAlign, ShouldEqual, models.AlignMiddle)
+		format = getCellFormat(response.JSON.CellFormats, 2, 1)
+		So(format, ShouldNotBeNil)
+		So(format.Align, ShouldBeEmpty)
+		So(format.VerticalAlign, ShouldEqual, models.AlignTop)
+	})
+
+	Convey("ParseHTML should read widths from cells and heights from rows and cells, converting pixels", t, func() {
+		request := createParseRequest("<table>"+
+			"<tr style=\"height: 40px\"><td width=\"60\">r0c0</td><td colspan=\"2\" style=\"width: 300px\">r0c1</td></tr>"+
+			"<tr><td height=\"20\">r1c0</td><td style=\"width: 30px\">r1c1</td><td width=\"25%\">r1c2</td></tr>"+
+			"<tr><td>r2c0</td><td>r2c1</td><td>r2c2</td></tr>"+
+			"</table>", false, 0, 0)
+		request.CellSizeUnits = "em"
+		request.SingleEmHeight = 20
+		response := invokeParseHTMLWithRequest(request)
+
+		columns := response.JSON.ColumnFormats
+		So(columns, ShouldHaveLength, 3)
+		So(columns[0].Width, ShouldEqual, "3em")
+		So(columns[1].Width, ShouldEqual, "1.5em")
+		So(columns[2].Width, ShouldEqual, "25%")
+
+		rows := response.JSON.RowFormats
+		So(rows, ShouldHaveLength, 2)
+		So(rows[0].Height, ShouldEqual, "2em")
+		So(rows[1].Height, ShouldEqual, "1em")
+
+		Convey("Or to percent of the current table size", func() {
+			request.CellSizeUnits = "%"
+			request.CurrentTableWidth = 300
+			request.CurrentTableHeight = 200
+			response := invokeParseHTMLWithRequest(request)
+
+			So(response.JSON.ColumnFormats[0].Width, ShouldEqual, "20%")
+			So(response.JSON.RowFormats[0].Height, ShouldEqual, "20%")
+			So(response.JSON.RowFormats[1].Height, ShouldEqual, "10%")
+		})
+	})
+
+}
+
 func TestParseHTML_RowFormats(t *testing.T) {
 
 	Convey("ParseHTML should create row formats with alignment, heading flags", t, func() {
@@ -468,6 +600,206 @@ func TestParseHTML_CellFormats(t *testing.T) {
 
 }
 
+func TestParseHTML_CellLayout(t *testing.T) {
+
+	Convey("ParseHTML should place the cells of a standard html table on a grid, leaving covered cells empty", t, func() {
+		response := invokeParseHTML("<table>"+
+			"<tr><th rowspan=\"2\">Area</th><th colspan=\"2\">Population</th></tr>"+
+			"<tr><th>2020</th><th>2021</th></tr>"+
+			"<tr><td>Wales</td><td class=\"right\">3.1</td><td class=\"right\">3.2</td></tr>"+
+			"<tr><td rowspan=\"0\">Scotland</td><td class=\"right\">5.4</td><td class=\"right\">5.5</td></tr>"+
+			"<tr><td class=\"bottom right\">5.6</td><td class=\"bottom right\">5.7</td></tr>"+
+			"</table>", false, 2, 0)
+
+		So(response.CellLayout, ShouldEqual, models.CellLayoutStandard)
+		So(response.JSON.Data, ShouldResemble, [][]string{
+			{"Area", "Population", ""},
+			{"", "2020", "2021"},
+			{"Wales", "3.1", "3.2"},
+			{"Scotland", "5.4", "5.5"},
+			{"", "5.6", "5.7"},
+		})
+
+		format := getCellFormat(response.JSON.CellFormats, 0, 0)
+		So(format, ShouldNotBeNil)
+		So(format.Rowspan, ShouldEqual, 2)
+		format = getCellFormat(response.JSON.CellFormats, 0, 1)
+		So(format, ShouldNotBeNil)
+		So(format.Colspan, ShouldEqual, 2)
+		format = getCellFormat(response.JSON.CellFormats, 3, 0)
+		So(format, ShouldNotBeNil)
+		So(format.Rowspan, ShouldEqual, 2)
+
+		So(response.JSON.RowFormats, ShouldHaveLength, 3)
+		So(response.JSON.RowFormats[2].Row, ShouldEqual, 4)
+		So(response.JSON.RowFormats[2].VerticalAlign, ShouldEqual, models.AlignBottom)
+	})
+
+	Convey("ParseHTML should detect a handsontable layout", t, func() {
+		Convey("when every row contains every cell", func() {
+			response := invokeParseHTML("<table>"+
+				"<tr><td colspan=\"2\">a</td><td></td><td>b</td></tr>"+
+				"<tr><td>c</td><td>d</td><td>e</td></tr>"+
+				"</table>", false, 0, 0)
+			So(response.CellLayout, ShouldEqual, models.CellLayoutHandsontable)
+			So(response.JSON.Data[0], ShouldResemble, []string{"a", "", "b"})
+		})
+
+		Convey("when the table has the htCore class", func() {
+			response := invokeParseHTML("<table class=\"htCore\"><tr><td>a</td></tr></table>", false, 0, 0)
+			So(response.CellLayout, ShouldEqual, models.CellLayoutHandsontable)
+		})
+
+		Convey("when a cell is hidden", func() {
+			response := invokeParseHTML("<table><tr><td>a</td><td style=\"display: none\"></td></tr></table>", false, 0, 0)
+			So(response.CellLayout, ShouldEqual, models.CellLayoutHandsontable)
+		})
+	})
+
+	Convey("ParseHTML should use the requested cell layout", t, func() {
+		table := "<table>" +
+			"<tr><td rowspan=\"2\">a</td><td>b</td></tr>" +
+			"<tr><td>c</td></tr>" +
+			"</table>"
+
+		request := createParseRequest(table, false, 0, 0)
+		request.CellLayout = models.CellLayoutStandard
+		response := invokeParseHTMLWithRequest(request)
+		So(response.CellLayout, ShouldEqual, models.CellLayoutStandard)
+		So(response.JSON.Data, ShouldResemble, [][]string{{"a", "b"}, {"", "c"}})
+
+		request.CellLayout = models.CellLayoutHandsontable
+		response = invokeParseHTMLWithRequest(request)
+		So(response.CellLayout, ShouldEqual, models.CellLayoutHandsontable)
+		So(response.JSON.Data, ShouldResemble, [][]string{{"a", "b"}, {"c", ""}})
+	})
+
+	Convey("ParseHTML should limit the spans of a cell, and return an error before placing a table that exceeds the limits", t, func() {
+		table := "<table><tr><td colspan=\"2000000000\" rowspan=\"2000000000\">a</td></tr><tr><td>b</td></tr></table>"
+		request := createParseRequest(table, false, 0, 0)
+		request.CellLayout = models.CellLayoutStandard
+		response := invokeParseHTMLWithRequest(request)
+		So(response.JSON.Data, ShouldHaveLength, 2)
+		So(response.JSON.Data[0], ShouldHaveLength, 1001)
+		So(getCellFormat(response.JSON.CellFormats, 0, 0).Colspan, ShouldEqual, 1000)
+		So(getCellFormat(response.JSON.CellFormats, 0, 0).Rowspan, ShouldEqual, 2)
+
+		for _, layout := range []string{models.CellLayoutAuto, models.CellLayoutStandard, models.CellLayoutHandsontable} {
+			request := createParseRequest("<table><tr><td colspan=\"20\">a</td><td>b</td><td>c</td></tr></table>", false, 0, 0)
+			request.CellLayout = layout
+			request.Limits = models.Limits{Columns: 2}
+			_, err := parser.ParseHTML(mockContext, request)
+			So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+		}
+
+		request = createParseRequest(table, false, 0, 0)
+		request.Limits = models.Limits{Cells: 500}
+		_, err := parser.ListTables(mockContext, request)
+		So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+	})
+
+}
+
+func TestParseHTML_InferStructure(t *testing.T) {
+
+	table := "<table>" +
+		"<caption> Population by area </caption>" +
+		"<colgroup><col style=\"width: 10em\"/><col span=\"2\" style=\"width: 5em\"/></colgroup>" +
+		"<thead>" +
+		"<tr><td rowspan=\"2\"></td><th colspan=\"2\" scope=\"colgroup\">Population</th></tr>" +
+		"<tr><th scope=\"col\">2020</th><th scope=\"col\">2021</th></tr>" +
+		"</thead>" +
+		"<tbody>" +
+		"<tr><th scope=\"row\">Wales</th><td>3.1</td><td>3.2</td></tr>" +
+		"<tr><th scope=\"row\">Scotland</th><td>5.4</td><td>5.5</td></tr>" +
+		"</tbody>" +
+		"<tfoot><tr><td>Total</td><td>8.5</td><td>8.7</td></tr></tfoot>" +
+		"</table>"
+
+	Convey("ParseHTML should infer headings, totals and the title from the html", t, func() {
+		request := createParseRequest(table, false, 0, 0)
+		request.Title = ""
+		request.InferStructure = true
+		response := invokeParseHTMLWithRequest(request)
+
+		So(response.JSON.Title, ShouldEqual, "Population by area")
+		So(response.JSON.RowFormats, ShouldHaveLength, 3)
+		So(response.JSON.RowFormats[0].Heading, ShouldBeTrue)
+		So(response.JSON.RowFormats[1].Heading, ShouldBeTrue)
+		So(response.JSON.RowFormats[2].Row, ShouldEqual, 4)
+		So(response.JSON.RowFormats[2].Total, ShouldBeTrue)
+		So(response.JSON.ColumnFormats[0].Heading, ShouldBeTrue)
+		So(response.JSON.ColumnFormats[1].Heading, ShouldBeFalse)
+
+		So(response.Inferred, ShouldResemble, &parser.Inferred{
+			HeaderRows: 2,
+			HeaderCols: 1,
+			TotalRows:  []int{4},
+			Title:      "Population by area",
+		})
+		So(response.PreviewHTML, ShouldContainSubstring, "table__total-row")
+	})
+
+	Convey("ParseHTML should count rows of th cells as headings", t, func() {
+		request := createParseRequest("<table>"+
+			"<tr><th>Area</th><th>2020</th></tr>"+
+			"<tr><td>Wales</td><td>3.1</td></tr>"+
+			"</table>", false, 0, 0)
+		request.InferStructure = true
+		response := invokeParseHTMLWithRequest(request)
+
+		So(response.Inferred.HeaderRows, ShouldEqual, 1)
+		So(response.Inferred.HeaderCols, ShouldEqual, 0)
+		So(response.Inferred.Title, ShouldBeEmpty)
+	})
+
+	Convey("ParseHTML should prefer the fields of the request to those inferred", t, func() {
+		request := createParseRequest(table, false, 1, 2)
+		request.InferStructure = true
+		response := invokeParseHTMLWithRequest(request)
+
+		So(response.JSON.Title, ShouldEqual, "myTitle")
+		So(response.JSON.RowFormats[0].Heading, ShouldBeTrue)
+		So(response.JSON.RowFormats[1].Heading, ShouldBeFalse)
+		So(response.JSON.ColumnFormats[1].Heading, ShouldBeTrue)
+		So(response.Inferred, ShouldResemble, &parser.Inferred{TotalRows: []int{4}})
+	})
+
+	Convey("ParseHTML should not infer anything unless requested", t, func() {
+		request := createParseRequest(table, false, 0, 0)
+		response := invokeParseHTMLWithRequest(request)
+
+		So(response.Inferred, ShouldBeNil)
+		So(response.JSON.Title, ShouldEqual, "myTitle")
+		for _, format := range response.JSON.RowFormats {
+			So(format.Heading, ShouldBeFalse)
+			So(format.Total, ShouldBeFalse)
+		}
+	})
+
+	Convey("ParseHTML should repeat the width of a col for each column it spans", t, func() {
+		request := createParseRequest(table, false, 0, 0)
+		response := invokeParseHTMLWithRequest(request)
+
+		So(response.JSON.ColumnFormats, ShouldHaveLength, 3)
+		So(response.JSON.ColumnFormats[0].Width, ShouldEqual, "10em")
+		So(response.JSON.ColumnFormats[1].Width, ShouldEqual, "5em")
+		So(response.JSON.ColumnFormats[2].Width, ShouldEqual, "5em")
+	})
+
+	Convey("ParseHTML should limit the span of a col, and return an error if the cols exceed the limits", t, func() {
+		colTable := "<table><colgroup><col span=\"1500000000\" style=\"width: 5em\"></colgroup><tr><td>a</td></tr></table>"
+		response := invokeParseHTMLWithRequest(createParseRequest(colTable, false, 0, 0))
+		So(response.JSON.ColumnFormats, ShouldHaveLength, 1000)
+
+		request := createParseRequest(colTable, false, 0, 0)
+		request.Limits = models.Limits{Columns: 500}
+		_, err := parser.ParseHTML(mockContext, request)
+		So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+	})
+
+}
+
 func TestParseHTML_KeepHeadingsTogether(t *testing.T) {
 
 	Convey("ParseHTML should honour KeepHeadingsTogether", t, func() {
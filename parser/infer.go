@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Inferred reports the structure inferred from the semantics of the html, where the request didn't specify it.
+// A field is empty if nothing was inferred for it, or if the request specified it.
+type Inferred struct {
+	HeaderRows int      `json:"header_rows,omitempty"` // the number of heading rows, from thead and rows of th cells
+	HeaderCols int      `json:"header_cols,omitempty"` // the number of heading columns, from th cells with scope="row"
+	TotalRows  []int    `json:"total_rows,omitempty"`  // the indexes of the rows of totals, from tfoot
+	Title      string   `json:"title,omitempty"`       // the text of the caption, figcaption or nearest heading before the table
+	Footnotes  []string `json:"footnotes,omitempty"`   // the items of the list following the table
+}
+
+// inferStructure sets the headings and totals of the model from the html, unless they are given in the request, and
+// returns what was inferred
+func inferStructure(model *parseModel) *Inferred {
+	inferred := &Inferred{}
+
+	for r, row := range model.rows {
+		if inSection(row, atom.Tfoot) {
+			model.totalRows = append(model.totalRows, r)
+		}
+	}
+	inferred.TotalRows = model.totalRows
+
+	if model.headerRows == 0 {
+		model.headerRows = countHeadingRows(model)
+		inferred.HeaderRows = model.headerRows
+	}
+	if model.headerCols == 0 {
+		model.headerCols = countHeadingColumns(model)
+		inferred.HeaderCols = model.headerCols
+	}
+	// the title and footnotes are always taken from the html when not given, but are only reported here
+	if len(model.request.Title) == 0 {
+		inferred.Title = model.title
+	}
+	if len(parseFootnotes(model.request.Footnotes)) == 0 {
+		inferred.Footnotes = model.footnotes
+	}
+	return inferred
+}
+
+// countHeadingRows returns the number of rows at the top of the table that are in the thead, or contain only th cells
+func countHeadingRows(model *parseModel) int {
+	count := 0
+	for count < len(model.cells) && !model.isTotal(count) {
+		if !inSection(model.rows[count], atom.Thead) && !allHeadingCells(model.cells[count], "") {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// countHeadingColumns returns the number of columns at the left of the table in which every cell below the heading
+// rows, other than totals, is a th with a scope of row or rowgroup
+func countHeadingColumns(model *parseModel) int {
+	count := 0
+	for {
+		column := []*html.Node{}
+		for r := model.headerRows; r < len(model.cells); r++ {
+			if count < len(model.cells[r]) && !model.isTotal(r) {
+				column = append(column, model.cells[r][count])
+			}
+		}
+		if !allHeadingCells(column, "row") {
+			return count
+		}
+		count++
+	}
+}
+
+// allHeadingCells returns true if there is at least one cell, and every cell is a th - with a scope starting with the
+// given prefix, if any. Slots covered by a merged cell are ignored.
+func allHeadingCells(cells []*html.Node, scope string) bool {
+	found := false
+	for _, cell := range cells {
+		if cell == nil {
+			continue
+		}
+		if cell.DataAtom != atom.Th || !strings.HasPrefix(strings.ToLower(h.GetAttribute(cell, "scope")), scope) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// inSection returns true if the row is in a section (thead, tbody or tfoot) of the given type
+func inSection(row *html.Node, section atom.Atom) bool {
+	return row != nil && row.Parent != nil && row.Parent.DataAtom == section
+}
+
+// isTotal returns true if row r is a row of totals
+func (model *parseModel) isTotal(r int) bool {
+	for _, total := range model.totalRows {
+		if total == r {
+			return true
+		}
+	}
+	return false
+}
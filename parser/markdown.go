@@ -0,0 +1,269 @@
+package parser
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"golang.org/x/net/html"
+)
+
+var (
+	delimiterRowPattern       = regexp.MustCompile(`^\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?$`)
+	headingPattern            = regexp.MustCompile(`^ {0,3}#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+	footnoteDefinitionPattern = regexp.MustCompile(`^ {0,3}\[\^([^\]\s]+)]:\s*(.*)$`)
+	footnoteLabelPattern      = regexp.MustCompile(`\[\^([^\]\s]+)]`)
+	escapePattern             = regexp.MustCompile("\\\\([!-/:-@\\[-`{-~])")
+	codeSpanPattern           = regexp.MustCompile("(`+)(.*?[^`])(`+)")
+	autolinkPattern           = regexp.MustCompile(`<((?:https?://|mailto:)[^\s<>]+)>`)
+	inlineTagPattern          = regexp.MustCompile(`(?i)</?(?:a|strong|em|b|i|sup|sub|br)(?:\s[^<>]*)?/?>`)
+	linkPattern               = regexp.MustCompile(`\[([^\[\]]*)]\(\s*<?([^\s<>()]*)>?(?:\s+(?:"[^"]*"|'[^']*'))?\s*\)`)
+	strongPattern             = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|\b__(\S(?:.*?\S)?)__\b`)
+	emphasisPattern           = regexp.MustCompile(`\*(\S(?:.*?\S)?)\*|\b_(\S(?:.*?\S)?)_\b`)
+)
+
+// escapeBase is the first of the private use characters that stand in for characters escaped with a backslash
+const escapeBase = '\uE000'
+
+// markdownTable is a pipe table found in markdown, with the heading and footnotes around it
+type markdownTable struct {
+	rows      [][]string // the cells of each row, including the header row, as markdown
+	aligns    []string   // the alignment of each column given by the delimiter row
+	title     string     // the nearest heading above the table
+	footnotes []string   // the footnote definitions of the document, in order
+	labels    []string   // the label of each footnote definition
+}
+
+// ParseMarkdown converts the first pipe table in the markdown of the request into the json used to render the table,
+// along with the preview html
+func ParseMarkdown(ctx context.Context, request *models.ParseRequest) ([]byte, error) {
+	if len(strings.TrimSpace(request.TableMarkdown)) == 0 {
+		return nil, models.NewMissingFieldsError([]string{"table_markdown"})
+	}
+	table := findMarkdownTable(request.TableMarkdown)
+	if table == nil {
+		return nil, models.NewError(models.CodeTableNotFound, "table_markdown does not contain a pipe table", nil)
+	}
+	return createResponse(ctx, func() (*parseModel, error) { return createMarkdownModel(request, table) })
+}
+
+// createMarkdownModel creates a model from a pipe table. The header row is a heading, and columns without alignment
+// markers are aligned to the right if they contain numbers. The nearest heading above the table is the title, and the
+// footnote definitions of the document are the footnotes, unless they are given in the request. An error is returned if
+// the table exceeds the limits of the request.
+func createMarkdownModel(request *models.ParseRequest, table *markdownTable) (*parseModel, error) {
+	inferred := &Inferred{}
+	footnotes := parseFootnotes(request.Footnotes)
+	numbers := make(map[string]int)
+	if len(footnotes) == 0 {
+		footnotes = table.footnotes
+		inferred.Footnotes = table.footnotes
+		for i, label := range table.labels {
+			if _, found := numbers[label]; !found {
+				numbers[label] = i + 1
+			}
+		}
+	}
+
+	values := make([][]string, len(table.rows))
+	for r, row := range table.rows {
+		values[r] = make([]string, len(row))
+		for c, cell := range row {
+			values[r][c] = markdownInline(cell, numbers)
+		}
+	}
+	headerRows := request.HeaderRows
+	if headerRows == 0 {
+		headerRows = 1
+		inferred.HeaderRows = headerRows
+	}
+	aligns := inferColumnAlignments(append([]string(nil), table.aligns...), values, headerRows)
+
+	model, err := newValuesModel(request, values, aligns)
+	if err != nil {
+		return nil, err
+	}
+	model.headerRows = headerRows
+	model.footnotes = footnotes
+	model.markers, model.footnotes = newFootnoteMarkers(model.footnotes)
+	for i, note := range model.footnotes {
+		model.footnotes[i] = h.InlineText(markdownInline(note, numbers))
+	}
+	if len(model.title) == 0 && len(table.title) > 0 {
+		model.title = model.markers.convert(h.InlineText(markdownInline(table.title, numbers)))
+		inferred.Title = model.title
+	}
+	model.inferred = inferred
+	return model, nil
+}
+
+// findMarkdownTable returns the first pipe table in the markdown - a header row followed by a delimiter row with the
+// same number of cells, then the rows up to a blank line or heading - or nil if there is none
+func findMarkdownTable(markdown string) *markdownTable {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	table := &markdownTable{}
+	table.footnotes, table.labels = markdownFootnotes(lines)
+
+	for i := 0; i+1 < len(lines); i++ {
+		if match := headingPattern.FindStringSubmatch(lines[i]); match != nil {
+			table.title = match[1]
+		}
+		if !strings.Contains(lines[i], "|") || !delimiterRowPattern.MatchString(strings.TrimSpace(lines[i+1])) {
+			continue
+		}
+		header := splitMarkdownRow(lines[i])
+		delimiters := splitMarkdownRow(lines[i+1])
+		if len(header) != len(delimiters) {
+			continue
+		}
+		for _, delimiter := range delimiters {
+			table.aligns = append(table.aligns, delimiterAlignment(delimiter))
+		}
+		table.rows = append(table.rows, header)
+		for _, line := range lines[i+2:] {
+			if len(strings.TrimSpace(line)) == 0 || headingPattern.MatchString(line) {
+				break
+			}
+			// as in GitHub flavored markdown, excess cells are ignored and missing cells are empty
+			row := make([]string, len(header))
+			copy(row, splitMarkdownRow(line))
+			table.rows = append(table.rows, row)
+		}
+		return table
+	}
+	return nil
+}
+
+// splitMarkdownRow splits a row of a pipe table into its cells, at each pipe that isn't escaped with a backslash. The
+// pipes at the start and end of the row are optional.
+func splitMarkdownRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			b.WriteString(`\|`)
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(b.String()))
+			b.Reset()
+		default:
+			b.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(b.String()))
+}
+
+// delimiterAlignment returns the alignment given by the colons of a cell in the delimiter row, e.g. :-: for Center
+func delimiterAlignment(delimiter string) string {
+	left, right := strings.HasPrefix(delimiter, ":"), strings.HasSuffix(delimiter, ":")
+	switch {
+	case left && right:
+		return models.AlignCenter
+	case right:
+		return models.AlignRight
+	case left:
+		return models.AlignLeft
+	}
+	return ""
+}
+
+// markdownFootnotes returns the text and label of each footnote definition, e.g. [^1]: A note, in order. Indented lines
+// that follow a definition continue it.
+func markdownFootnotes(lines []string) ([]string, []string) {
+	var footnotes, labels []string
+	for i := 0; i < len(lines); i++ {
+		match := footnoteDefinitionPattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		text := []string{strings.TrimSpace(match[2])}
+		for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "  ") && len(strings.TrimSpace(lines[i+1])) > 0 {
+			i++
+			text = append(text, strings.TrimSpace(lines[i]))
+		}
+		footnotes = append(footnotes, strings.Join(text, " "))
+		labels = append(labels, match[1])
+	}
+	return footnotes, labels
+}
+
+// markdownInline converts the inline markdown of a value - emphasis, links, code spans, backslash escapes and
+// references to footnotes such as [^1] - to the canonical inline markup. The inline html allowed by the markup is kept,
+// and any other < is escaped. A reference uses the number of the footnote with its label, or else the label itself if
+// it is a number.
+func markdownInline(value string, numbers map[string]int) string {
+	value = escapePattern.ReplaceAllStringFunc(value, func(escaped string) string {
+		return string(escapeBase + rune(escaped[1]))
+	})
+
+	var b strings.Builder
+	last := 0
+	for _, match := range codeSpanPattern.FindAllStringSubmatchIndex(value, -1) {
+		// a code span is closed by a run of backticks of the same length
+		if match[3]-match[2] != match[7]-match[6] {
+			continue
+		}
+		b.WriteString(markdownText(value[last:match[0]], numbers))
+		b.WriteString(html.EscapeString(strings.TrimSpace(value[match[4]:match[5]])))
+		last = match[1]
+	}
+	b.WriteString(markdownText(value[last:], numbers))
+
+	return strings.Map(func(r rune) rune {
+		if r >= escapeBase && r <= escapeBase+'~' {
+			return r - escapeBase
+		}
+		return r
+	}, escapeLiteralCharacters(b.String()))
+}
+
+// markdownText converts the inline markdown of text outside code spans
+func markdownText(text string, numbers map[string]int) string {
+	text = autolinkPattern.ReplaceAllString(text, `<a href="$1">$1</a>`)
+
+	// escape any < that doesn't start a tag of the inline markup
+	var b strings.Builder
+	last := 0
+	for _, tag := range inlineTagPattern.FindAllStringIndex(text, -1) {
+		b.WriteString(strings.ReplaceAll(text[last:tag[0]], "<", "&lt;"))
+		b.WriteString(text[tag[0]:tag[1]])
+		last = tag[1]
+	}
+	b.WriteString(strings.ReplaceAll(text[last:], "<", "&lt;"))
+	text = b.String()
+
+	text = footnoteLabelPattern.ReplaceAllStringFunc(text, func(reference string) string {
+		label := footnoteLabelPattern.FindStringSubmatch(reference)[1]
+		if number, found := numbers[label]; found {
+			return "[" + strconv.Itoa(number) + "]"
+		}
+		if _, err := strconv.Atoi(label); err == nil {
+			return "[" + label + "]"
+		}
+		return reference
+	})
+	text = linkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		match := linkPattern.FindStringSubmatch(link)
+		return `<a href="` + html.EscapeString(match[2]) + `">` + match[1] + `</a>`
+	})
+	text = strongPattern.ReplaceAllString(text, "<strong>$1$2</strong>")
+	return emphasisPattern.ReplaceAllString(text, "<em>$1$2</em>")
+}
+
+// escapeLiteralCharacters escapes the characters that were escaped with a backslash, so that they are kept as text
+func escapeLiteralCharacters(value string) string {
+	return strings.NewReplacer(
+		string(escapeBase+'<'), "&lt;",
+		string(escapeBase+'>'), "&gt;",
+		string(escapeBase+'&'), "&amp;",
+	).Replace(value)
+}
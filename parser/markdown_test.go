@@ -0,0 +1,111 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const markdownTable = `# Population *by* region
+
+Some text before the table.
+
+| Region | Code | Count | Change |
+|:-------|:----:|-------|--------|
+| **North**[^est] | N1 | 1,234 | +5% |
+| South | S1 | 12 | .. |
+| [West](https://example.com/west) | ` + "`W\\|1`" + ` | 567 |
+| a < b \* c | X | 8 | -1 | extra |
+
+Text after the table.
+
+[^est]: Estimated from
+  the survey
+[^unused]: Not referenced
+`
+
+func TestParseMarkdown(t *testing.T) {
+
+	Convey("ParseMarkdown should convert a pipe table to the json used to render it", t, func() {
+		response := invokeParseMarkdown(&models.ParseRequest{Filename: "myFilename", TableMarkdown: markdownTable})
+
+		So(response.JSON.Data, ShouldResemble, [][]string{
+			{"Region", "Code", "Count", "Change"},
+			{"<strong>North</strong>[1]", "N1", "1,234", "+5%"},
+			{"South", "S1", "12", ".."},
+			{`<a href="https://example.com/west">West</a>`, "W|1", "567", ""},
+			{"a &lt; b * c", "X", "8", "-1"},
+		})
+		So(response.JSON.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}})
+		So(response.PreviewHTML, ShouldContainSubstring, "<table")
+		So(response.CellLayout, ShouldEqual, models.CellLayoutStandard)
+	})
+
+	Convey("ParseMarkdown should use the alignment markers, and align columns of numbers to the right", t, func() {
+		response := invokeParseMarkdown(&models.ParseRequest{Filename: "myFilename", TableMarkdown: markdownTable})
+
+		So(response.JSON.ColumnFormats, ShouldResemble, []models.ColumnFormat{
+			{Column: 0, Align: models.AlignLeft},
+			{Column: 1, Align: models.AlignCenter},
+			{Column: 2, Align: models.AlignRight},
+			{Column: 3, Align: models.AlignRight},
+		})
+	})
+
+	Convey("ParseMarkdown should take the title from the heading, and the footnotes from their definitions", t, func() {
+		response := invokeParseMarkdown(&models.ParseRequest{Filename: "myFilename", TableMarkdown: markdownTable})
+
+		So(response.JSON.Title, ShouldEqual, "Population by region")
+		So(response.JSON.Footnotes, ShouldResemble, []string{"Estimated from the survey", "Not referenced"})
+		So(response.Inferred, ShouldResemble, &parser.Inferred{
+			HeaderRows: 1,
+			Title:      "Population by region",
+			Footnotes:  []string{"Estimated from the survey", "Not referenced"},
+		})
+		So(response.Warnings, ShouldHaveLength, 1)
+		So(response.Warnings[0].Code, ShouldEqual, models.WarningUnusedFootnote)
+	})
+
+	Convey("ParseMarkdown should prefer the headings, title and footnotes of the request", t, func() {
+		request := &models.ParseRequest{
+			Filename:      "myFilename",
+			Title:         "myTitle",
+			HeaderRows:    2,
+			Footnotes:     []string{"Note one", "Note two"},
+			TableMarkdown: "| Year | Count[^2] |\n|---|---|\n| 2020* | 5 |",
+		}
+		response := invokeParseMarkdown(request)
+
+		So(response.JSON.Title, ShouldEqual, "myTitle")
+		So(response.JSON.Data, ShouldResemble, [][]string{{"Year", "Count[2]"}, {"2020[1]", "5"}})
+		So(response.JSON.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}, {Row: 1, Heading: true}})
+		So(response.Inferred, ShouldResemble, &parser.Inferred{})
+	})
+
+	Convey("ParseMarkdown should return an error before filling the rows of a table that exceeds the limits", t, func() {
+		markdown := "| a | b | c | d |\n|---|---|---|---|\n| 1 |\n| 2 |\n| 3 |"
+		_, err := parser.ParseMarkdown(mockContext, &models.ParseRequest{Filename: "myFilename", TableMarkdown: markdown, Limits: models.Limits{Cells: 10}})
+		So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+		So(err.(*models.Error).Details["limit"], ShouldEqual, "max_cells")
+	})
+
+	Convey("ParseMarkdown should return an error when there is no pipe table", t, func() {
+		for _, markdown := range []string{"# Heading\n\nSome text | with a pipe", "| a | b |\n|---|\n| c | d |"} {
+			_, err := parser.ParseMarkdown(mockContext, &models.ParseRequest{Filename: "myFilename", TableMarkdown: markdown})
+			So(errors.Is(err, &models.Error{Code: models.CodeTableNotFound}), ShouldBeTrue)
+		}
+	})
+}
+
+func invokeParseMarkdown(request *models.ParseRequest) *parser.ResponseModel {
+	resultBytes, err := parser.ParseMarkdown(mockContext, request)
+	So(err, ShouldBeNil)
+
+	result := parser.ResponseModel{}
+	So(json.Unmarshal(resultBytes, &result), ShouldBeNil)
+	return &result
+}
@@ -0,0 +1,622 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxRangeCells is the largest number of cells in the range of a sheet that will be parsed
+	maxRangeCells = 1 << 20
+	// maxSheetRows and maxSheetColumns are the size of the largest sheet of a spreadsheet, and so the most rows or
+	// columns that an element can repeat or a cell can span
+	maxSheetRows    = 1 << 20
+	maxSheetColumns = 1 << 14
+)
+
+var (
+	// odsAlignments maps the text-align of a paragraph style to the value of an align attribute
+	odsAlignments = map[string]string{
+		"left":    "left",
+		"start":   "left",
+		"center":  "center",
+		"right":   "right",
+		"end":     "right",
+		"justify": "justify",
+	}
+	// odsVerticalAlignments maps the vertical-align of a cell style to the value of a valign attribute
+	odsVerticalAlignments = map[string]string{
+		"top":    "top",
+		"middle": "middle",
+		"bottom": "bottom",
+	}
+	// odsNumericTypes are the value types that a spreadsheet aligns to the right unless the cell's style aligns them
+	odsNumericTypes = map[string]bool{"float": true, "percentage": true, "currency": true, "date": true, "time": true}
+	// odsLengthUnits are the number of points in each unit of length
+	odsLengthUnits = map[string]float64{"pt": 1, "pc": 12, "in": 72, "cm": 72 / 2.54, "mm": 72 / 25.4, "px": 0.75}
+
+	rangePattern     = regexp.MustCompile(`^\$?([A-Za-z]{1,3})\$?([0-9]{1,7}):\$?([A-Za-z]{1,3})\$?([0-9]{1,7})$`)
+	odsLengthPattern = regexp.MustCompile(`^([0-9]*\.?[0-9]+)([a-z]+)$`)
+	whitespace       = regexp.MustCompile(`\s+`)
+	errNoContent     = errors.New("content.xml is missing")
+)
+
+// odsFile is an OpenDocument spreadsheet: its sheets, and the styles they use
+type odsFile struct {
+	sheets []*xmlNode           // the table element of each sheet
+	styles map[string]*odsStyle // the styles, by family and name, e.g. table-cell:ce1
+}
+
+// odsStyle is a style of an OpenDocument spreadsheet. An empty property is inherited from the parent style.
+type odsStyle struct {
+	parent      string
+	align       string // the text-align of paragraphs
+	alignSource string // value-type if the alignment of a cell depends on the type of its value, rather than align
+	valign      string // the vertical-align of cells
+	width       string // the width of columns
+	bold        string
+	italic      string
+	position    string // the text-position of text, e.g. super or sub
+}
+
+// odsSlot is a cell of a sheet, or a cell covered by a merged cell
+type odsSlot struct {
+	cell   *xmlNode // the table-cell element, or nil if it is covered
+	row    *xmlNode // the table-row element containing the cell
+	column *xmlNode // the table-column element of the column containing the cell
+}
+
+// odsColumn is a table-column element, which may be repeated, and whether it is a heading column
+type odsColumn struct {
+	node    *xmlNode
+	repeat  int
+	heading bool
+}
+
+// odsRow is a table-row element, which may be repeated, and whether it is a heading row
+type odsRow struct {
+	node    *xmlNode
+	repeat  int
+	heading bool
+}
+
+// cellRange is a range of the cells of a sheet, from 0
+type cellRange struct {
+	top, left, bottom, right int
+}
+
+// ParseOds converts the range of cells of the sheet of an OpenDocument spreadsheet chosen by the request into the json
+// used to render the table, along with the preview html. The sheet defaults to the first, and the range to the cells
+// of the sheet that aren't empty.
+func ParseOds(ctx context.Context, request *models.ParseRequest) ([]byte, error) {
+	doc, err := openOds(request.Ods, request.Limits)
+	if err != nil {
+		log.Error(ctx, "Unable to read ods", err)
+		return nil, err
+	}
+	sheet, err := doc.selectSheet(request.Sheet)
+	if err != nil {
+		return nil, err
+	}
+	columns, rows := sheetColumns(sheet), sheetRows(sheet)
+	bounds, err := selectRange(request.Range, rows)
+	if err != nil {
+		return nil, err
+	}
+	if err = request.Limits.CheckTableSize(bounds.bottom-bounds.top+1, bounds.right-bounds.left+1); err != nil {
+		return nil, err
+	}
+	return createResponse(ctx, func() (*parseModel, error) { return doc.createModel(request, columns, rows, bounds), nil })
+}
+
+// openOds reads the sheets of an OpenDocument spreadsheet, along with its styles. The number of nodes read from each
+// part is limited by the cells allowed by the limits.
+func openOds(content []byte, limits models.Limits) (*odsFile, error) {
+	archive, err := openZipDocument(content, "ods", limits)
+	if err != nil {
+		return nil, err
+	}
+	document, err := archive.part("content.xml")
+	if err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, invalidDocument("ods", errNoContent)
+	}
+	styles, err := archive.part("styles.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &odsFile{styles: make(map[string]*odsStyle)}
+	doc.sheets = document.path("body", "spreadsheet").childrenNamed("table")
+	for _, container := range []*xmlNode{styles.child("styles"), styles.child("automatic-styles"), document.child("automatic-styles")} {
+		for _, style := range container.childrenNamed("style") {
+			doc.styles[style.attr("family")+":"+style.attr("name")] = &odsStyle{
+				parent:      style.attr("parent-style-name"),
+				align:       style.child("paragraph-properties").attr("text-align"),
+				alignSource: style.child("table-cell-properties").attr("text-align-source"),
+				valign:      style.child("table-cell-properties").attr("vertical-align"),
+				width:       style.child("table-column-properties").attr("column-width"),
+				bold:        style.child("text-properties").attr("font-weight"),
+				italic:      style.child("text-properties").attr("font-style"),
+				position:    style.child("text-properties").attr("text-position"),
+			}
+		}
+	}
+	return doc, nil
+}
+
+// selectSheet returns the sheet with the given name, or else index, or the first sheet if none is given
+func (d *odsFile) selectSheet(name string) (*xmlNode, error) {
+	if len(d.sheets) == 0 {
+		return nil, models.NewError(models.CodeTableNotFound, "ods does not contain a sheet", nil)
+	}
+	if len(name) == 0 {
+		return d.sheets[0], nil
+	}
+	var names []string
+	for _, sheet := range d.sheets {
+		if sheet.attr("name") == name {
+			return sheet, nil
+		}
+		names = append(names, sheet.attr("name"))
+	}
+	if index, err := strconv.Atoi(strings.TrimSpace(name)); err == nil && index >= 0 && index < len(d.sheets) {
+		return d.sheets[index], nil
+	}
+	return nil, &models.Error{
+		Code:    models.CodeTableNotFound,
+		Message: "sheet is not the name or index of a sheet in ods",
+		Details: map[string]interface{}{"sheet": name, "sheets": names},
+	}
+}
+
+// sheetColumns returns the columns of a sheet, including those in groups
+func sheetColumns(node *xmlNode) []odsColumn {
+	var columns []odsColumn
+	for _, child := range node.children {
+		switch child.name {
+		case "table-column":
+			columns = append(columns, odsColumn{node: child, repeat: odsCount(child, "number-columns-repeated", maxSheetColumns)})
+		case "table-header-columns":
+			for _, column := range sheetColumns(child) {
+				column.heading = true
+				columns = append(columns, column)
+			}
+		case "table-columns", "table-column-group":
+			columns = append(columns, sheetColumns(child)...)
+		}
+	}
+	return columns
+}
+
+// sheetRows returns the rows of a sheet, including those in groups
+func sheetRows(node *xmlNode) []odsRow {
+	var rows []odsRow
+	for _, child := range node.children {
+		switch child.name {
+		case "table-row":
+			rows = append(rows, odsRow{node: child, repeat: odsCount(child, "number-rows-repeated", maxSheetRows)})
+		case "table-header-rows":
+			for _, row := range sheetRows(child) {
+				row.heading = true
+				rows = append(rows, row)
+			}
+		case "table-rows", "table-row-group":
+			rows = append(rows, sheetRows(child)...)
+		}
+	}
+	return rows
+}
+
+// rowCells returns the cells and covered cells of a row, each with the number of times it is repeated
+func rowCells(row *xmlNode) ([]*xmlNode, []int) {
+	var cells []*xmlNode
+	var repeats []int
+	for _, child := range row.children {
+		if child.name == "table-cell" || child.name == "covered-table-cell" {
+			cells = append(cells, child)
+			repeats = append(repeats, odsCount(child, "number-columns-repeated", maxSheetColumns))
+		}
+	}
+	return cells, repeats
+}
+
+// odsCount returns the number of rows or columns that an element repeats or a cell spans, given by the attribute. It
+// is at least 1, and at most the given maximum, so that the bounds of a sheet can't overflow.
+func odsCount(node *xmlNode, attr string, maximum int) int {
+	return min(max(attrInt(node, attr, 1), 1), maximum)
+}
+
+// selectRange returns the range of cells given in A1 notation, e.g. B2:F20, or else the smallest range containing the
+// cells of the sheet that aren't empty
+func selectRange(value string, rows []odsRow) (cellRange, error) {
+	var bounds cellRange
+	value = strings.TrimSpace(value)
+	if len(value) > 0 {
+		match := rangePattern.FindStringSubmatch(value)
+		if match == nil {
+			return bounds, rangeNotFound(value, "range is not a range of cells, e.g. A1:D10")
+		}
+		top, _ := strconv.Atoi(match[2])
+		bottom, _ := strconv.Atoi(match[4])
+		bounds = cellRange{top: top - 1, left: columnIndex(match[1]), bottom: bottom - 1, right: columnIndex(match[3])}
+		if bounds.top < 0 || bounds.bottom < 0 {
+			return bounds, rangeNotFound(value, "range is not a range of cells, e.g. A1:D10")
+		}
+		bounds.top, bounds.bottom = min(bounds.top, bounds.bottom), max(bounds.top, bounds.bottom)
+		bounds.left, bounds.right = min(bounds.left, bounds.right), max(bounds.left, bounds.right)
+	} else {
+		bounds = usedRange(rows)
+		if bounds.bottom < 0 {
+			return bounds, models.NewError(models.CodeTableNotFound, "the sheet of ods is empty", nil)
+		}
+	}
+	// the rows and columns are checked separately, as the number of cells could overflow
+	numRows, numColumns := bounds.bottom-bounds.top+1, bounds.right-bounds.left+1
+	if numRows > maxRangeCells || numColumns > maxRangeCells/numRows {
+		return bounds, &models.Error{
+			Code:    models.CodeTableTooLarge,
+			Message: fmt.Sprintf("The range of cells is larger than the limit (%d cells)", maxRangeCells),
+			Details: map[string]interface{}{"range": value, "max": maxRangeCells},
+		}
+	}
+	return bounds, nil
+}
+
+// usedRange returns the smallest range containing the cells that aren't empty, or a range with a bottom of -1 if every
+// cell is empty. Repeated cells are counted without being expanded.
+func usedRange(rows []odsRow) cellRange {
+	bounds := cellRange{top: -1, left: -1, bottom: -1, right: -1}
+	r := 0
+	for _, row := range rows {
+		cells, repeats := rowCells(row.node)
+		c := 0
+		for i, cell := range cells {
+			if !isEmptyCell(cell) {
+				bottom := r + row.repeat - 1 + odsCount(cell, "number-rows-spanned", maxSheetRows) - 1
+				right := c + repeats[i] - 1 + odsCount(cell, "number-columns-spanned", maxSheetColumns) - 1
+				if bounds.bottom < 0 {
+					bounds = cellRange{top: r, left: c, bottom: bottom, right: right}
+				}
+				bounds.top, bounds.bottom = min(bounds.top, r), max(bounds.bottom, bottom)
+				bounds.left, bounds.right = min(bounds.left, c), max(bounds.right, right)
+			}
+			c += repeats[i]
+		}
+		r += row.repeat
+	}
+	return bounds
+}
+
+// isEmptyCell returns true for a covered cell, or a cell without text or a value that doesn't span other cells
+func isEmptyCell(cell *xmlNode) bool {
+	if cell.name != "table-cell" {
+		return true
+	}
+	if cell.hasAttr("value-type") || attrInt(cell, "number-columns-spanned", 1) > 1 || attrInt(cell, "number-rows-spanned", 1) > 1 {
+		return false
+	}
+	for _, p := range cell.childrenNamed("p") {
+		if len(strings.TrimSpace(p.textContent())) > 0 || p.child("s") != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// columnIndex returns the index, from 0, of a column given by its letters, e.g. 27 for AB
+func columnIndex(letters string) int {
+	index := 0
+	for _, letter := range strings.ToUpper(letters) {
+		index = index*26 + int(letter-'A') + 1
+	}
+	return index - 1
+}
+
+// rangeNotFound returns the error for a range that can't be parsed
+func rangeNotFound(value string, message string) error {
+	return &models.Error{Code: models.CodeTableNotFound, Message: message, Details: map[string]interface{}{"range": value}}
+}
+
+// createModel creates a model from a range of the cells of a sheet. Cells merged across columns or rows become the
+// colspan and rowspan of cell formats, limited to the range. Rows and columns that repeat as headings when the sheet is
+// printed are headings, unless the request gives the number of heading rows or columns.
+func (d *odsFile) createModel(request *models.ParseRequest, columns []odsColumn, rows []odsRow, bounds cellRange) *parseModel {
+	// widths are converted to % of the width of the range, unless no widths are wanted
+	sized := *request
+	if len(sized.CellSizeUnits) == 0 {
+		sized.CellSizeUnits = "%"
+	}
+	model := parseModel{
+		request:    &sized,
+		cellLayout: models.CellLayoutStandard,
+		headerRows: request.HeaderRows,
+		headerCols: request.HeaderCols,
+		title:      request.Title,
+		footnotes:  parseFootnotes(request.Footnotes),
+		inferred:   &Inferred{},
+	}
+	model.markers, model.footnotes = newFootnoteMarkers(model.footnotes)
+
+	slots, headings := rangeSlots(columns, rows, bounds)
+	numRows, numColumns := bounds.bottom-bounds.top+1, bounds.right-bounds.left+1
+	model.cells = make([][]*html.Node, numRows)
+	model.merges = make([][]merge, numRows)
+	merged := make([][]bool, numRows)
+	for r := range model.cells {
+		model.cells[r] = make([]*html.Node, numColumns)
+		model.merges[r] = make([]merge, numColumns)
+		merged[r] = make([]bool, numColumns)
+	}
+	for r := range model.cells {
+		for c := range model.cells[r] {
+			slot := slots[r][c]
+			if merged[r][c] {
+				continue
+			}
+			if slot.cell == nil {
+				// an empty cell, or a covered cell whose merged cell is outside the range
+				model.cells[r][c] = newCell("")
+				continue
+			}
+			model.cells[r][c] = d.cell(slot)
+			rowspan := min(odsCount(slot.cell, "number-rows-spanned", maxSheetRows), numRows-r)
+			colspan := min(odsCount(slot.cell, "number-columns-spanned", maxSheetColumns), numColumns-c)
+			if rowspan > 1 {
+				model.merges[r][c].rowspan = rowspan
+			}
+			if colspan > 1 {
+				model.merges[r][c].colspan = colspan
+			}
+			for i := r; i < r+rowspan; i++ {
+				for j := c; j < c+colspan; j++ {
+					merged[i][j] = i != r || j != c
+				}
+			}
+		}
+	}
+	model.alignments = parseAlignments(&model)
+
+	if model.headerRows == 0 {
+		for model.headerRows < numRows && headings.rows[model.headerRows] {
+			model.headerRows++
+		}
+		model.inferred.HeaderRows = model.headerRows
+	}
+	if model.headerCols == 0 {
+		for model.headerCols < numColumns && headings.columns[model.headerCols] {
+			model.headerCols++
+		}
+		model.inferred.HeaderCols = model.headerCols
+	}
+	model.widths = d.columnWidths(slots)
+	return &model
+}
+
+// rangeHeadings records which rows and columns of a range repeat as headings when the sheet is printed
+type rangeHeadings struct {
+	rows    []bool
+	columns []bool
+}
+
+// rangeSlots returns the cells of the range of a sheet, expanding repeated rows, columns and cells within the range,
+// and which of its rows and columns are headings
+func rangeSlots(columns []odsColumn, rows []odsRow, bounds cellRange) ([][]odsSlot, rangeHeadings) {
+	numRows, numColumns := bounds.bottom-bounds.top+1, bounds.right-bounds.left+1
+	slots := make([][]odsSlot, numRows)
+	headings := rangeHeadings{rows: make([]bool, numRows), columns: make([]bool, numColumns)}
+	for r := range slots {
+		slots[r] = make([]odsSlot, numColumns)
+	}
+
+	c := 0
+	for _, column := range columns {
+		for i := max(c, bounds.left); i < min(c+column.repeat, bounds.right+1); i++ {
+			headings.columns[i-bounds.left] = column.heading
+			for r := range slots {
+				slots[r][i-bounds.left].column = column.node
+			}
+		}
+		c += column.repeat
+	}
+
+	r := 0
+	for _, row := range rows {
+		if r > bounds.bottom {
+			break
+		}
+		cells, repeats := rowCells(row.node)
+		for i := max(r, bounds.top); i < min(r+row.repeat, bounds.bottom+1); i++ {
+			headings.rows[i-bounds.top] = row.heading
+			c := 0
+			for n, cell := range cells {
+				for j := max(c, bounds.left); j < min(c+repeats[n], bounds.right+1); j++ {
+					slot := &slots[i-bounds.top][j-bounds.left]
+					slot.row = row.node
+					if cell.name == "table-cell" {
+						slot.cell = cell
+					}
+				}
+				c += repeats[n]
+			}
+		}
+		r += row.repeat
+	}
+	return slots, headings
+}
+
+// cell creates a td element from a cell of the sheet, with the displayed text of each of its paragraphs separated by
+// line breaks, and its alignment
+func (d *odsFile) cell(slot odsSlot) *html.Node {
+	var values []string
+	paragraphStyle := ""
+	for _, p := range slot.cell.childrenNamed("p") {
+		value := strings.TrimSpace(runsMarkup(d.runs(p, textRun{})))
+		if len(value) == 0 && len(values) == 0 {
+			continue
+		}
+		if len(values) == 0 {
+			paragraphStyle = p.attr("style-name")
+		}
+		values = append(values, value)
+	}
+	value := strings.TrimSpace(strings.Join(values, "\n"))
+	if len(values) == 0 {
+		// a cell written without its displayed text
+		value = html.EscapeString(cellValue(slot.cell))
+	}
+	cell := newCell(value)
+
+	cellStyle := slot.cell.attr("style-name")
+	if len(cellStyle) == 0 {
+		cellStyle = slot.row.attr("default-cell-style-name")
+	}
+	if len(cellStyle) == 0 {
+		cellStyle = slot.column.attr("default-cell-style-name")
+	}
+	if len(cellStyle) == 0 {
+		cellStyle = "Default"
+	}
+	align := d.property("paragraph", paragraphStyle, func(s *odsStyle) string { return s.align })
+	if len(align) == 0 && d.property("table-cell", cellStyle, func(s *odsStyle) string { return s.alignSource }) != "value-type" {
+		align = d.property("table-cell", cellStyle, func(s *odsStyle) string { return s.align })
+	}
+	if len(align) == 0 && odsNumericTypes[slot.cell.attr("value-type")] {
+		align = "right"
+	}
+	if align = odsAlignments[align]; len(align) > 0 {
+		h.AddAttribute(cell, "align", align)
+	}
+	if valign := odsVerticalAlignments[d.property("table-cell", cellStyle, func(s *odsStyle) string { return s.valign })]; len(valign) > 0 {
+		h.AddAttribute(cell, "valign", valign)
+	}
+	return cell
+}
+
+// cellValue returns the value of a cell from its attributes, for a cell without paragraphs
+func cellValue(cell *xmlNode) string {
+	for _, attr := range []string{"value", "date-value", "time-value", "boolean-value", "string-value"} {
+		if value := cell.attr(attr); len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
+
+// runs returns the runs of text in a paragraph, or a span or link within it, with the formatting of its text style
+func (d *odsFile) runs(node *xmlNode, format textRun) []textRun {
+	if style := node.attr("style-name"); len(style) > 0 && node.name != "p" {
+		format.bold = format.bold || isBold(d.property("text", style, func(s *odsStyle) string { return s.bold }))
+		format.italic = format.italic || isItalic(d.property("text", style, func(s *odsStyle) string { return s.italic }))
+		// the position is super, sub, or a % of the font height to raise the text by
+		if position := strings.Fields(d.property("text", style, func(s *odsStyle) string { return s.position })); len(position) > 0 {
+			raise, _ := strconv.ParseFloat(strings.TrimSuffix(position[0], "%"), 64)
+			format.superscript = position[0] == "super" || raise > 0
+			format.subscript = position[0] == "sub" || raise < 0
+		}
+	}
+	var runs []textRun
+	for _, child := range node.children {
+		run := format
+		switch child.name {
+		case "":
+			run.text = whitespace.ReplaceAllString(child.text, " ")
+		case "s":
+			run.text = strings.Repeat(" ", max(attrInt(child, "c", 1), 1))
+		case "tab":
+			run.text = " "
+		case "line-break":
+			run.text = "\n"
+		case "a":
+			run.link = child.attr("href")
+			runs = append(runs, d.runs(child, run)...)
+			continue
+		case "span":
+			runs = append(runs, d.runs(child, run)...)
+			continue
+		case "note", "annotation", "soft-page-break", "bookmark", "bookmark-start", "bookmark-end":
+			continue
+		default:
+			run.text = child.textContent()
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// property returns a property of a style of the given family, or else of the style it inherits from
+func (d *odsFile) property(family string, name string, property func(*odsStyle) string) string {
+	for i := 0; i <= len(d.styles) && len(name) > 0; i++ {
+		style, found := d.styles[family+":"+name]
+		if !found {
+			break
+		}
+		if value := property(style); len(value) > 0 {
+			return value
+		}
+		name = style.parent
+	}
+	return ""
+}
+
+// columnWidths returns the width of each column of the range as a % of the width of the range, if every column has
+// a width
+func (d *odsFile) columnWidths(slots [][]odsSlot) [][]string {
+	if len(slots) == 0 {
+		return nil
+	}
+	points := make([]float64, len(slots[0]))
+	total := 0.0
+	for c, slot := range slots[0] {
+		width := d.property("table-column", slot.column.attr("style-name"), func(s *odsStyle) string { return s.width })
+		points[c] = lengthInPoints(width)
+		if points[c] <= 0 {
+			return nil
+		}
+		total += points[c]
+	}
+	widths := make([][]string, len(points))
+	for c, width := range points {
+		length := fmt.Sprintf("%.1f%%", width*100/total)
+		widths[c] = []string{widthTrailingZeroesPattern.ReplaceAllString(length, "$1")}
+	}
+	return widths
+}
+
+// lengthInPoints converts a length such as 2.5cm or 1in to points, returning 0 if it can't be converted
+func lengthInPoints(length string) float64 {
+	match := odsLengthPattern.FindStringSubmatch(strings.TrimSpace(length))
+	if match == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0
+	}
+	return value * odsLengthUnits[match[2]]
+}
+
+// isBold returns true for a font-weight that is bold
+func isBold(weight string) bool {
+	if weight == "bold" || weight == "bolder" {
+		return true
+	}
+	n, err := strconv.Atoi(weight)
+	return err == nil && n >= 600
+}
+
+// isItalic returns true for a font-style that is italic or oblique
+func isItalic(style string) bool {
+	return style == "italic" || style == "oblique"
+}
@@ -0,0 +1,209 @@
+package parser_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	odsNamespaces = `xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" ` +
+		`xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" ` +
+		`xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" ` +
+		`xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" ` +
+		`xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0" ` +
+		`xmlns:xlink="http://www.w3.org/1999/xlink"`
+
+	odsAutomaticStyles = `
+<office:automatic-styles>
+  <style:style style:name="co1" style:family="table-column"><style:table-column-properties style:column-width="4cm"/></style:style>
+  <style:style style:name="co2" style:family="table-column"><style:table-column-properties style:column-width="20mm"/></style:style>
+  <style:style style:name="ce1" style:family="table-cell" style:parent-style-name="Default"><style:table-cell-properties style:vertical-align="bottom"/></style:style>
+  <style:style style:name="ce2" style:family="table-cell" style:parent-style-name="Heading"/>
+  <style:style style:name="ce3" style:family="table-cell" style:parent-style-name="Default"><style:table-cell-properties style:text-align-source="fix" style:vertical-align="middle"/><style:paragraph-properties fo:text-align="start"/></style:style>
+  <style:style style:name="T1" style:family="text"><style:text-properties fo:font-weight="bold"/></style:style>
+  <style:style style:name="T2" style:family="text"><style:text-properties style:text-position="super 58%"/></style:style>
+</office:automatic-styles>`
+
+	odsSheets = `
+<table:table table:name="Population">
+  <table:table-column table:style-name="co1" table:default-cell-style-name="Default"/>
+  <table:table-column table:style-name="co2" table:number-columns-repeated="2" table:default-cell-style-name="Default"/>
+  <table:table-column table:style-name="co2" table:number-columns-repeated="1021"/>
+  <table:table-header-rows>
+    <table:table-row>
+      <table:table-cell table:style-name="ce1" table:number-rows-spanned="2" office:value-type="string"><text:p>Region</text:p></table:table-cell>
+      <table:table-cell table:style-name="ce2" table:number-columns-spanned="2" office:value-type="string"><text:p>Count</text:p></table:table-cell>
+      <table:covered-table-cell/>
+      <table:table-cell table:number-columns-repeated="1021"/>
+    </table:table-row>
+    <table:table-row>
+      <table:covered-table-cell/>
+      <table:table-cell office:value-type="float" office:value="2020"><text:p>2020</text:p></table:table-cell>
+      <table:table-cell office:value-type="float" office:value="2021"><text:p>2021</text:p></table:table-cell>
+      <table:table-cell table:number-columns-repeated="1021"/>
+    </table:table-row>
+  </table:table-header-rows>
+  <table:table-row>
+    <table:table-cell office:value-type="string"><text:p><text:span text:style-name="T1">North<text:s/>East</text:span><text:span text:style-name="T2">1</text:span></text:p></table:table-cell>
+    <table:table-cell office:value-type="float" office:value="1234"><text:p>1,234</text:p></table:table-cell>
+    <table:table-cell table:style-name="ce3" office:value-type="float" office:value="1240.5"><text:p>1,240.50</text:p></table:table-cell>
+    <table:table-cell table:number-columns-repeated="1021"/>
+  </table:table-row>
+  <table:table-row>
+    <table:table-cell office:value-type="string"><text:p><text:a xlink:href="https://example.com/wales">Wales</text:a></text:p><text:p>a &lt; b</text:p></table:table-cell>
+    <table:table-cell office:value-type="percentage" office:value="0.25"><text:p>25%</text:p></table:table-cell>
+    <table:table-cell table:style-name="ce2" office:value-type="string"><text:p>..</text:p></table:table-cell>
+    <table:table-cell table:number-columns-repeated="1021"/>
+  </table:table-row>
+  <table:table-row table:number-rows-repeated="1048572"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+</table:table>
+<table:table table:name="Empty">
+  <table:table-row table:number-rows-repeated="1048576"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+</table:table>`
+
+	odsStyles = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-styles ` + odsNamespaces + `>
+<office:styles>
+  <style:style style:name="Default" style:family="table-cell"/>
+  <style:style style:name="Heading" style:family="table-cell" style:parent-style-name="Default"><style:table-cell-properties style:text-align-source="fix"/><style:paragraph-properties fo:text-align="center"/></style:style>
+</office:styles>
+</office:document-styles>`
+)
+
+func TestParseOds(t *testing.T) {
+
+	Convey("ParseOds should convert the cells of the first sheet that aren't empty to the json used to render them", t, func() {
+		response := invokeParseOds(&models.ParseRequest{Filename: "myFilename", Ods: createOds(odsSheets)})
+
+		So(response.JSON.Data, ShouldResemble, [][]string{
+			{"Region", "Count", ""},
+			{"", "2020", "2021"},
+			{"<strong>North East</strong><sup>1</sup>", "1,234", "1,240.50"},
+			{"<a href=\"https://example.com/wales\">Wales</a>\na &lt; b", "25%", ".."},
+		})
+		So(response.JSON.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}, {Row: 1, Heading: true}})
+		So(response.JSON.CellFormats, ShouldResemble, []models.CellFormat{
+			{Row: 0, Column: 0, Rowspan: 2, VerticalAlign: models.AlignBottom},
+			{Row: 0, Column: 1, Colspan: 2, Align: models.AlignCenter},
+			{Row: 1, Column: 1, Align: models.AlignRight},
+			{Row: 1, Column: 2, Align: models.AlignRight},
+			{Row: 2, Column: 1, Align: models.AlignRight},
+			{Row: 2, Column: 2, Align: models.AlignLeft, VerticalAlign: models.AlignMiddle},
+			{Row: 3, Column: 1, Align: models.AlignRight},
+			{Row: 3, Column: 2, Align: models.AlignCenter},
+		})
+		So(response.JSON.ColumnFormats, ShouldResemble, []models.ColumnFormat{
+			{Column: 0, Width: "50%"},
+			{Column: 1, Width: "25%"},
+			{Column: 2, Width: "25%"},
+		})
+		So(response.Inferred, ShouldResemble, &parser.Inferred{HeaderRows: 2})
+		So(response.PreviewHTML, ShouldContainSubstring, "<table")
+	})
+
+	Convey("ParseOds should parse the chosen range of the chosen sheet, limiting merged cells to the range", t, func() {
+		request := &models.ParseRequest{Filename: "myFilename", Ods: createOds(odsSheets), Sheet: "Population", Range: "$B$1:c3", HeaderRows: 1}
+		response := invokeParseOds(request)
+
+		So(response.JSON.Data, ShouldResemble, [][]string{
+			{"Count", ""},
+			{"2020", "2021"},
+			{"1,234", "1,240.50"},
+		})
+		So(response.JSON.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}})
+		So(response.JSON.CellFormats[0], ShouldResemble, models.CellFormat{Row: 0, Column: 0, Colspan: 2, Align: models.AlignCenter})
+		So(response.JSON.ColumnFormats, ShouldResemble, []models.ColumnFormat{
+			{Column: 0, Width: "50%"},
+			{Column: 1, Width: "50%"},
+		})
+
+		request = &models.ParseRequest{Filename: "myFilename", Ods: createOds(odsSheets), Range: "A2:B2", CellSizeUnits: "auto"}
+		response = invokeParseOds(request)
+		So(response.JSON.Data, ShouldResemble, [][]string{{"", "2020"}})
+		So(response.JSON.CellFormats, ShouldBeEmpty)
+		So(response.JSON.ColumnFormats, ShouldResemble, []models.ColumnFormat{{Column: 1, Align: models.AlignRight}})
+	})
+
+	Convey("ParseOds should return an error for a document that can't be read, or a sheet or range that doesn't exist", t, func() {
+		_, err := parser.ParseOds(mockContext, &models.ParseRequest{Ods: []byte("not a zip")})
+		So(errors.Is(err, &models.Error{Code: models.CodeInvalidDocument}), ShouldBeTrue)
+
+		_, err = parser.ParseOds(mockContext, &models.ParseRequest{Ods: createOds(odsSheets), Sheet: "Missing"})
+		So(errors.Is(err, &models.Error{Code: models.CodeTableNotFound}), ShouldBeTrue)
+		So(err.(*models.Error).Details["sheets"], ShouldResemble, []string{"Population", "Empty"})
+
+		_, err = parser.ParseOds(mockContext, &models.ParseRequest{Ods: createOds(odsSheets), Sheet: "1"})
+		So(errors.Is(err, &models.Error{Code: models.CodeTableNotFound}), ShouldBeTrue)
+
+		_, err = parser.ParseOds(mockContext, &models.ParseRequest{Ods: createOds(odsSheets), Range: "A1"})
+		So(errors.Is(err, &models.Error{Code: models.CodeTableNotFound}), ShouldBeTrue)
+
+		_, err = parser.ParseOds(mockContext, &models.ParseRequest{Ods: createOds(odsSheets), Range: "A1:AMJ1048576"})
+		So(errors.Is(err, &models.Error{Code: models.CodeTableTooLarge}), ShouldBeTrue)
+	})
+
+	Convey("ParseOds should limit the spans and repeats of cells, and reject a range that exceeds the limits", t, func() {
+		sheet := `<table:table table:name="Spans"><table:table-row table:number-rows-repeated="4294967296">` +
+			`<table:table-cell table:number-rows-spanned="4294967296" table:number-columns-spanned="4294967296" office:value-type="string"><text:p>a</text:p></table:table-cell>` +
+			`</table:table-row></table:table>`
+		_, err := parser.ParseOds(mockContext, &models.ParseRequest{Ods: createOds(sheet)})
+		So(errors.Is(err, &models.Error{Code: models.CodeTableTooLarge}), ShouldBeTrue)
+
+		_, err = parser.ParseOds(mockContext, &models.ParseRequest{Ods: createOds(sheet), Range: "A1:B2"})
+		So(err, ShouldBeNil)
+
+		_, err = parser.ParseOds(mockContext, &models.ParseRequest{Ods: createOds(odsSheets), Limits: models.Limits{Rows: 3}})
+		So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+		So(err.(*models.Error).Details["limit"], ShouldEqual, "max_rows")
+	})
+
+	Convey("ParseOds should stop reading a content.xml with more elements than the limits allow, whichever sheet is chosen", t, func() {
+		sheets := odsSheets + `<table:table table:name="Large">` + strings.Repeat("<table:table-row><table:table-cell/></table:table-row>", 40000) + `</table:table>`
+		ods := createOds(sheets)
+		So(len(ods), ShouldBeLessThan, 20000)
+
+		_, err := parser.ParseOds(mockContext, &models.ParseRequest{Ods: ods, Sheet: "Population", Limits: models.Limits{Cells: 10}})
+		So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+		So(err.(*models.Error).Details["part"], ShouldEqual, "content.xml")
+
+		response := invokeParseOds(&models.ParseRequest{Ods: ods, Sheet: "Population", Limits: models.Limits{Cells: 5000}})
+		So(response.JSON.Data, ShouldHaveLength, 4)
+	})
+}
+
+// createOds creates an OpenDocument spreadsheet with the given sheets
+func createOds(sheets string) []byte {
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	parts := []struct{ name, content string }{
+		{"mimetype", "application/vnd.oasis.opendocument.spreadsheet"},
+		{"content.xml", `<?xml version="1.0" encoding="UTF-8"?><office:document-content ` + odsNamespaces + `>` +
+			odsAutomaticStyles + `<office:body><office:spreadsheet>` + sheets + `</office:spreadsheet></office:body></office:document-content>`},
+		{"styles.xml", odsStyles},
+	}
+	for _, part := range parts {
+		f, err := w.Create(part.name)
+		So(err, ShouldBeNil)
+		_, err = f.Write([]byte(part.content))
+		So(err, ShouldBeNil)
+	}
+	So(w.Close(), ShouldBeNil)
+	return b.Bytes()
+}
+
+func invokeParseOds(request *models.ParseRequest) *parser.ResponseModel {
+	resultBytes, err := parser.ParseOds(mockContext, request)
+	So(err, ShouldBeNil)
+
+	result := parser.ResponseModel{}
+	So(json.Unmarshal(resultBytes, &result), ShouldBeNil)
+	return &result
+}
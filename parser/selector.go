@@ -0,0 +1,343 @@
+package parser
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	compoundSelectorPattern = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9-]*|\*)?((?:[#.][-\w]+|\[[^\]]+\])*)$`)
+	simpleSelectorPattern   = regexp.MustCompile(`[#.][-\w]+|\[[^\]]+\]`)
+	headingAtoms            = []atom.Atom{atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6}
+	errUnsupportedSelector  = errors.New("unsupported selector") // the cause of the error for a selector that can't be parsed
+)
+
+// compoundSelector matches an element by its tag, id, classes and attributes, e.g. div#main.content[lang=en]
+type compoundSelector struct {
+	tag        string
+	id         string
+	classes    []string
+	attributes map[string]*string // the value an attribute must have, or nil if it need only be present
+	child      bool               // true if the element must be a child of that matched by the previous selector, rather than a descendant
+}
+
+// selectorMatcher matches elements against a selector from the top of the document down, remembering the state of each
+// node so that every element is compared with each compound selector only once, however the selector is combined
+type selectorMatcher struct {
+	selectors []compoundSelector
+	states    map[*html.Node]*matchState
+}
+
+// matchState records, for each compound selector, whether a node matches the selectors up to and including it, and
+// whether the node or any of its ancestors does
+type matchState struct {
+	matched []bool
+	within  []bool
+}
+
+// parseDocument parses html that may be a complete document or a fragment
+func parseDocument(tableHTML string) (*html.Node, error) {
+	doc, err := html.Parse(strings.NewReader(tableHTML))
+	if err != nil {
+		return nil, models.NewError(models.CodeInvalidTableHTML, "table_html could not be parsed", err)
+	}
+	return doc, nil
+}
+
+// selectTable returns the table in the document chosen by the selector: the index of the table among all the tables
+// in the document, or a css selector. If the selector matches an element that isn't a table, the first table inside it
+// is chosen. Without a selector, the first table is chosen.
+func selectTable(doc *html.Node, selector string) (*html.Node, error) {
+	tables := h.FindNodes(doc, atom.Table)
+	if len(tables) == 0 {
+		return nil, models.NewError(models.CodeInvalidTableHTML, "table_html does not contain a table element", nil)
+	}
+	selector = strings.TrimSpace(selector)
+	if len(selector) == 0 {
+		return tables[0], nil
+	}
+	if index, err := strconv.Atoi(selector); err == nil {
+		if index < 0 || index >= len(tables) {
+			return nil, tableNotFound(selector, nil)
+		}
+		return tables[index], nil
+	}
+	selectors, err := parseSelector(selector)
+	if err != nil {
+		return nil, tableNotFound(selector, err)
+	}
+	matcher := newSelectorMatcher(selectors)
+	var table *html.Node
+	walk(doc, func(node *html.Node) bool {
+		if matcher.matches(node) {
+			table = node
+			if node.DataAtom != atom.Table {
+				table = h.FindNode(node, atom.Table)
+			}
+		}
+		return table == nil
+	})
+	if table == nil {
+		return nil, tableNotFound(selector, nil)
+	}
+	return table, nil
+}
+
+// tableNotFound returns the error for a selector that doesn't choose a table
+func tableNotFound(selector string, err error) error {
+	message := "table_selector does not match a table in table_html"
+	if err != nil {
+		message = "table_selector is not a supported selector"
+	}
+	return &models.Error{
+		Code:    models.CodeTableNotFound,
+		Message: message,
+		Details: map[string]interface{}{"table_selector": selector},
+		Err:     err,
+	}
+}
+
+// parseSelector parses a css selector made of compound selectors combined by descendant or child combinators, e.g.
+// "#main > div.content table"
+func parseSelector(selector string) ([]compoundSelector, error) {
+	var selectors []compoundSelector
+	child := false
+	for _, token := range strings.Fields(strings.ReplaceAll(selector, ">", " > ")) {
+		if token == ">" {
+			if len(selectors) == 0 || child {
+				return nil, errUnsupportedSelector
+			}
+			child = true
+			continue
+		}
+		match := compoundSelectorPattern.FindStringSubmatch(token)
+		if match == nil {
+			return nil, errUnsupportedSelector
+		}
+		compound := compoundSelector{tag: strings.ToLower(strings.TrimPrefix(match[1], "*")), child: child}
+		for _, simple := range simpleSelectorPattern.FindAllString(match[2], -1) {
+			switch simple[0] {
+			case '#':
+				compound.id = simple[1:]
+			case '.':
+				compound.classes = append(compound.classes, simple[1:])
+			default:
+				if compound.attributes == nil {
+					compound.attributes = make(map[string]*string)
+				}
+				name, value, found := strings.Cut(simple[1:len(simple)-1], "=")
+				name = strings.ToLower(strings.TrimSpace(name))
+				if found {
+					value = strings.Trim(strings.TrimSpace(value), `"'`)
+					compound.attributes[name] = &value
+				} else {
+					compound.attributes[name] = nil
+				}
+			}
+		}
+		selectors = append(selectors, compound)
+		child = false
+	}
+	if len(selectors) == 0 || child {
+		return nil, errUnsupportedSelector
+	}
+	return selectors, nil
+}
+
+// newSelectorMatcher creates a matcher for the selectors parsed by parseSelector
+func newSelectorMatcher(selectors []compoundSelector) *selectorMatcher {
+	return &selectorMatcher{selectors: selectors, states: make(map[*html.Node]*matchState)}
+}
+
+// matches returns true if the node is an element that matches the last of the selectors, and its ancestors match the others
+func (m *selectorMatcher) matches(node *html.Node) bool {
+	return m.state(node).matched[len(m.selectors)-1]
+}
+
+// state returns the state of the node, derived from that of its parent. A node matches the selectors up to selector i
+// if it matches selector i, and its parent (for a child combinator) or the parent or one of its ancestors (for a
+// descendant combinator) matches the selectors up to selector i-1.
+func (m *selectorMatcher) state(node *html.Node) *matchState {
+	if state, ok := m.states[node]; ok {
+		return state
+	}
+	state := &matchState{matched: make([]bool, len(m.selectors)), within: make([]bool, len(m.selectors))}
+	if node.Parent != nil {
+		parent := m.state(node.Parent)
+		for i, selector := range m.selectors {
+			if node.Type == html.ElementNode && selector.matches(node) {
+				switch {
+				case i == 0:
+					state.matched[i] = true
+				case selector.child:
+					state.matched[i] = parent.matched[i-1]
+				default:
+					state.matched[i] = parent.within[i-1]
+				}
+			}
+			state.within[i] = state.matched[i] || parent.within[i]
+		}
+	}
+	m.states[node] = state
+	return state
+}
+
+// matches returns true if the node has the tag, id, classes and attributes of the selector
+func (s compoundSelector) matches(node *html.Node) bool {
+	if len(s.tag) > 0 && node.Data != s.tag {
+		return false
+	}
+	if len(s.id) > 0 && h.GetAttribute(node, "id") != s.id {
+		return false
+	}
+	for _, class := range s.classes {
+		if !hasClass(node, class) {
+			return false
+		}
+	}
+	for name, value := range s.attributes {
+		if !hasAttribute(node, name) || (value != nil && h.GetAttribute(node, name) != *value) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAttribute returns true if the node has the attribute, whatever its value
+func hasAttribute(node *html.Node, name string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// walk visits each node below the given node in document order until visit returns false, returning false if it did
+func walk(node *html.Node, visit func(*html.Node) bool) bool {
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		if !visit(c) || !walk(c, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// findTitle returns the title of the table, as found by findTitles
+func findTitle(table *html.Node) string {
+	return findTitles(root(table))[table]
+}
+
+// findTitles returns the title of every table in the document, found in one pass over it: the text of the caption of
+// the table, or else the figcaption of a figure containing the table, or else the nearest heading before the table
+// that comes after any other table
+func findTitles(doc *html.Node) map[*html.Node]string {
+	titles := make(map[*html.Node]string)
+	figcaptions := make(map[*html.Node]*html.Node) // the first figcaption of each figure, found once
+	var heading *html.Node
+	walk(doc, func(node *html.Node) bool {
+		switch {
+		case isHeading(node):
+			heading = node
+		case node.DataAtom == atom.Table:
+			titles[node] = tableTitle(node, heading, figcaptions)
+			heading = nil
+		}
+		return true
+	})
+	return titles
+}
+
+// tableTitle returns the text of the caption of the table, or else the figcaption of a figure containing the table, or
+// else the heading before it
+func tableTitle(table *html.Node, heading *html.Node, figcaptions map[*html.Node]*html.Node) string {
+	for c := table.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == atom.Caption {
+			return nodeText(c)
+		}
+	}
+	for ancestor := table.Parent; ancestor != nil; ancestor = ancestor.Parent {
+		if ancestor.DataAtom == atom.Figure {
+			figcaption, ok := figcaptions[ancestor]
+			if !ok {
+				figcaption = h.FindNode(ancestor, atom.Figcaption)
+				figcaptions[ancestor] = figcaption
+			}
+			if figcaption != nil {
+				return nodeText(figcaption)
+			}
+		}
+	}
+	if heading == nil {
+		return ""
+	}
+	return nodeText(heading)
+}
+
+// findNotes returns the text of the items of the first list after the table that comes before any heading or table
+func findNotes(table *html.Node) []string {
+	var notes []string
+	after := false
+	walk(root(table), func(node *html.Node) bool {
+		if node == table {
+			after = true
+			return true
+		}
+		if !after || isInside(node, table) {
+			return true
+		}
+		if isHeading(node) || node.DataAtom == atom.Table {
+			return false
+		}
+		if node.DataAtom == atom.Ol || node.DataAtom == atom.Ul {
+			for item := node.FirstChild; item != nil; item = item.NextSibling {
+				if item.DataAtom == atom.Li {
+					notes = append(notes, nodeText(item))
+				}
+			}
+			return false
+		}
+		return true
+	})
+	return notes
+}
+
+// root returns the document containing the node
+func root(node *html.Node) *html.Node {
+	for node.Parent != nil {
+		node = node.Parent
+	}
+	return node
+}
+
+// isInside returns true if the node is a descendant of the ancestor
+func isInside(node *html.Node, ancestor *html.Node) bool {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		if parent == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// isHeading returns true for the elements h1 to h6
+func isHeading(node *html.Node) bool {
+	for _, a := range headingAtoms {
+		if node.DataAtom == a {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeText returns the text of a node, with runs of whitespace collapsed to a single space
+func nodeText(node *html.Node) string {
+	return strings.Join(strings.Fields(h.GetText(node)), " ")
+}
@@ -0,0 +1,142 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const page = `<!DOCTYPE html>
+<html><head><title>Statistics</title></head>
+<body>
+<h1>Statistics</h1>
+<nav><ul><li>Home</li></ul></nav>
+<h2>Population</h2>
+<table id="population"><tr><td>Wales</td><td>3.1</td></tr></table>
+<p>Notes</p>
+<ol><li>Estimated</li><li> Rounded
+ to one decimal place</li></ol>
+<div class="content">
+<figure><figcaption>Area</figcaption><table class="data wide"><tr><td>Wales</td><td>20,779</td><td>km2</td></tr><tr><td>Scotland</td><td>77,910</td><td>km2</td></tr></table></figure>
+<section><table data-kind="summary"><caption>Summary</caption><tr><td>2</td></tr></table></section>
+</div>
+</body></html>`
+
+func TestParseHTML_TableSelector(t *testing.T) {
+
+	Convey("ParseHTML should choose a table from a page", t, func() {
+		selectors := map[string]string{
+			"":                                  "Wales 3.1",
+			"1":                                 "Wales 20,779",
+			"#population":                       "Wales 3.1",
+			".data":                             "Wales 20,779",
+			"table.data.wide":                   "Wales 20,779",
+			"div.content table":                 "Wales 20,779",
+			"figure > table":                    "Wales 20,779",
+			"[data-kind=summary]":               "2",
+			"table[data-kind='summary']":        "2",
+			"body > div section":                "2",
+			"section":                           "2",
+			"div.content > section > table":     "2",
+			"html body div.content figure.none": "",
+			"html div > figure > table":         "Wales 20,779",
+			"body > figure table":               "",
+			"body div > section table":          "2",
+		}
+		for selector, expected := range selectors {
+			request := &models.ParseRequest{Filename: "page", TableHTML: page, TableSelector: selector}
+			body, err := parser.ParseHTML(mockContext, request)
+			if len(expected) == 0 {
+				So(errors.Is(err, &models.Error{Code: models.CodeTableNotFound}), ShouldBeTrue)
+				continue
+			}
+			So(err, ShouldBeNil)
+			var response parser.ResponseModel
+			So(json.Unmarshal(body, &response), ShouldBeNil)
+			first := response.JSON.Data[0][0]
+			if len(response.JSON.Data[0]) > 1 {
+				first += " " + response.JSON.Data[0][1]
+			}
+			So(first, ShouldEqual, expected)
+		}
+	})
+
+	Convey("ParseHTML should reject a selector that doesn't choose a table", t, func() {
+		for _, selector := range []string{"3", "-1", "#missing", "div > > table", "table:first-child", "> table"} {
+			request := &models.ParseRequest{Filename: "page", TableHTML: page, TableSelector: selector}
+			_, err := parser.ParseHTML(mockContext, request)
+			var modelError *models.Error
+			So(errors.As(err, &modelError), ShouldBeTrue)
+			So(modelError.Code, ShouldEqual, models.CodeTableNotFound)
+			So(modelError.Details["table_selector"], ShouldEqual, selector)
+		}
+	})
+
+	Convey("ParseHTML should take the title and footnotes from the page when they aren't given", t, func() {
+		request := &models.ParseRequest{Filename: "page", TableHTML: page, TableSelector: "#population", InferStructure: true}
+		response := invokeParseHTMLWithRequest(request)
+		So(response.JSON.Title, ShouldEqual, "Population")
+		So(response.JSON.Footnotes, ShouldResemble, []string{"Estimated", "Rounded to one decimal place"})
+		So(response.Inferred.Title, ShouldEqual, "Population")
+		So(response.Inferred.Footnotes, ShouldResemble, []string{"Estimated", "Rounded to one decimal place"})
+
+		request = &models.ParseRequest{Filename: "page", TableHTML: page, TableSelector: ".data"}
+		response = invokeParseHTMLWithRequest(request)
+		So(response.JSON.Title, ShouldEqual, "Area")
+		So(response.JSON.Footnotes, ShouldBeEmpty)
+
+		request = &models.ParseRequest{Filename: "page", TableHTML: page, TableSelector: "section"}
+		response = invokeParseHTMLWithRequest(request)
+		So(response.JSON.Title, ShouldEqual, "Summary")
+
+		request = &models.ParseRequest{Filename: "page", TableHTML: page, Title: "Mine", Footnotes: []string{"Mine"}, InferStructure: true}
+		response = invokeParseHTMLWithRequest(request)
+		So(response.JSON.Title, ShouldEqual, "Mine")
+		So(response.JSON.Footnotes, ShouldResemble, []string{"Mine"})
+		So(response.Inferred.Title, ShouldBeEmpty)
+		So(response.Inferred.Footnotes, ShouldBeEmpty)
+	})
+
+	Convey("ParseHTML should match a selector with many combinators against deeply nested elements in linear time", t, func() {
+		nested := strings.Repeat("<div>", 60) + "<table><tr><td>a</td></tr></table>" + strings.Repeat("</div>", 60)
+		selector := "section " + strings.Repeat("div ", 30) + "table"
+		_, err := parser.ParseHTML(mockContext, &models.ParseRequest{Filename: "page", TableHTML: nested, TableSelector: selector})
+		So(errors.Is(err, &models.Error{Code: models.CodeTableNotFound}), ShouldBeTrue)
+
+		selector = "body > " + strings.Repeat("div > ", 59) + "div table"
+		response := invokeParseHTMLWithRequest(&models.ParseRequest{Filename: "page", TableHTML: nested, TableSelector: selector})
+		So(response.JSON.Data, ShouldResemble, [][]string{{"a"}})
+	})
+
+	Convey("ParseHTML should reject html without a table", t, func() {
+		_, err := parser.ParseHTML(mockContext, &models.ParseRequest{Filename: "page", TableHTML: "<p>no tables</p>"})
+		So(errors.Is(err, &models.Error{Code: models.CodeInvalidTableHTML}), ShouldBeTrue)
+	})
+}
+
+func TestListTables(t *testing.T) {
+
+	Convey("ListTables should describe every table in a page", t, func() {
+		body, err := parser.ListTables(mockContext, &models.ParseRequest{Filename: "page", TableHTML: page})
+		So(err, ShouldBeNil)
+
+		var response parser.TablesResponse
+		So(json.Unmarshal(body, &response), ShouldBeNil)
+		So(response.Tables, ShouldResemble, []parser.TableSummary{
+			{Index: 0, ID: "population", Title: "Population", Rows: 1, Columns: 2},
+			{Index: 1, Class: "data wide", Title: "Area", Rows: 2, Columns: 3},
+			{Index: 2, Title: "Summary", Rows: 1, Columns: 1},
+		})
+	})
+
+	Convey("ListTables should return an empty list for html without a table", t, func() {
+		body, err := parser.ListTables(mockContext, &models.ParseRequest{Filename: "page", TableHTML: "<p>no tables</p>"})
+		So(err, ShouldBeNil)
+		So(string(body), ShouldEqual, `{"tables":[]}`)
+	})
+}
@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"golang.org/x/net/html"
+)
+
+// alignment is the horizontal and vertical alignment of a cell
+type alignment struct {
+	align  string // Left, Center, Right or Justify, or empty
+	valign string // Top, Middle or Bottom, or empty
+}
+
+var (
+	// cssAlignments maps the values of text-align and the align attribute to an Alignment
+	cssAlignments = map[string]string{
+		"left":    models.AlignLeft,
+		"start":   models.AlignLeft,
+		"center":  models.AlignCenter,
+		"middle":  models.AlignCenter,
+		"right":   models.AlignRight,
+		"end":     models.AlignRight,
+		"justify": models.AlignJustify,
+	}
+	// cssVerticalAlignments maps the values of vertical-align and the valign attribute to an Alignment
+	cssVerticalAlignments = map[string]string{
+		"top":    models.AlignTop,
+		"middle": models.AlignMiddle,
+		"center": models.AlignMiddle,
+		"bottom": models.AlignBottom,
+	}
+	pixelAttributePattern = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// parseAlignments finds the alignment of each cell. A cell without an alignment of its own takes that of its row, then
+// that of the thead, tbody or tfoot containing the row, then that of its col element.
+func parseAlignments(model *parseModel) [][]alignment {
+	alignments := make([][]alignment, len(model.cells))
+	for r, row := range model.cells {
+		alignments[r] = make([]alignment, len(row))
+		var inherited []*html.Node
+		if r < len(model.rows) {
+			inherited = append(inherited, model.rows[r], model.rows[r].Parent)
+		}
+		for c, cell := range row {
+			if cell == nil {
+				continue
+			}
+			nodes := append([]*html.Node{cell}, inherited...)
+			if c < len(model.cols) {
+				nodes = append(nodes, model.cols[c])
+			}
+			for _, node := range nodes {
+				align, valign := model.nodeAlignment(node)
+				if len(alignments[r][c].align) == 0 {
+					alignments[r][c].align = align
+				}
+				if len(alignments[r][c].valign) == 0 {
+					alignments[r][c].valign = valign
+				}
+			}
+		}
+	}
+	return alignments
+}
+
+// nodeAlignment returns the alignment given by the inline style of a node, or else its align and valign attributes,
+// or else its classes
+func (model *parseModel) nodeAlignment(node *html.Node) (string, string) {
+	if node == nil || node.Type != html.ElementNode {
+		return "", ""
+	}
+	align := cssAlignments[strings.ToLower(styleProperty(node, "text-align"))]
+	if len(align) == 0 {
+		align = cssAlignments[strings.ToLower(h.GetAttribute(node, "align"))]
+	}
+	valign := cssVerticalAlignments[strings.ToLower(styleProperty(node, "vertical-align"))]
+	if len(valign) == 0 {
+		valign = cssVerticalAlignments[strings.ToLower(h.GetAttribute(node, "valign"))]
+	}
+	for _, class := range strings.Fields(h.GetAttribute(node, "class")) {
+		if len(align) == 0 {
+			align = model.alignMap[class]
+		}
+		if len(valign) == 0 {
+			valign = model.valignMap[class]
+		}
+	}
+	return align, valign
+}
+
+// styleProperty returns the value of a property in the inline style of a node, or an empty string
+func styleProperty(node *html.Node, property string) string {
+	for _, declaration := range strings.Split(h.GetAttribute(node, "style"), ";") {
+		name, value, found := strings.Cut(declaration, ":")
+		if found && strings.EqualFold(strings.TrimSpace(name), property) {
+			return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		}
+	}
+	return ""
+}
+
+// lengthOf returns a width or height of a node from its inline style, or else from its legacy attribute, where a
+// number is in pixels
+func lengthOf(node *html.Node, property string) string {
+	if length := styleProperty(node, property); len(length) > 0 {
+		return length
+	}
+	length := strings.TrimSpace(h.GetAttribute(node, property))
+	if pixelAttributePattern.MatchString(length) {
+		return length + "px"
+	}
+	return length
+}
+
+// parseLengths returns the widths given for each column, from its col element and then the cells that span only that
+// column, and the heights given for each row, from its tr element and then the cells that span only that row
+func parseLengths(model *parseModel) ([][]string, [][]string) {
+	numColumns := 0
+	if len(model.cells) > 0 {
+		numColumns = len(model.cells[0])
+	}
+	widths := make([][]string, max(numColumns, len(model.cols)))
+	for c := range widths {
+		nodes := model.columnCells(c)
+		if c < len(model.cols) {
+			nodes = append([]*html.Node{model.cols[c]}, nodes...)
+		}
+		for _, node := range nodes {
+			widths[c] = append(widths[c], lengthOf(node, "width"))
+		}
+	}
+	heights := make([][]string, len(model.cells))
+	for r := range heights {
+		nodes := model.rowCells(r)
+		if r < len(model.rows) {
+			nodes = append([]*html.Node{model.rows[r]}, nodes...)
+		}
+		for _, node := range nodes {
+			heights[r] = append(heights[r], lengthOf(node, "height"))
+		}
+	}
+	return widths, heights
+}
+
+// columnCells returns the cells in column c that don't span other columns
+func (model *parseModel) columnCells(c int) []*html.Node {
+	var cells []*html.Node
+	for r, row := range model.cells {
+		if c < len(row) && row[c] != nil && model.merges[r][c].colspan <= 1 {
+			cells = append(cells, row[c])
+		}
+	}
+	return cells
+}
+
+// rowCells returns the cells in row r that don't span other rows
+func (model *parseModel) rowCells(r int) []*html.Node {
+	var cells []*html.Node
+	for c, cell := range model.cells[r] {
+		if cell != nil && model.merges[r][c].rowspan <= 1 {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
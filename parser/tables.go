@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"golang.org/x/net/html/atom"
+)
+
+// TableSummary describes a table found in html, so that it can be chosen with a table selector
+type TableSummary struct {
+	Index   int    `json:"index"`           // the index of the table, which selects it
+	ID      string `json:"id,omitempty"`    // the id of the table element
+	Class   string `json:"class,omitempty"` // the class of the table element
+	Title   string `json:"title,omitempty"` // the caption, figcaption or nearest heading before the table
+	Rows    int    `json:"rows"`
+	Columns int    `json:"columns"`
+}
+
+// TablesResponse defines the format of the json returned from ListTables
+type TablesResponse struct {
+	Tables []TableSummary `json:"tables"`
+}
+
+// ListTables finds every table in the html of the request, which may be a complete document or a fragment
+func ListTables(ctx context.Context, request *models.ParseRequest) ([]byte, error) {
+	doc, err := parseDocument(request.TableHTML)
+	if err != nil {
+		log.Error(ctx, "Unable to parse TableHTML", err)
+		return nil, err
+	}
+	response := TablesResponse{Tables: []TableSummary{}}
+	titles := findTitles(doc)
+	for i, table := range h.FindNodes(doc, atom.Table) {
+		cells, _, err := getCells(table, request.CellLayout, false, false, request.Limits)
+		if err != nil {
+			log.Error(ctx, "Unable to find the cells of a table", err, log.Data{"index": i})
+			return nil, err
+		}
+		summary := TableSummary{
+			Index: i,
+			ID:    h.GetAttribute(table, "id"),
+			Class: h.GetAttribute(table, "class"),
+			Title: titles[table],
+			Rows:  len(cells),
+		}
+		if len(cells) > 0 {
+			summary.Columns = len(cells[0])
+		}
+		response.Tables = append(response.Tables, summary)
+	}
+	return json.Marshal(response)
+}
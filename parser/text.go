@@ -0,0 +1,256 @@
+package parser
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"golang.org/x/net/html"
+)
+
+const (
+	tabWidth     = 8 // the number of characters between tab stops in fixed-width text
+	minColumnGap = 2 // the number of blank characters that separate columns detected from the text
+)
+
+// rulePattern matches a line of fixed-width text that only rules off the rows above, e.g. '-----  ------' or '==+=='
+var rulePattern = regexp.MustCompile(`^[ +|]*[-=]{2,}[-=+| ]*$`)
+
+// textTable is a table of fixed-width text, split into lines
+type textTable struct {
+	lines   [][]rune // the lines containing cells, with tabs expanded and without trailing spaces
+	rule    []rune   // the first rule with lines both above and below it, if any
+	ruleRow int      // the number of lines above the rule
+}
+
+// textCell is the value of a cell in fixed-width text, and the columns of characters it occupies
+type textCell struct {
+	value string
+	start int // the position of the first character of the value, or -1 if the cell is empty
+	end   int // the position after the last character of the value
+}
+
+// ParseText converts a table of fixed-width text in the request into the json used to render the table, along with
+// the preview html
+func ParseText(ctx context.Context, request *models.ParseRequest) ([]byte, error) {
+	if len(strings.TrimSpace(request.TableText)) == 0 {
+		return nil, models.NewMissingFieldsError([]string{"table_text"})
+	}
+	return createResponse(ctx, func() (*parseModel, error) { return createTextModel(request) })
+}
+
+// createTextModel creates a model from fixed-width text. The columns begin at the requested boundaries, or else after
+// the runs of a rule below the headings, or else after each gap of at least two characters that are blank on every
+// line. Headings are the lines above a rule, or else the lines without numbers above the first line with numbers.
+// An error is returned if the lines and columns exceed the limits of the request.
+func createTextModel(request *models.ParseRequest) (*parseModel, error) {
+	table := splitTextLines(request.TableText)
+	starts := columnStarts(request.ColumnBoundaries)
+	if len(starts) == 0 {
+		starts = table.columnStarts()
+	}
+	if err := request.Limits.CheckTableSize(len(table.lines), len(starts)); err != nil {
+		return nil, err
+	}
+
+	cells := make([][]textCell, len(table.lines))
+	values := make([][]string, len(table.lines))
+	for r, line := range table.lines {
+		cells[r] = splitTextLine(line, starts)
+		values[r] = make([]string, len(cells[r]))
+		for c, cell := range cells[r] {
+			values[r][c] = html.EscapeString(cell.value)
+		}
+	}
+
+	inferred := &Inferred{}
+	headerRows := request.HeaderRows
+	if headerRows == 0 {
+		headerRows = table.ruleRow
+		if table.rule == nil {
+			headerRows = countLeadingTextRows(values)
+		}
+		inferred.HeaderRows = headerRows
+	}
+
+	aligns := textAlignments(cells, headerRows, len(starts))
+	aligns = inferColumnAlignments(aligns, values, headerRows)
+	for c, align := range aligns {
+		// left is the default alignment, but a column aligned to the left isn't inferred to be aligned to the right
+		if align == models.AlignLeft {
+			aligns[c] = ""
+		}
+	}
+
+	model, err := newValuesModel(request, values, aligns)
+	if err != nil {
+		return nil, err
+	}
+	model.headerRows = headerRows
+	model.markers, model.footnotes = newFootnoteMarkers(model.footnotes)
+	model.inferred = inferred
+	return model, nil
+}
+
+// splitTextLines splits text into the lines of a table, expanding tabs and leaving out blank lines and rules
+func splitTextLines(text string) *textTable {
+	table := &textTable{}
+	var rule []rune
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(strings.TrimRightFunc(expandTabs(line), unicode.IsSpace))
+		switch {
+		case len(runes) == 0:
+			continue
+		case rulePattern.MatchString(string(runes)):
+			if rule == nil && len(table.lines) > 0 {
+				rule = runes
+				table.ruleRow = len(table.lines)
+			}
+		default:
+			if rule != nil && table.rule == nil {
+				table.rule = rule
+			}
+			table.lines = append(table.lines, runes)
+		}
+	}
+	if table.rule == nil {
+		table.ruleRow = 0
+	}
+	return table
+}
+
+// expandTabs replaces each tab with spaces to the next tab stop
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range line {
+		if r == '\t' {
+			for ok := true; ok; ok = n%tabWidth != 0 {
+				b.WriteRune(' ')
+				n++
+			}
+			continue
+		}
+		b.WriteRune(r)
+		n++
+	}
+	return b.String()
+}
+
+// columnStarts returns the position at which each column begins, from the requested boundaries of the columns after
+// the first, or nothing if no boundaries were requested
+func columnStarts(boundaries []int) []int {
+	if len(boundaries) == 0 {
+		return nil
+	}
+	starts := []int{0}
+	sorted := append([]int(nil), boundaries...)
+	sort.Ints(sorted)
+	for _, boundary := range sorted {
+		if boundary > starts[len(starts)-1] {
+			starts = append(starts, boundary)
+		}
+	}
+	return starts
+}
+
+// columnStarts returns the position at which each column begins: after each run of the rule below the headings, if it
+// has more than one, or else after each gap of at least two characters that are blank on every line
+func (t *textTable) columnStarts() []int {
+	starts := []int{0}
+	rule := []rune(strings.NewReplacer("+", " ", "|", " ").Replace(string(t.rule)))
+	if len(strings.Fields(string(rule))) > 1 {
+		// each column begins after the run before it, so that a value aligned to the right may extend into the gap
+		for i := 1; i+1 < len(rule); i++ {
+			if rule[i] == ' ' && rule[i-1] != ' ' {
+				starts = append(starts, i)
+			}
+		}
+		return starts
+	}
+
+	var occupied []bool
+	for _, line := range t.lines {
+		for len(occupied) < len(line) {
+			occupied = append(occupied, false)
+		}
+		for i, r := range line {
+			if !unicode.IsSpace(r) {
+				occupied[i] = true
+			}
+		}
+	}
+	// a single space is taken to separate words rather than columns
+	for i := minColumnGap; i < len(occupied); i++ {
+		if occupied[i] && !slices.Contains(occupied[i-minColumnGap:i], true) {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// splitTextLine splits a line into the cells of the columns beginning at the given positions
+func splitTextLine(line []rune, starts []int) []textCell {
+	cells := make([]textCell, len(starts))
+	for c, start := range starts {
+		cells[c].start = -1
+		end := len(line)
+		if c+1 < len(starts) {
+			end = min(starts[c+1], len(line))
+		}
+		if start >= end {
+			continue
+		}
+		text := string(line[start:end])
+		trimmed := strings.TrimLeftFunc(text, unicode.IsSpace)
+		value := strings.TrimRightFunc(trimmed, unicode.IsSpace)
+		if len(value) == 0 {
+			continue
+		}
+		cells[c].value = value
+		cells[c].start = start + len([]rune(text)) - len([]rune(trimmed))
+		cells[c].end = cells[c].start + len([]rune(value))
+	}
+	return cells
+}
+
+// textAlignments returns the alignment of each column given by the position of its values below the headings: Right if
+// they end in the same place but don't all start in the same place, Left if the reverse, or Center if neither but their
+// middles are in the same place
+func textAlignments(cells [][]textCell, headerRows int, numColumns int) []string {
+	aligns := make([]string, numColumns)
+	for c := range aligns {
+		var column []textCell
+		for r := headerRows; r < len(cells); r++ {
+			if c < len(cells[r]) && cells[r][c].start >= 0 {
+				column = append(column, cells[r][c])
+			}
+		}
+		if len(column) < 2 {
+			continue
+		}
+		sameStart, sameEnd, sameMiddle := true, true, true
+		for _, cell := range column[1:] {
+			sameStart = sameStart && cell.start == column[0].start
+			sameEnd = sameEnd && cell.end == column[0].end
+			middle := cell.start + cell.end - column[0].start - column[0].end
+			sameMiddle = sameMiddle && middle >= -1 && middle <= 1
+		}
+		switch {
+		case sameEnd && !sameStart:
+			aligns[c] = models.AlignRight
+		case sameStart && !sameEnd:
+			aligns[c] = models.AlignLeft
+		case !sameStart && !sameEnd && sameMiddle:
+			aligns[c] = models.AlignCenter
+		}
+	}
+	return aligns
+}
@@ -0,0 +1,111 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/parser"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseText(t *testing.T) {
+
+	Convey("ParseText should split columns after the runs of the rule below the headings", t, func() {
+		text := `
+Region           Males    Females
+               (000s)     (000s)
+---------------  -------  -------
+North East         1,234        987
+London            12,345      9,876
+Wales*               567         ..
+`
+		response := invokeParseText(&models.ParseRequest{Filename: "myFilename", TableText: text, Footnotes: []string{"Provisional"}})
+
+		So(response.JSON.Data, ShouldResemble, [][]string{
+			{"Region", "Males", "Females"},
+			{"", "(000s)", "(000s)"},
+			{"North East", "1,234", "987"},
+			{"London", "12,345", "9,876"},
+			{"Wales[1]", "567", ".."},
+		})
+		So(response.JSON.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}, {Row: 1, Heading: true}})
+		So(response.JSON.ColumnFormats, ShouldResemble, []models.ColumnFormat{
+			{Column: 1, Align: models.AlignRight},
+			{Column: 2, Align: models.AlignRight},
+		})
+		So(response.Inferred.HeaderRows, ShouldEqual, 2)
+		So(response.PreviewHTML, ShouldContainSubstring, "<table")
+	})
+
+	Convey("ParseText should detect columns from the gaps that are blank on every line", t, func() {
+		text := "Name    Count  Status\n" +
+			"alpha       5  in progress\n" +
+			"b & c     120    done\n" +
+			"delta      17  a  b"
+		response := invokeParseText(&models.ParseRequest{Filename: "myFilename", TableText: text})
+
+		So(response.JSON.Data, ShouldResemble, [][]string{
+			{"Name", "Count", "Status"},
+			{"alpha", "5", "in progress"},
+			{"b &amp; c", "120", "done"},
+			{"delta", "17", "a b"},
+		})
+		So(response.JSON.RowFormats, ShouldResemble, []models.RowFormat{{Row: 0, Heading: true}})
+		So(response.JSON.ColumnFormats, ShouldResemble, []models.ColumnFormat{{Column: 1, Align: models.AlignRight}})
+	})
+
+	Convey("ParseText should use the requested column boundaries and headings, expanding tabs", t, func() {
+		text := "Year Total\n2020\t5\n2021 10"
+		request := &models.ParseRequest{Filename: "myFilename", TableText: text, ColumnBoundaries: []int{5}, HeaderRows: 1}
+		response := invokeParseText(request)
+
+		So(response.JSON.Data, ShouldResemble, [][]string{{"Year", "Total"}, {"2020", "5"}, {"2021", "10"}})
+		So(response.Inferred, ShouldResemble, &parser.Inferred{})
+	})
+
+	Convey("ParseText should align columns by the position of their values", t, func() {
+		text := "a     left   right   centre\n" +
+			"b     x          xx    xxx\n" +
+			"c     xxxx        x     x"
+		response := invokeParseText(&models.ParseRequest{Filename: "myFilename", TableText: text, HeaderRows: 1})
+
+		So(response.JSON.ColumnFormats, ShouldResemble, []models.ColumnFormat{
+			{Column: 2, Align: models.AlignRight},
+			{Column: 3, Align: models.AlignCenter},
+		})
+	})
+
+	Convey("ParseText should return an error before creating the cells of text that exceeds the limits", t, func() {
+		text := strings.Repeat("x  ", 300) + strings.Repeat("\ny", 20)
+		for limit, limits := range map[string]models.Limits{
+			"max_rows":    {Rows: 20},
+			"max_columns": {Columns: 299},
+			"max_cells":   {Cells: 6000},
+		} {
+			_, err := parser.ParseText(mockContext, &models.ParseRequest{Filename: "myFilename", TableText: text, Limits: limits})
+			So(models.ErrorCode(err), ShouldEqual, models.CodeTableTooLarge)
+			So(err.(*models.Error).Details["limit"], ShouldEqual, limit)
+		}
+
+		response := invokeParseText(&models.ParseRequest{Filename: "myFilename", TableText: text, Limits: models.Limits{Cells: 6300}})
+		So(response.JSON.Data, ShouldHaveLength, 21)
+		So(response.JSON.Data[20], ShouldHaveLength, 300)
+	})
+
+	Convey("ParseText should return an error when there is no text", t, func() {
+		_, err := parser.ParseText(mockContext, &models.ParseRequest{Filename: "myFilename", TableText: " \n "})
+		So(err, ShouldNotBeNil)
+		So(err.(*models.Error).Code, ShouldEqual, models.CodeMissingFields)
+	})
+}
+
+func invokeParseText(request *models.ParseRequest) *parser.ResponseModel {
+	resultBytes, err := parser.ParseText(mockContext, request)
+	So(err, ShouldBeNil)
+
+	result := parser.ResponseModel{}
+	So(json.Unmarshal(resultBytes, &result), ShouldBeNil)
+	return &result
+}
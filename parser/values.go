@@ -0,0 +1,176 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// numberPattern matches a number as written in a statistical table, e.g. -1,234.5, £12, 45%, or 3.2e-4
+	numberPattern = regexp.MustCompile(`^[-+−]?[£$€]?(?:[0-9][0-9,]*(?:\.[0-9]+)?|\.[0-9]+)(?:[eE][-+]?[0-9]+)?%?$`)
+	// shorthandPattern matches the shorthand used in place of a number, e.g. .. for not available, or x for suppressed
+	shorthandPattern = regexp.MustCompile(`^(?:\.\.|[-–—:xz*]|\[[a-z]])$`)
+)
+
+// textRun is a run of text in a paragraph of a document with the same formatting
+type textRun struct {
+	text        string
+	bold        bool
+	italic      bool
+	superscript bool
+	subscript   bool
+	link        string // the url of the hyperlink containing the run
+}
+
+// newCell creates a td element containing a value in the canonical inline markup, in which \n is a line break
+func newCell(value string) *html.Node {
+	cell := h.CreateNode("td", atom.Td)
+	nodes, err := h.ParseInline(strings.ReplaceAll(value, "\n", "<br>"))
+	if err != nil {
+		cell.AppendChild(h.Text(value))
+		return cell
+	}
+	for _, node := range nodes {
+		cell.AppendChild(node)
+	}
+	return cell
+}
+
+// newValuesModel creates a model of a table given as the values of its cells, rather than html, with the alignment of
+// each column (Left, Center, Right, or empty). Rows are filled with empty cells to the width of the widest row, unless
+// that would exceed the limits of the request, when an error is returned.
+func newValuesModel(request *models.ParseRequest, values [][]string, aligns []string) (*parseModel, error) {
+	model := parseModel{
+		request:    request,
+		cellLayout: models.CellLayoutStandard,
+		headerRows: request.HeaderRows,
+		headerCols: request.HeaderCols,
+		title:      request.Title,
+		footnotes:  parseFootnotes(request.Footnotes),
+	}
+	numColumns := len(aligns)
+	for _, row := range values {
+		numColumns = max(numColumns, len(row))
+	}
+	if err := request.Limits.CheckTableSize(len(values), numColumns); err != nil {
+		return nil, err
+	}
+	model.cells = make([][]*html.Node, len(values))
+	model.merges = make([][]merge, len(values))
+	for r, row := range values {
+		model.cells[r] = make([]*html.Node, numColumns)
+		model.merges[r] = make([]merge, numColumns)
+		for c := range model.cells[r] {
+			value := ""
+			if c < len(row) {
+				value = row[c]
+			}
+			model.cells[r][c] = newCell(value)
+			if c < len(aligns) && len(aligns[c]) > 0 {
+				h.AddAttribute(model.cells[r][c], "align", strings.ToLower(aligns[c]))
+			}
+		}
+	}
+	model.alignments = parseAlignments(&model)
+	return &model, nil
+}
+
+// countLeadingTextRows returns the number of rows at the top of a table of values in which no cell is a number, provided
+// that a row below them contains a number - as the headings of a table of statistics. It returns 0 if no row contains a
+// number.
+func countLeadingTextRows(values [][]string) int {
+	for r, row := range values {
+		for _, value := range row {
+			if isNumber(value) {
+				return r
+			}
+		}
+	}
+	return 0
+}
+
+// inferColumnAlignments sets the alignment of each column without one to Right if every value below the heading rows is
+// a number, or the shorthand for a missing number, and at least one is a number
+func inferColumnAlignments(aligns []string, values [][]string, headerRows int) []string {
+	for _, row := range values {
+		for len(aligns) < len(row) {
+			aligns = append(aligns, "")
+		}
+	}
+	for c := range aligns {
+		if len(aligns[c]) > 0 {
+			continue
+		}
+		numbers := 0
+		for r := headerRows; r < len(values); r++ {
+			if c >= len(values[r]) {
+				continue
+			}
+			value := plainValue(values[r][c])
+			if isNumber(value) {
+				numbers++
+			} else if len(value) > 0 && !shorthandPattern.MatchString(value) {
+				numbers = 0
+				break
+			}
+		}
+		if numbers > 0 {
+			aligns[c] = models.AlignRight
+		}
+	}
+	return aligns
+}
+
+// isNumber returns true if the value, without markup or references to footnotes, is a number
+func isNumber(value string) bool {
+	return numberPattern.MatchString(plainValue(value))
+}
+
+// plainValue returns the text of a value, without markup, references to footnotes or surrounding whitespace
+func plainValue(value string) string {
+	value = models.FootnoteReference.ReplaceAllLiteralString(h.InlineText(value), "")
+	return strings.TrimSpace(value)
+}
+
+// runsMarkup converts runs of text to the canonical inline markup, combining neighbouring runs with the same formatting
+func runsMarkup(runs []textRun) string {
+	var b strings.Builder
+	for i := 0; i < len(runs); i++ {
+		run := runs[i]
+		for i+1 < len(runs) && sameFormat(run, runs[i+1]) {
+			i++
+			run.text += runs[i].text
+		}
+		text := html.EscapeString(run.text)
+		if len(strings.TrimSpace(text)) > 0 {
+			text = wrap(text, "sub", run.subscript)
+			text = wrap(text, "sup", run.superscript)
+			text = wrap(text, "em", run.italic)
+			text = wrap(text, "strong", run.bold)
+			if len(run.link) > 0 {
+				text = `<a href="` + html.EscapeString(run.link) + `">` + text + "</a>"
+			}
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// sameFormat returns true if two runs have the same formatting
+func sameFormat(a textRun, b textRun) bool {
+	a.text, b.text = "", ""
+	return a == b
+}
+
+// wrap returns the markup inside an element with the given tag, if wanted
+func wrap(markup string, tag string, wanted bool) string {
+	if !wanted {
+		return markup
+	}
+	return "<" + tag + ">" + markup + "</" + tag + ">"
+}
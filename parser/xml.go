@@ -0,0 +1,236 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+)
+
+const (
+	// maxPartBytes is the largest uncompressed size of a part of a document that will be read
+	maxPartBytes = 64 << 20
+	// maxPartNodes is the largest number of elements and text nodes in a part of a document that will be read,
+	// whatever the limits of the request
+	maxPartNodes = 1 << 22
+	// partNodesAllowance is the number of nodes allowed in a part of a document besides those for its cells, for the
+	// styles and other content around a table
+	partNodesAllowance = 1 << 16
+	// nodesPerCell is the number of nodes allowed in a part of a document for each cell allowed by the limits
+	nodesPerCell = 16
+)
+
+var (
+	// errPartTooLarge is the cause of the error for a part of a document that exceeds maxPartBytes
+	errPartTooLarge = errors.New("part of the document is too large")
+	// errTooManyNodes is returned by parseXML when a document has more nodes than the maximum
+	errTooManyNodes = errors.New("the xml has too many nodes")
+	// textElements are the elements whose text is content, including any whitespace: paragraphs and headings, and the
+	// text of a run. Text that is only whitespace is dropped outside them.
+	textElements = map[string]bool{"p": true, "h": true, "t": true}
+)
+
+// xmlNode is an element of an xml document, or the text within an element, in a tree that keeps the order of the text
+// and elements
+type xmlNode struct {
+	name     string     // the local name of the element, or empty for text
+	attrs    []xml.Attr // the attributes of the element
+	text     string     // the text, for a text node
+	children []*xmlNode
+}
+
+// parseXML reads an xml document into a tree, returning its root element. Text that is only whitespace is left out
+// unless it is inside one of the textElements. errTooManyNodes is returned, as soon as it is known, if the tree would
+// have more than maxNodes elements and text nodes.
+func parseXML(r io.Reader, maxNodes int) (*xmlNode, error) {
+	decoder := xml.NewDecoder(r)
+	document := &xmlNode{}
+	stack := []*xmlNode{document}
+	nodes := 0
+	inText := 0 // the number of open textElements
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			if nodes++; nodes > maxNodes {
+				return nil, errTooManyNodes
+			}
+			node := &xmlNode{name: t.Name.Local, attrs: t.Attr}
+			parent.children = append(parent.children, node)
+			stack = append(stack, node)
+			if textElements[node.name] {
+				inText++
+			}
+		case xml.EndElement:
+			if textElements[parent.name] {
+				inText--
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if inText == 0 && len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+			if nodes++; nodes > maxNodes {
+				return nil, errTooManyNodes
+			}
+			parent.children = append(parent.children, &xmlNode{text: string(t)})
+		}
+	}
+	for _, node := range document.children {
+		if len(node.name) > 0 {
+			return node, nil
+		}
+	}
+	return nil, errors.New("the xml has no root element")
+}
+
+// attr returns the value of the attribute with the given local name, or an empty string
+func (n *xmlNode) attr(name string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// hasAttr returns true if the node has an attribute with the given local name
+func (n *xmlNode) hasAttr(name string) bool {
+	if n == nil {
+		return false
+	}
+	for _, a := range n.attrs {
+		if a.Name.Local == name {
+			return true
+		}
+	}
+	return false
+}
+
+// child returns the first child element with the given local name, or nil. A nil node has no children.
+func (n *xmlNode) child(name string) *xmlNode {
+	if n == nil || len(name) == 0 {
+		return nil
+	}
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// path returns the element found by following the child elements with the given local names, or nil
+func (n *xmlNode) path(names ...string) *xmlNode {
+	for _, name := range names {
+		n = n.child(name)
+	}
+	return n
+}
+
+// childrenNamed returns the child elements with the given local name
+func (n *xmlNode) childrenNamed(name string) []*xmlNode {
+	if n == nil {
+		return nil
+	}
+	var children []*xmlNode
+	for _, c := range n.children {
+		if c.name == name {
+			children = append(children, c)
+		}
+	}
+	return children
+}
+
+// textContent returns all the text within the node
+func (n *xmlNode) textContent() string {
+	if n == nil {
+		return ""
+	}
+	if len(n.name) == 0 {
+		return n.text
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		b.WriteString(c.textContent())
+	}
+	return b.String()
+}
+
+// zipDocument is a document stored as a zip archive of parts, such as a .docx or .ods file
+type zipDocument struct {
+	reader   *zip.Reader
+	field    string // the field of the request containing the document, for error messages
+	maxNodes int    // the largest number of elements and text nodes in a part
+}
+
+// openZipDocument opens a document stored as a zip archive, returning an error naming the field of the request that
+// contained it if it isn't a zip archive. The number of nodes read from each part is limited by the cells allowed by
+// the limits, and by maxPartNodes.
+func openZipDocument(content []byte, field string, limits models.Limits) (*zipDocument, error) {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, invalidDocument(field, err)
+	}
+	maxNodes := maxPartNodes
+	if limits.Cells > 0 && limits.Cells < (maxPartNodes-partNodesAllowance)/nodesPerCell {
+		maxNodes = partNodesAllowance + limits.Cells*nodesPerCell
+	}
+	return &zipDocument{reader: reader, field: field, maxNodes: maxNodes}, nil
+}
+
+// part reads the xml part of the document with the given name. It returns nil, without an error, if the document has
+// no such part.
+func (d *zipDocument) part(name string) (*xmlNode, error) {
+	for _, file := range d.reader.File {
+		if file.Name != name {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, invalidDocument(d.field, err)
+		}
+		defer rc.Close()
+		content, err := io.ReadAll(io.LimitReader(rc, maxPartBytes+1))
+		if err != nil {
+			return nil, invalidDocument(d.field, err)
+		}
+		if len(content) > maxPartBytes {
+			return nil, invalidDocument(d.field, errPartTooLarge)
+		}
+		node, err := parseXML(bytes.NewReader(content), d.maxNodes)
+		if errors.Is(err, errTooManyNodes) {
+			return nil, &models.Error{
+				Code:    models.CodeTableTooLarge,
+				Message: fmt.Sprintf("%s of %s has more than the limit of %d elements", name, d.field, d.maxNodes),
+				Details: map[string]interface{}{"part": name, "max": d.maxNodes},
+				Err:     err,
+			}
+		}
+		if err != nil {
+			return nil, invalidDocument(d.field, fmt.Errorf("%s: %w", name, err))
+		}
+		return node, nil
+	}
+	return nil, nil
+}
+
+// invalidDocument returns the error for a document that can't be read
+func invalidDocument(field string, err error) error {
+	return models.NewError(models.CodeInvalidDocument, field+" could not be read", err)
+}
@@ -0,0 +1,58 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+)
+
+// the number of column headings named in the alt text of a table, before the rest are counted
+const altTextHeadings = 5
+
+// AltText returns a short summary of the table - its title, size, column headings and source - for use as the text
+// alternative of an image of it
+func AltText(ctx context.Context, request *models.RenderRequest) string {
+	table := createModel(ctx, request)
+
+	var parts []string
+	if title := plainText(request.Title); len(title) > 0 {
+		parts = append(parts, "Table: "+strings.TrimSuffix(title, "."))
+	} else {
+		parts = append(parts, "Table")
+	}
+	if subtitle := plainText(request.Subtitle); len(subtitle) > 0 {
+		parts = append(parts, strings.TrimSuffix(subtitle, "."))
+	}
+	rows := len(request.Data) - table.headRows
+	parts = append(parts, fmt.Sprintf("%s and %s", plural(rows, "row"), plural(len(table.columns), "column")))
+
+	var headings []string
+	for _, label := range table.findColumnLabels() {
+		if n := len(headings); len(label) > 0 && (n == 0 || headings[n-1] != label) {
+			headings = append(headings, label)
+		}
+	}
+	if len(headings) > altTextHeadings {
+		headings = append(headings[:altTextHeadings], fmt.Sprintf("and %d more", len(headings)-altTextHeadings))
+	}
+	if len(headings) > 0 {
+		parts = append(parts, "Columns: "+strings.Join(headings, ", "))
+	}
+	if units := plainText(request.Units); len(units) > 0 {
+		parts = append(parts, "Units: "+strings.TrimSuffix(units, "."))
+	}
+	if source := plainText(request.Source); len(source) > 0 {
+		parts = append(parts, sourceText+strings.TrimSuffix(source, "."))
+	}
+	return strings.Join(parts, ". ") + "."
+}
+
+// plural returns the number with the singular or plural of the noun, e.g. "1 row" or "2 rows"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,100 @@
+package renderer_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+)
+
+// the number of rows in the tables rendered by the benchmarks
+var benchmarkRowCounts = []int{1000, 10000, 100000, 200000}
+
+// BenchmarkWriteCSV reports the peak heap used while writing, in addition to that used by the request itself.
+// This should stay flat as the number of rows grows.
+func BenchmarkWriteCSV(b *testing.B) {
+	benchmarkWrite(b, renderer.WriteCSV)
+}
+
+// BenchmarkWriteXLSX reports the peak heap used while writing, in addition to that used by the request itself.
+// This should stay flat as the number of rows grows.
+func BenchmarkWriteXLSX(b *testing.B) {
+	benchmarkWrite(b, renderer.WriteXLSX)
+}
+
+func benchmarkWrite(b *testing.B, write func(context.Context, io.Writer, *models.RenderRequest) error) {
+	for _, rows := range benchmarkRowCounts {
+		b.Run(fmt.Sprintf("rows=%d", rows), func(b *testing.B) {
+			request := createBenchmarkRequest(rows)
+			b.ReportAllocs()
+			b.ResetTimer()
+			peak := uint64(0)
+			for i := 0; i < b.N; i++ {
+				w := newHeapSamplingWriter()
+				if err := write(mockContext, w, request); err != nil {
+					b.Fatal(err)
+				}
+				if w.peak > peak {
+					peak = w.peak
+				}
+			}
+			b.ReportMetric(float64(peak)/(1024*1024), "peak-heap-MB")
+		})
+	}
+}
+
+// createBenchmarkRequest creates a request for a table with the given number of rows of numbers, a heading row and column
+func createBenchmarkRequest(rows int) *models.RenderRequest {
+	data := make([][]string, rows)
+	data[0] = []string{"Date", "Index", "Rate", "Index", "Rate", "Index", "Rate", "Index", "Rate", "Notes"}
+	for r := 1; r < rows; r++ {
+		data[r] = []string{strconv.Itoa(1900 + r%100), strconv.Itoa(r), "1.5", "101.2", "0.25", strconv.Itoa(r * 2), "3.1", "99.9", "0.001", "some text"}
+	}
+	return &models.RenderRequest{
+		Filename:      "benchmark",
+		Title:         "Benchmark table",
+		RowFormats:    []models.RowFormat{{Row: 0, Heading: true}},
+		ColumnFormats: []models.ColumnFormat{{Column: 0, Heading: true}, {Column: 9, Align: models.AlignRight}},
+		CellFormats:   []models.CellFormat{{Row: 0, Column: 1, Colspan: 2}},
+		Data:          data,
+		Footnotes:     []string{"A footnote"},
+	}
+}
+
+// heapSamplingWriter discards everything written to it, sampling the live heap as it goes to find the peak in use
+// above that in use when it was created. Garbage is collected before each sample so that only live memory is counted.
+type heapSamplingWriter struct {
+	baseline    uint64
+	peak        uint64
+	sinceSample int
+}
+
+// the number of bytes written between samples of the heap
+const heapSampleInterval = 1024 * 1024
+
+func newHeapSamplingWriter() *heapSamplingWriter {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return &heapSamplingWriter{baseline: stats.HeapAlloc}
+}
+
+func (w *heapSamplingWriter) Write(p []byte) (int, error) {
+	w.sinceSample += len(p)
+	if w.sinceSample >= heapSampleInterval {
+		w.sinceSample = 0
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapAlloc > w.baseline && stats.HeapAlloc-w.baseline > w.peak {
+			w.peak = stats.HeapAlloc - w.baseline
+		}
+	}
+	return ioutil.Discard.Write(p)
+}
@@ -0,0 +1,232 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
+)
+
+// the size of a chart if the request doesn't specify one, and the limits of the size that can be requested
+const (
+	defaultChartWidth  = 640
+	defaultChartHeight = 400
+	minChartSize       = 200
+	maxChartSize       = 4000
+)
+
+// the chart types, and whether each draws its values as bars
+var chartTypes = map[string]bool{
+	models.ChartLine:       false,
+	models.ChartBar:        true,
+	models.ChartStackedBar: true,
+}
+
+// chartModel holds the data of a chart, taken from the table. All the text is plain text, without markup.
+type chartModel struct {
+	kind          string // line, bar or stacked-bar
+	width         int
+	height        int
+	title         string
+	subtitle      string
+	units         string // the label of the value axis
+	source        string
+	categoryLabel string // the label of the category axis
+	categories    []string
+	series        []chartSeries
+}
+
+// chartSeries is a named set of values, one for each category of the chart
+type chartSeries struct {
+	name   string
+	values []float64 // NaN where the table has no number, e.g. a shorthand marker such as [x]
+}
+
+// invalidChart returns an error reporting why the table can't be drawn as a chart
+func invalidChart(message string, details map[string]interface{}) error {
+	return &models.Error{Code: models.CodeInvalidChart, Message: message, Details: details}
+}
+
+// newChartModel extracts the categories and series of the chart from the table, as chosen by the chart spec of the
+// request or, where the spec doesn't say, the headings of the table
+func newChartModel(ctx context.Context, request *models.RenderRequest) (*chartModel, error) {
+	ctx, span := tracing.StartSpan(ctx, "createChartModel")
+	defer span.End()
+
+	spec := models.ChartSpec{}
+	if request.Chart != nil {
+		spec = *request.Chart
+	}
+	chart := &chartModel{
+		kind:     spec.Type,
+		width:    spec.Width,
+		height:   spec.Height,
+		title:    plainText(request.Title),
+		subtitle: plainText(request.Subtitle),
+		units:    plainText(request.Units),
+		source:   plainText(request.Source),
+	}
+	if len(chart.kind) == 0 {
+		chart.kind = models.ChartBar
+	}
+	if _, ok := chartTypes[chart.kind]; !ok {
+		return nil, invalidChart("Unknown chart type", map[string]interface{}{"type": chart.kind})
+	}
+	if chart.width == 0 {
+		chart.width = defaultChartWidth
+	}
+	if chart.height == 0 {
+		chart.height = defaultChartHeight
+	}
+	if chart.width < minChartSize || chart.width > maxChartSize || chart.height < minChartSize || chart.height > maxChartSize {
+		return nil, invalidChart(fmt.Sprintf("The width and height of a chart must be between %d and %d", minChartSize, maxChartSize),
+			map[string]interface{}{"width": chart.width, "height": chart.height})
+	}
+
+	table := createModel(ctx, request)
+	numeric := table.findNumericColumns()
+	labels := table.findColumnLabels()
+
+	rows := spec.Rows
+	if rows == nil {
+		for i := table.headRows; i < len(request.Data); i++ {
+			if !table.rowFormat(i).Total {
+				rows = append(rows, i)
+			}
+		}
+	}
+	for _, row := range rows {
+		if row < 0 || row >= len(request.Data) {
+			return nil, invalidChart("The chart includes a row that isn't in the table", map[string]interface{}{"row": row})
+		}
+	}
+	if len(rows) == 0 {
+		return nil, invalidChart("The table has no rows to chart", nil)
+	}
+
+	var categoryColumns []int
+	switch {
+	case spec.CategoryColumn != nil:
+		categoryColumns = []int{*spec.CategoryColumn}
+	default:
+		for i, column := range table.columns {
+			if column.Heading {
+				categoryColumns = append(categoryColumns, i)
+			}
+		}
+		if categoryColumns == nil && len(numeric) > 1 && !numeric[0] {
+			categoryColumns = []int{0}
+		}
+	}
+	seriesColumns := spec.SeriesColumns
+	if seriesColumns == nil {
+		for i := range table.columns {
+			if numeric[i] && !table.columns[i].Heading && !containsInt(categoryColumns, i) {
+				seriesColumns = append(seriesColumns, i)
+			}
+		}
+	}
+	for _, column := range append(categoryColumns, seriesColumns...) {
+		if column < 0 || column >= len(table.columns) {
+			return nil, invalidChart("The chart includes a column that isn't in the table", map[string]interface{}{"column": column})
+		}
+	}
+	if len(seriesColumns) == 0 {
+		return nil, invalidChart("The table has no columns of numbers to chart", nil)
+	}
+
+	var categoryLabels []string
+	for _, column := range categoryColumns {
+		if n := len(categoryLabels); len(labels[column]) > 0 && (n == 0 || categoryLabels[n-1] != labels[column]) {
+			categoryLabels = append(categoryLabels, labels[column])
+		}
+	}
+	chart.categoryLabel = strings.Join(categoryLabels, " ")
+	for _, row := range rows {
+		var text []string
+		for _, column := range categoryColumns {
+			if value := plainText(table.cellValue(row, column)); len(value) > 0 {
+				text = append(text, value)
+			}
+		}
+		chart.categories = append(chart.categories, strings.Join(text, " "))
+	}
+	for _, column := range seriesColumns {
+		series := chartSeries{name: labels[column], values: make([]float64, len(rows))}
+		if len(series.name) == 0 {
+			series.name = fmt.Sprintf("Column %d", column+1)
+		}
+		for i, row := range rows {
+			series.values[i] = math.NaN()
+			if value, ok := numericValue(plainText(table.cellValue(row, column))); ok {
+				series.values[i] = value
+			}
+		}
+		chart.series = append(chart.series, series)
+	}
+	return chart, nil
+}
+
+// cellValue returns the value of the cell at row, col. The value of a cell hidden by a merged cell is the value of the
+// merged cell.
+func (m *tableModel) cellValue(row int, col int) string {
+	if cell := m.cells[row][col]; cell != nil && cell.skip {
+		for _, format := range m.request.CellFormats {
+			if format.Row <= row && row < format.Row+max(format.Rowspan, 1) &&
+				format.Column <= col && col < format.Column+max(format.Colspan, 1) &&
+				(format.Row != row || format.Column != col) {
+				row, col = format.Row, format.Column
+				break
+			}
+		}
+	}
+	if row >= len(m.request.Data) || col >= len(m.request.Data[row]) {
+		return ""
+	}
+	return m.request.Data[row][col]
+}
+
+// containsInt returns true if the value is in the list
+func containsInt(list []int, value int) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// valueRange returns the smallest and largest values drawn on the chart, which for a stacked bar chart are the
+// totals of the negative and positive values of each category. Bar charts always include zero.
+func (c *chartModel) valueRange() (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for i := range c.categories {
+		var negative, positive float64
+		for _, series := range c.series {
+			v := series.values[i]
+			switch {
+			case math.IsNaN(v):
+				continue
+			case c.kind == models.ChartStackedBar && v < 0:
+				negative += v
+			case c.kind == models.ChartStackedBar:
+				positive += v
+			default:
+				low, high = math.Min(low, v), math.Max(high, v)
+			}
+		}
+		if c.kind == models.ChartStackedBar {
+			low, high = math.Min(low, negative), math.Max(high, positive)
+		}
+	}
+	if math.IsInf(low, 1) {
+		return 0, 1
+	}
+	if chartTypes[c.kind] {
+		low, high = math.Min(low, 0), math.Max(high, 0)
+	}
+	return low, high
+}
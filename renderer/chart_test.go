@@ -0,0 +1,98 @@
+package renderer_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func chartRequest(chart *models.ChartSpec) *models.RenderRequest {
+	return &models.RenderRequest{
+		Filename:      "myId",
+		Title:         "Sales <b>by</b> region [1]",
+		Chart:         chart,
+		RowFormats:    []models.RowFormat{{Row: 0, Heading: true}},
+		ColumnFormats: []models.ColumnFormat{{Column: 0, Heading: true}, {Column: 1, Heading: true}},
+		CellFormats:   []models.CellFormat{{Row: 1, Column: 0, Rowspan: 2}},
+		Data: [][]string{
+			{"Region", "Product", "Sales", "Returns"},
+			{"North", "Tea", "1,200", "10"},
+			{"", "Coffee", "900", "[x]"},
+		},
+	}
+}
+
+func TestWriteSVGChart(t *testing.T) {
+
+	Convey("A table is drawn as a bar chart of its numeric columns by default", t, func() {
+		output, err := invokeWriteSVGChart(chartRequest(nil))
+		So(err, ShouldBeNil)
+		So(output, ShouldStartWith, `<svg xmlns="http://www.w3.org/2000/svg" width="640" height="400"`)
+		So(output, ShouldContainSubstring, `role="img" aria-labelledby="chart-myId-title chart-myId-desc"`)
+		So(output, ShouldContainSubstring, `<title id="chart-myId-title">Sales by region</title>`)
+		So(output, ShouldContainSubstring, `<g class="chart__bars">`)
+		So(output, ShouldContainSubstring, "<title>Sales, North Tea: 1200</title>")
+
+		Convey("And a merged cell labels every category it spans", func() {
+			So(output, ShouldContainSubstring, "<title>Sales, North Coffee: 900</title>")
+		})
+
+		Convey("And a missing value is left out", func() {
+			So(output, ShouldContainSubstring, "<title>Returns, North Tea: 10</title>")
+			So(output, ShouldNotContainSubstring, "<title>Returns, North Coffee")
+		})
+	})
+
+	Convey("The chart spec chooses the columns, rows, type and size of the chart", t, func() {
+		category := 1
+		output, err := invokeWriteSVGChart(chartRequest(&models.ChartSpec{
+			Type: models.ChartLine, CategoryColumn: &category, SeriesColumns: []int{2}, Rows: []int{2}, Width: 300, Height: 200,
+		}))
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, `width="300" height="200"`)
+		So(output, ShouldContainSubstring, `<g class="chart__lines">`)
+		So(output, ShouldContainSubstring, "<title>Sales, Coffee: 900</title>")
+		So(output, ShouldNotContainSubstring, "Tea")
+		So(output, ShouldNotContainSubstring, "Returns")
+	})
+
+	Convey("Rows of totals are left out of a chart by default", t, func() {
+		request := chartRequest(nil)
+		request.RowFormats = append(request.RowFormats, models.RowFormat{Row: 3, Total: true})
+		request.Data = append(request.Data, []string{"Total", "", "2,100", "10"})
+		output, err := invokeWriteSVGChart(request)
+		So(err, ShouldBeNil)
+		So(output, ShouldContainSubstring, "<title>Sales, North Tea: 1200</title>")
+		So(output, ShouldNotContainSubstring, "2100")
+	})
+
+	Convey("A table that can't be drawn as a chart is an invalid chart error", t, func() {
+		for _, spec := range []*models.ChartSpec{
+			{Type: "pie"},
+			{Width: 100},
+			{Height: 5000},
+			{Rows: []int{3}},
+			{SeriesColumns: []int{4}},
+		} {
+			_, err := invokeWriteSVGChart(chartRequest(spec))
+			var modelErr *models.Error
+			So(errors.As(err, &modelErr), ShouldBeTrue)
+			So(modelErr.Code, ShouldEqual, models.CodeInvalidChart)
+		}
+
+		request := &models.RenderRequest{Filename: "myId", Data: [][]string{{"a", "b"}, {"c", "d"}}}
+		_, err := invokeWriteSVGChart(request)
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldContainSubstring, "no columns of numbers")
+	})
+}
+
+func invokeWriteSVGChart(request *models.RenderRequest) (string, error) {
+	var buf bytes.Buffer
+	err := renderer.WriteSVGChart(mockContext, &buf, request)
+	return buf.String(), err
+}
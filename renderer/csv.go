@@ -5,45 +5,63 @@ import (
 	"context"
 	"encoding/csv"
 	"fmt"
+	"io"
 
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
 	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
 // RenderCSV returns a csv representation of the table generated from the given request
 func RenderCSV(ctx context.Context, request *models.RenderRequest) ([]byte, error) {
 	var buf bytes.Buffer
-	writer := csv.NewWriter(&buf)
+	if err := WriteCSV(ctx, &buf, request); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteCSV writes a csv representation of the table generated from the given request to w, one row at a time
+func WriteCSV(ctx context.Context, w io.Writer, request *models.RenderRequest) (err error) {
+	writer := csv.NewWriter(w)
 
 	model := createModel(ctx, request)
 
-	err := writeTitles(ctx, writer, request)
+	ctx, span := tracing.StartSpan(ctx, "write", tracing.Format.String("csv"))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	err = writeTitles(ctx, writer, request)
 	if err != nil {
-		return nil, err
+		return renderError("csv", err)
 	}
 
 	err = writeData(ctx, writer, model, request)
 	if err != nil {
-		return nil, err
+		return renderError("csv", err)
 	}
 
 	err = writeUnits(ctx, writer, request)
 	if err != nil {
-		return nil, err
+		return renderError("csv", err)
 	}
 
 	err = writeSource(ctx, writer, request)
 	if err != nil {
-		return nil, err
+		return renderError("csv", err)
 	}
 
 	err = writeFootnotes(ctx, writer, request)
 	if err != nil {
-		return nil, err
+		return renderError("csv", err)
 	}
 
 	writer.Flush()
-	return buf.Bytes(), nil
+	if err = writer.Error(); err != nil {
+		log.Error(ctx, "unable to flush csv", err)
+		return renderError("csv", err)
+	}
+	return nil
 }
 
 // writeTitles writes the title and subtitle to the csv
@@ -63,11 +81,12 @@ func writeTitles(ctx context.Context, writer *csv.Writer, request *models.Render
 
 // writeData writes each row of the table to the csv writer, replacing cells hidden by a merge with an empty string
 func writeData(ctx context.Context, writer *csv.Writer, model *tableModel, request *models.RenderRequest) error {
+	var out []string
 	for r, row := range request.Data {
-		out := []string{}
+		out = out[:0]
 		for c, value := range row {
 			if cellIsVisible(model, r, c) {
-				out = append(out, value)
+				out = append(out, h.PlainText(value))
 			} else {
 				out = append(out, "")
 			}
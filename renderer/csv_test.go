@@ -106,6 +106,26 @@ func TestRenderCSV(t *testing.T) {
 		}
 	})
 
+	Convey("Markup in cells should not be present in the csv", t, func() {
+		request := models.RenderRequest{Filename: "filename", Title: "This is the Heading", Subtitle: "This is a Subtitle",
+			Data: [][]string{{"<strong>R&amp;D</strong>[1]", "line 1\nline 2"}}}
+
+		rows := invokeRenderCSV(&request)
+
+		rowOffset := getCSVDataRowOffset(&request)
+		So(rows[rowOffset], ShouldResemble, []string{"R&D[1]", "line 1\nline 2"})
+	})
+
+	Convey("Values containing < or > that isn't markup should be kept in the csv", t, func() {
+		request := models.RenderRequest{Filename: "filename", Title: "This is the Heading", Subtitle: "This is a Subtitle",
+			Data: [][]string{{"<LOD", "a<b and c>d", "<em>&lt;5</em>"}}}
+
+		rows := invokeRenderCSV(&request)
+
+		rowOffset := getCSVDataRowOffset(&request)
+		So(rows[rowOffset], ShouldResemble, []string{"<LOD", "a<b and c>d", "<5"})
+	})
+
 	Convey("Cells hidden by a merge should not be present in the csv", t, func() {
 		data := [][]string{
 			{"Cell 1A", "hidden", "Cell 1C", "Cell 1D"},
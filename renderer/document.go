@@ -0,0 +1,179 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
+	"github.com/ONSdigital/log.go/v2/log"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// DefaultLanguage is the lang of an html document when the request doesn't specify one
+const DefaultLanguage = "en"
+
+var cssComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// cssRule is a rule of a stylesheet that can be applied to the style attribute of matching elements
+type cssRule struct {
+	selectors    []string // element names, or class names with a leading '.'
+	declarations string
+}
+
+// WriteHTMLDocument writes the table to w as a complete html page, with a stylesheet that reproduces the formatting of
+// the table without any other css, and prints the heading rows on every page. If the request asks for inline styles,
+// the rules of the stylesheet are also copied to the style attribute of each element they apply to.
+func WriteHTMLDocument(ctx context.Context, w io.Writer, request *models.RenderRequest) error {
+	if !request.InlineStyles {
+		return writeHTML(ctx, w, request, "html-document", true)
+	}
+
+	var buf bytes.Buffer
+	if err := writeHTML(ctx, &buf, request, "html-document", true); err != nil {
+		return err
+	}
+	_, span := tracing.StartSpan(ctx, "inlineStyles")
+	err := inlineStyles(&buf, w)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		log.Error(ctx, "unable to inline styles", err, log.Data{"file_name": request.Filename})
+		return renderError("html-document", err)
+	}
+	return nil
+}
+
+// documentLanguage returns the lang of the html document
+func documentLanguage(request *models.RenderRequest) string {
+	if len(request.Language) > 0 {
+		return request.Language
+	}
+	return DefaultLanguage
+}
+
+// inlineStyles reads an html document, copies the rules of its stylesheets to the style attributes of the elements they
+// apply to, and writes the document to w. Only rules with simple selectors (an element or class name) are copied:
+// they are applied in the order of the stylesheet, followed by any existing style of the element, so the stylesheet
+// should list element rules before class rules. Other rules, such as print styles, are left in the stylesheet.
+func inlineStyles(r io.Reader, w io.Writer) error {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return err
+	}
+	var rules []cssRule
+	for _, style := range findElements(doc, atom.Style) {
+		if style.FirstChild != nil {
+			rules = append(rules, parseStylesheet(style.FirstChild.Data)...)
+		}
+	}
+	for _, body := range findElements(doc, atom.Body) {
+		applyRules(body, rules)
+	}
+	return html.Render(w, doc)
+}
+
+// findElements returns every element of the given type in the tree
+func findElements(n *html.Node, a atom.Atom) []*html.Node {
+	var found []*html.Node
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		found = append(found, n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		found = append(found, findElements(c, a)...)
+	}
+	return found
+}
+
+// parseStylesheet returns the rules of the stylesheet whose selectors are all element or class names.
+// At-rules such as @media are skipped.
+func parseStylesheet(css string) []cssRule {
+	css = cssComment.ReplaceAllString(css, "")
+	var rules []cssRule
+	depth, start, body := 0, 0, 0
+	for i := 0; i < len(css); i++ {
+		switch css[i] {
+		case '{':
+			if depth == 0 {
+				body = i + 1
+			}
+			depth++
+		case '}':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if rule, ok := newCSSRule(css[start:body-1], css[body:i]); ok {
+				rules = append(rules, rule)
+			}
+			start = i + 1
+		}
+	}
+	return rules
+}
+
+// newCSSRule creates a rule from the text of its selectors and declarations, if all the selectors are simple
+func newCSSRule(selectors string, declarations string) (cssRule, bool) {
+	rule := cssRule{declarations: strings.TrimSuffix(strings.Join(strings.Fields(declarations), " "), ";")}
+	for _, selector := range strings.Split(selectors, ",") {
+		selector = strings.ReplaceAll(strings.TrimSpace(selector), `\`, "")
+		name := strings.TrimPrefix(selector, ".")
+		if len(name) == 0 || strings.ContainsAny(name, " >+~:[.*#@") {
+			return cssRule{}, false
+		}
+		rule.selectors = append(rule.selectors, selector)
+	}
+	return rule, len(rule.declarations) > 0
+}
+
+// matches returns true if any of the rule's selectors apply to the element
+func (rule cssRule) matches(n *html.Node, classes []string) bool {
+	for _, selector := range rule.selectors {
+		if name, isClass := strings.CutPrefix(selector, "."); isClass {
+			for _, class := range classes {
+				if class == name {
+					return true
+				}
+			}
+		} else if selector == n.Data {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRules adds the declarations of the matching rules to the style attribute of the node and its descendants
+func applyRules(n *html.Node, rules []cssRule) {
+	if n.Type == html.ElementNode {
+		var classes []string
+		styleIdx := -1
+		for i, attr := range n.Attr {
+			switch attr.Key {
+			case "class":
+				classes = strings.Fields(attr.Val)
+			case "style":
+				styleIdx = i
+			}
+		}
+		var declarations []string
+		for _, rule := range rules {
+			if rule.matches(n, classes) {
+				declarations = append(declarations, rule.declarations)
+			}
+		}
+		if len(declarations) > 0 {
+			if styleIdx >= 0 {
+				declarations = append(declarations, strings.TrimSuffix(strings.TrimSpace(n.Attr[styleIdx].Val), ";"))
+				n.Attr[styleIdx].Val = strings.Join(declarations, "; ")
+			} else {
+				n.Attr = append(n.Attr, html.Attribute{Key: "style", Val: strings.Join(declarations, "; ")})
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		applyRules(c, rules)
+	}
+}
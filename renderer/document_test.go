@@ -0,0 +1,122 @@
+package renderer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+	"github.com/ONSdigital/dp-table-renderer/testdata"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+func TestWriteHTMLDocument(t *testing.T) {
+
+	Convey("A table is rendered as a complete html page", t, func() {
+		request, err := models.CreateRenderRequest(mockContext, bytes.NewReader(testdata.LoadExampleRequest(t)))
+		So(err, ShouldBeNil)
+
+		doc, output := invokeWriteHTMLDocument(request)
+		So(output, ShouldStartWith, "<!DOCTYPE html>\n")
+
+		root := FindNode(doc, atom.Html)
+		So(GetAttribute(root, "lang"), ShouldEqual, renderer.DefaultLanguage)
+		So(FindNodeWithAttributes(doc, atom.Meta, map[string]string{"charset": "utf-8"}), ShouldNotBeNil)
+		So(FindNode(doc, atom.Title).FirstChild.Data, ShouldEqual, "This is an example table")
+
+		style := FindNode(doc, atom.Style).FirstChild.Data
+		So(style, ShouldContainSubstring, ".align-right { text-align: right; }")
+		So(style, ShouldContainSubstring, ".table__nowrap { white-space: nowrap; }")
+		So(style, ShouldContainSubstring, ".visuallyhidden {")
+		So(style, ShouldContainSubstring, "@media print")
+		So(style, ShouldContainSubstring, "thead { display: table-header-group; }")
+
+		Convey("With the heading rows in a thead, so that they are repeated when printed", func() {
+			table := FindNode(doc, atom.Table)
+			thead := FindNode(table, atom.Thead)
+			So(thead, ShouldNotBeNil)
+			So(len(FindNodes(thead, atom.Tr)), ShouldEqual, 1)
+			So(len(FindNodes(FindNode(table, atom.Tbody), atom.Tr)), ShouldEqual, len(request.Data)-1)
+		})
+
+		Convey("With the widths and heights of the table as styles", func() {
+			cols := FindNodes(doc, atom.Col)
+			So(GetAttribute(cols[0], "style"), ShouldEqual, "width: 5em")
+			So(output, ShouldContainSubstring, `style="height: 5em"`)
+		})
+	})
+
+	Convey("The html fragment doesn't have a thead", t, func() {
+		request, err := models.CreateRenderRequest(mockContext, bytes.NewReader(testdata.LoadExampleRequest(t)))
+		So(err, ShouldBeNil)
+		response, err := renderer.RenderHTML(mockContext, request)
+		So(err, ShouldBeNil)
+		So(string(response), ShouldNotContainSubstring, "<thead")
+	})
+
+	Convey("The language of the request is the lang of the document, and the filename its title if there is no title", t, func() {
+		request := &models.RenderRequest{Filename: "myId", Language: "cy", Data: [][]string{{"a"}}}
+
+		doc, _ := invokeWriteHTMLDocument(request)
+		So(GetAttribute(FindNode(doc, atom.Html), "lang"), ShouldEqual, "cy")
+		So(FindNode(doc, atom.Title).FirstChild.Data, ShouldEqual, "myId")
+	})
+
+	Convey("The styles can be inlined for email clients", t, func() {
+		request := &models.RenderRequest{
+			Filename:      "myId",
+			Title:         "Title",
+			InlineStyles:  true,
+			RowFormats:    []models.RowFormat{{Row: 0, Heading: true}},
+			ColumnFormats: []models.ColumnFormat{{Column: 1, Align: models.AlignRight, Width: "4em"}},
+			Data:          [][]string{{"Name", "Value [1]"}, {"a", "1"}},
+			Footnotes:     []string{"A note"},
+		}
+
+		doc, _ := invokeWriteHTMLDocument(request)
+		So(GetAttribute(FindNode(doc, atom.Table), "style"), ShouldContainSubstring, "border-collapse: collapse")
+		So(GetAttribute(FindNode(doc, atom.Caption), "style"), ShouldContainSubstring, "font-weight: bold")
+
+		cells := FindNodes(doc, atom.Td)
+		So(GetAttribute(cells[0], "style"), ShouldEndWith, "text-align: left")
+		So(GetAttribute(cells[1], "style"), ShouldEndWith, "text-align: left; text-align: right")
+
+		hidden := FindNodeWithAttributes(doc, atom.Span, map[string]string{"class": "visuallyhidden"})
+		So(GetAttribute(hidden, "style"), ShouldContainSubstring, "position: absolute")
+
+		cols := FindNodes(doc, atom.Col)
+		So(GetAttribute(cols[1], "style"), ShouldEqual, "width: 4em")
+
+		Convey("And the print styles are kept in the stylesheet", func() {
+			So(FindNode(doc, atom.Style).FirstChild.Data, ShouldContainSubstring, "@media print")
+		})
+	})
+
+	Convey("Each theme defines the styles of its own classes", t, func() {
+		request := &models.RenderRequest{Filename: "myId", Theme: "govuk", InlineStyles: true, Data: [][]string{{"a", "1"}}}
+
+		doc, _ := invokeWriteHTMLDocument(request)
+		cells := FindNodes(doc, atom.Td)
+		So(GetAttribute(cells[1], "class"), ShouldEqual, "govuk-table__cell govuk-table__cell--numeric")
+		So(GetAttribute(cells[1], "style"), ShouldEndWith, "text-align: right")
+	})
+
+	Convey("An unknown theme is an error", t, func() {
+		request := &models.RenderRequest{Filename: "myId", Theme: "unknown", Data: [][]string{{"a"}}}
+		var buf bytes.Buffer
+		So(renderer.WriteHTMLDocument(mockContext, &buf, request), ShouldNotBeNil)
+	})
+}
+
+func invokeWriteHTMLDocument(request *models.RenderRequest) (*html.Node, string) {
+	var buf bytes.Buffer
+	err := renderer.WriteHTMLDocument(mockContext, &buf, request)
+	So(err, ShouldBeNil)
+	doc, err := html.Parse(strings.NewReader(buf.String()))
+	So(err, ShouldBeNil)
+	return doc, buf.String()
+}
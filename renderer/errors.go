@@ -0,0 +1,13 @@
+package renderer
+
+import (
+	"github.com/ONSdigital/dp-table-renderer/models"
+)
+
+// ErrRenderFailed can be used with errors.Is to identify an error that occurred while rendering a table
+var ErrRenderFailed = &models.Error{Code: models.CodeRenderFailed, Message: "Failed to render the table"}
+
+// renderError wraps an error that occurred while rendering the table in the given format
+func renderError(format string, err error) error {
+	return models.NewError(models.CodeRenderFailed, "Failed to render the table as "+format, err)
+}
@@ -0,0 +1,46 @@
+package renderer
+
+import (
+	"context"
+	"io"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+)
+
+// Format describes an output format that a table can be rendered in
+type Format struct {
+	Name        string // the name used to request the format, e.g. the render_type of /render/{render_type}
+	ContentType string
+	Extension   string // the file extension, including the leading '.'
+	Write       func(context.Context, io.Writer, *models.RenderRequest) error
+	AltText     func(context.Context, *models.RenderRequest) string // the text alternative of an image format, or nil
+}
+
+// Formats lists the supported output formats
+var Formats = []Format{
+	{Name: "html", ContentType: "text/html", Extension: ".html", Write: WriteHTML},
+	{Name: "html-document", ContentType: "text/html", Extension: ".document.html", Write: WriteHTMLDocument},
+	{Name: "xlsx", ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Extension: ".xlsx", Write: WriteXLSX},
+	{Name: "csv", ContentType: "text/csv", Extension: ".csv", Write: WriteCSV},
+	{Name: "svg-chart", ContentType: "image/svg+xml", Extension: ".svg", Write: WriteSVGChart},
+	{Name: "png", ContentType: "image/png", Extension: ".png", Write: WritePNG, AltText: AltText},
+}
+
+// FindFormat returns the Format with the given name, and whether it exists
+func FindFormat(name string) (Format, bool) {
+	for _, format := range Formats {
+		if format.Name == name {
+			return format, true
+		}
+	}
+	return Format{}, false
+}
+
+// FormatNames returns the names of all the supported formats
+func FormatNames() []string {
+	names := make([]string, len(Formats))
+	for i, format := range Formats {
+		names[i] = format.Name
+	}
+	return names
+}
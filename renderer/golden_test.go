@@ -0,0 +1,114 @@
+package renderer_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// run the tests with -update to rewrite the golden files after a deliberate change to the output
+var update = flag.Bool("update", false, "update the golden files")
+
+// goldenDir contains a directory of requests (*.json) and the output expected from them for each golden test
+const goldenDir = "../testdata/golden"
+
+func TestGovukGoldenFiles(t *testing.T) {
+	testGoldenFiles(t, "govuk", ".html", func(request *models.RenderRequest) ([]byte, error) {
+		request.Theme = "govuk"
+		return renderer.RenderHTML(mockContext, request)
+	})
+}
+
+func TestSVGChartGoldenFiles(t *testing.T) {
+	testGoldenFiles(t, "svg-chart", ".svg", func(request *models.RenderRequest) ([]byte, error) {
+		var buf bytes.Buffer
+		err := renderer.WriteSVGChart(mockContext, &buf, request)
+		return buf.Bytes(), err
+	})
+}
+
+func TestPNGGoldenFiles(t *testing.T) {
+	testGoldenFiles(t, "png", ".png", func(request *models.RenderRequest) ([]byte, error) {
+		var buf bytes.Buffer
+		err := renderer.WritePNG(mockContext, &buf, request)
+		return buf.Bytes(), err
+	})
+}
+
+// testGoldenFiles renders each request in the named directory, and compares the output with the file of the same name
+// and the given extension
+func testGoldenFiles(t *testing.T, name string, extension string, render func(*models.RenderRequest) ([]byte, error)) {
+	dir := filepath.Join(goldenDir, name)
+	inputs, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("no golden files in %s: %v", dir, err)
+	}
+
+	for _, input := range inputs {
+		base := strings.TrimSuffix(filepath.Base(input), ".json")
+		Convey("The "+name+" output of "+base+" is as in its golden file", t, func() {
+			content, err := os.ReadFile(input)
+			So(err, ShouldBeNil)
+			request, err := models.CreateRenderRequest(mockContext, bytes.NewReader(content))
+			So(err, ShouldBeNil)
+
+			response, err := render(request)
+			So(err, ShouldBeNil)
+
+			golden := filepath.Join(dir, base+extension)
+			if *update {
+				So(os.WriteFile(golden, response, 0o644), ShouldBeNil)
+			}
+			expected, err := os.ReadFile(golden)
+			So(err, ShouldBeNil)
+			So(string(response), ShouldEqual, string(expected))
+		})
+	}
+}
+
+func TestGovukNumericCells(t *testing.T) {
+
+	Convey("Numbers are right aligned with the numeric modifier, unless the column is aligned otherwise", t, func() {
+		request := &models.RenderRequest{
+			Filename:      "myId",
+			RowFormats:    []models.RowFormat{{Row: 0, Heading: true}},
+			ColumnFormats: []models.ColumnFormat{{Column: 2, Align: models.AlignCenter}},
+			Data:          [][]string{{"Name", "Value", "Change"}, {"a", "-£1,234.50 [1]", "12%"}, {"b", "..", "3"}},
+			Footnotes:     []string{"Estimated"},
+			Theme:         "govuk",
+		}
+		response, err := renderer.RenderHTML(mockContext, request)
+		So(err, ShouldBeNil)
+		output := string(response)
+		So(output, ShouldContainSubstring, `<th scope="col" class="govuk-table__header">Name</th>`)
+		So(output, ShouldContainSubstring, `<th scope="col" class="govuk-table__header govuk-table__header--numeric">Value</th>`)
+		So(output, ShouldContainSubstring, `<td class="govuk-table__cell govuk-table__cell--numeric">-£1,234.50 <a`)
+		So(output, ShouldContainSubstring, `<td class="govuk-table__cell govuk-table__cell--numeric">..</td>`)
+		So(output, ShouldContainSubstring, `<th scope="col" class="govuk-table__header govuk-!-text-align-centre">Change</th>`)
+		So(output, ShouldContainSubstring, `<td class="govuk-table__cell govuk-!-text-align-centre">3</td>`)
+		So(output, ShouldContainSubstring, `<td class="govuk-table__cell">a</td>`)
+	})
+
+	Convey("A column containing text is not numeric, though its numbers are", t, func() {
+		request := &models.RenderRequest{
+			Filename:   "myId",
+			RowFormats: []models.RowFormat{{Row: 0, Heading: true}},
+			Data:       [][]string{{"Value"}, {"1"}, {"n/a"}, {".."}},
+			Theme:      "govuk",
+		}
+		response, err := renderer.RenderHTML(mockContext, request)
+		So(err, ShouldBeNil)
+		output := string(response)
+		So(output, ShouldContainSubstring, `<th scope="col" class="govuk-table__header">Value</th>`)
+		So(output, ShouldContainSubstring, `<td class="govuk-table__cell govuk-table__cell--numeric">1</td>`)
+		So(output, ShouldContainSubstring, `<td class="govuk-table__cell">n/a</td>`)
+		So(output, ShouldContainSubstring, `<td class="govuk-table__cell">..</td>`)
+	})
+}
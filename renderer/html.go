@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"html/template"
+	"io"
+	"iter"
 
 	"regexp"
 
@@ -11,10 +14,10 @@ import (
 
 	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
 	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
 	"github.com/ONSdigital/log.go/v2/log"
 
 	"golang.org/x/net/html"
-	"golang.org/x/net/html/atom"
 )
 
 var (
@@ -22,30 +25,36 @@ var (
 	footnoteLink   = regexp.MustCompile(`\[[0-9]+]`)
 	emptyCellModel = &cellModel{}
 
-	// text that will need internationalising at some point:
-	sourceText         = "Source: "
-	unitsText          = "Units: "
-	notesText          = "Notes"
-	footnoteHiddenText = "Footnote "
-
-	// a map of the alignments to their css classes
-	cssAlignmentMap = map[string]string{
-		models.AlignTop:     "align-top",
-		models.AlignMiddle:  "align-middle",
-		models.AlignBottom:  "align-bottom",
-		models.AlignLeft:    "align-left",
-		models.AlignCenter:  "align-center",
-		models.AlignRight:   "align-right",
-		models.AlignJustify: "align-justify",
+	// text that will need internationalising at some point (the themes contain the equivalent text for html):
+	sourceText = "Source: "
+	unitsText  = "Units: "
+	notesText  = "Notes"
+
+	// the layouts that can be used by the theme templates
+	layoutNames = map[string]string{
+		models.LayoutStacked: "stacked",
+		models.LayoutScroll:  "scroll",
+	}
+
+	// a map of the alignments to the names used in the views passed to the theme templates
+	alignmentNames = map[string]string{
+		models.AlignTop:     "top",
+		models.AlignMiddle:  "middle",
+		models.AlignBottom:  "bottom",
+		models.AlignLeft:    "left",
+		models.AlignCenter:  "center",
+		models.AlignRight:   "right",
+		models.AlignJustify: "justify",
 	}
 )
 
 // Contains details of the table that need to be calculated once from the request and cached
 type tableModel struct {
-	request *models.RenderRequest
-	columns []models.ColumnFormat
-	rows    []models.RowFormat
-	cells   map[int]map[int]*cellModel
+	request  *models.RenderRequest
+	columns  []models.ColumnFormat
+	rows     map[int]models.RowFormat // only those rows with a RowFormat - use rowFormat(i) to find the format for any row
+	cells    map[int]map[int]*cellModel
+	headRows int // the number of heading rows at the top of the table
 }
 
 // contains details of a cell that requires special handling
@@ -57,255 +66,353 @@ type cellModel struct {
 	valign  string
 }
 
-// RenderHTML returns an HTML representation of the table generated from the given request
-func RenderHTML(ctx context.Context, request *models.RenderRequest) ([]byte, error) {
-	model := createModel(ctx, request)
+// htmlModel holds what is needed to create the views of an html table
+type htmlModel struct {
+	ctx context.Context
+	*tableModel
+	theme          *Theme
+	footnoteLinks  []string // the html of the link to each footnote, as defined by the theme
+	numericColumns []bool   // whether each column holds numbers
+	columnLabels   []string // the text of the headings of each column, in the stacked layout
+}
+
+// tableView is passed to the "figure" template of a theme
+type tableView struct {
+	ID                  string // the id of the table, as used in links etc
+	HasCaption          bool
+	Title               template.HTML
+	Subtitle            template.HTML
+	Columns             []columnView      // one for each column, or nil if the request has no column formats
+	Rows                iter.Seq[rowView] // every row of the table
+	Head                iter.Seq[rowView] // the heading rows at the top of the table, or nil if there are none
+	Body                iter.Seq[rowView] // the rows following the Head
+	Units               template.HTML
+	Source              template.HTML
+	Footnotes           []footnoteView
+	KeepHeadersTogether bool
+	PlainTitle          string // the text of the title without any markup, or the filename if there is no title
+	CaptionID           string // the id of the caption
+	Layout              string // the responsive layout on narrow screens, stacked or scroll, or empty
+	Document            bool   // true if the table is rendered as a complete html document, rather than a fragment
+	Language            string // the lang of the html document
+}
+
+// columnView describes a column of the table
+type columnView struct {
+	Width template.CSS
+}
 
-	figure := h.CreateNode("figure", atom.Figure,
-		h.Attr("class", "figure"),
-		h.Attr("id", tableID(request)),
-		"\n")
+// rowView describes a row of the table
+type rowView struct {
+	Index         int
+	Heading       bool
+	Total         bool   // true for a row of totals
+	NoWrap        bool   // true if the content of the row's cells shouldn't wrap
+	VerticalAlign string // top, middle or bottom, or empty
+	Height        template.CSS
+	Cells         []cellView
+}
 
-	table := addTable(ctx, request, figure)
+// cellView describes a cell of the table. Cells hidden by a merged cell are not included.
+type cellView struct {
+	Row           int
+	Column        int
+	Header        bool   // true for a th cell
+	Scope         string // the scope of a th cell: col, colgroup, row or rowgroup
+	Colspan       int    // zero unless the cell spans more than one column
+	Rowspan       int    // zero unless the cell spans more than one row
+	Align         string // left, center, right or justify, or empty
+	VerticalAlign string // top, middle or bottom, or empty
+	NoWrap        bool   // true if the content of the cell shouldn't wrap
+	Label         string // the text of the column headings that apply to a cell of the body, in the stacked layout
+	Numeric       bool   // true if the cell holds a number (or a placeholder in a column of numbers), or heads a column of numbers
+	Content       template.HTML
+}
 
-	addColumnGroup(model, table)
-	addRows(ctx, model, table)
+// footnoteView describes a footnote
+type footnoteView struct {
+	ID      string
+	Number  int
+	Content template.HTML
+}
 
-	addFooter(ctx, request, figure)
+// footnoteLinkView is passed to the "footnote-link" template of a theme
+type footnoteLinkView struct {
+	ID     string // the id of the footnote
+	Number int
+}
 
+// RenderHTML returns an HTML representation of the table generated from the given request
+func RenderHTML(ctx context.Context, request *models.RenderRequest) ([]byte, error) {
 	var buf bytes.Buffer
-	html.Render(&buf, figure)
-	buf.WriteString("\n")
+	if err := WriteHTML(ctx, &buf, request); err != nil {
+		return nil, err
+	}
 	return buf.Bytes(), nil
 }
 
-// tableID returns the id for the table, as used in links etc
-func tableID(request *models.RenderRequest) string {
-	return "table-" + request.Filename
+// WriteHTML writes an HTML representation of the table generated from the given request to w, using the theme named
+// in the request
+func WriteHTML(ctx context.Context, w io.Writer, request *models.RenderRequest) error {
+	return writeHTML(ctx, w, request, "html", false)
 }
 
-// addTable creates a table node with a caption and adds it to the given node
-func addTable(ctx context.Context, request *models.RenderRequest, parent *html.Node) *html.Node {
-	table := h.CreateNode("table", atom.Table,
-		h.Attr("class", "table"),
-		"\n")
-
-	// add title and subtitle as a caption
-	if len(request.Title) > 0 || len(request.Subtitle) > 0 {
-		caption := h.CreateNode("caption", atom.Caption,
-			h.Attr("class", "table__caption"),
-			parseValue(ctx, request, request.Title))
-		if len(request.Subtitle) > 0 {
-			subtitle := h.CreateNode("span", atom.Span,
-				h.Attr("class", "table__subtitle"),
-				parseValue(ctx, request, request.Subtitle))
-
-			caption.AppendChild(h.CreateNode("br", atom.Br))
-			caption.AppendChild(subtitle)
+// writeHTML writes the table using the theme named in the request, as a fragment starting with the "figure" template,
+// or a complete html page starting with the "document" template
+func writeHTML(ctx context.Context, w io.Writer, request *models.RenderRequest, format string, document bool) error {
+	theme, err := FindTheme(request.Theme)
+	if err != nil {
+		return err
+	}
+	model, err := newHTMLModel(ctx, request, theme)
+	if err != nil {
+		log.Error(ctx, "unable to create html model", err, log.Data{"file_name": request.Filename, "theme": theme.Name})
+		return renderError(format, err)
+	}
+
+	view := model.tableView()
+	name := "figure"
+	if document {
+		name = "document"
+		view.Document = true
+		view.Language = documentLanguage(request)
+	}
+	_, span := tracing.StartSpan(ctx, "write", tracing.Format.String(format))
+	err = theme.template.ExecuteTemplate(w, name, view)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		log.Error(ctx, "unable to render html", err, log.Data{"file_name": request.Filename, "theme": theme.Name, "format": format})
+		return renderError(format, err)
+	}
+	return nil
+}
 
+// newHTMLModel creates the model of the table, and the links to its footnotes
+func newHTMLModel(ctx context.Context, request *models.RenderRequest, theme *Theme) (*htmlModel, error) {
+	m := &htmlModel{ctx: ctx, tableModel: createModel(ctx, request), theme: theme}
+	m.numericColumns = m.findNumericColumns()
+	if request.Layout == models.LayoutStacked {
+		m.columnLabels = m.findColumnLabels()
+	}
+	var link strings.Builder
+	for i := range request.Footnotes {
+		link.Reset()
+		view := footnoteLinkView{ID: footnoteID(request, i+1), Number: i + 1}
+		if err := theme.template.ExecuteTemplate(&link, "footnote-link", view); err != nil {
+			return nil, err
 		}
-		table.AppendChild(caption)
-		table.AppendChild(h.Text("\n"))
+		m.footnoteLinks = append(m.footnoteLinks, link.String())
 	}
-	parent.AppendChild(table)
-	return table
+	return m, nil
 }
 
-// addColumnGroup adds a columnGroup, if required, to the given table. Cols in the colgroup specify column width.
-func addColumnGroup(model *tableModel, table *html.Node) {
-	if len(model.request.ColumnFormats) > 0 {
-		colgroup := h.CreateNode("colgroup", atom.Colgroup)
+// tableID returns the id for the table, as used in links etc
+func tableID(request *models.RenderRequest) string {
+	return "table-" + request.Filename
+}
 
-		for _, col := range model.columns {
-			node := h.CreateNode("col", atom.Col)
-			if len(col.Width) > 0 {
-				h.AddAttribute(node, "style", "width: "+col.Width)
-			}
-			colgroup.AppendChild(node)
+// footnoteID returns the id of the nth footnote
+func footnoteID(request *models.RenderRequest, n int) string {
+	return fmt.Sprintf("table-%s-note-%d", request.Filename, n)
+}
+
+// tableView creates the view of the whole table. The rows are created as the template iterates over them.
+func (m *htmlModel) tableView() *tableView {
+	request := m.request
+	view := &tableView{
+		ID:                  tableID(request),
+		HasCaption:          len(request.Title) > 0 || len(request.Subtitle) > 0,
+		Title:               m.parseValue(request.Title),
+		PlainTitle:          plainTitle(request),
+		CaptionID:           tableID(request) + "-caption",
+		Layout:              layoutNames[request.Layout],
+		Rows:                m.rowViews(0, len(request.Data)),
+		KeepHeadersTogether: request.KeepHeadersTogether,
+	}
+	if len(request.Subtitle) > 0 {
+		view.Subtitle = m.parseValue(request.Subtitle)
+	}
+	if len(request.ColumnFormats) > 0 {
+		view.Columns = make([]columnView, len(m.columns))
+		for i, col := range m.columns {
+			view.Columns[i].Width = template.CSS(col.Width)
 		}
+	}
 
-		table.AppendChild(colgroup)
-		table.AppendChild(h.Text("\n"))
+	if m.headRows > 0 {
+		view.Head = m.rowViews(0, m.headRows)
 	}
+	view.Body = m.rowViews(m.headRows, len(request.Data))
+
+	if len(request.Units) > 0 {
+		view.Units = m.parseValue(request.Units)
+	}
+	if len(request.Source) > 0 {
+		view.Source = m.parseValue(request.Source)
+	}
+	for i, note := range request.Footnotes {
+		view.Footnotes = append(view.Footnotes, footnoteView{
+			ID:      footnoteID(request, i+1),
+			Number:  i + 1,
+			Content: m.parseValue(note),
+		})
+	}
+	return view
 }
 
-// adds all rows to the table. Rows contain th or td cells as appropriate.
-func addRows(ctx context.Context, model *tableModel, table *html.Node) {
-	for rowIdx, row := range model.request.Data {
-		tr := h.CreateNode("tr", atom.Tr)
-		table.AppendChild(tr)
-		if model.rows[rowIdx].Heading {
-			h.AddAttribute(tr, "class", "table__header-row")
-			if model.request.KeepHeadersTogether {
-				h.AppendAttribute(tr, "class", "table__nowrap")
+// rowViews returns a sequence of the views of rows from start up to (but not including) end
+func (m *htmlModel) rowViews(start int, end int) iter.Seq[rowView] {
+	return func(yield func(rowView) bool) {
+		for i := start; i < end; i++ {
+			if !yield(m.rowView(i)) {
+				return
 			}
 		}
-		if len(model.rows[rowIdx].VerticalAlign) > 0 {
-			h.AppendAttribute(tr, "class", mapAlignmentToClass(model.rows[rowIdx].VerticalAlign))
-		}
-		if len(model.rows[rowIdx].Height) > 0 {
-			h.AddAttribute(tr, "style", "height: "+model.rows[rowIdx].Height)
+	}
+}
+
+// rowView creates the view of a row, and its cells
+func (m *htmlModel) rowView(rowIdx int) rowView {
+	rowFormat := m.rowFormat(rowIdx)
+	view := rowView{
+		Index:         rowIdx,
+		Heading:       rowFormat.Heading,
+		Total:         rowFormat.Total,
+		NoWrap:        rowFormat.Heading && m.request.KeepHeadersTogether,
+		VerticalAlign: alignmentNames[rowFormat.VerticalAlign],
+		Height:        template.CSS(rowFormat.Height),
+	}
+	row := m.request.Data[rowIdx]
+	view.Cells = make([]cellView, 0, len(row))
+	for colIdx, value := range row {
+		cell := m.cells[rowIdx][colIdx]
+		if cell == nil {
+			cell = emptyCellModel
 		}
-		for colIdx, col := range row {
-			addTableCell(ctx, model, tr, col, rowIdx, colIdx)
+		if !cell.skip {
+			view.Cells = append(view.Cells, m.cellView(cell, rowFormat, value, rowIdx, colIdx))
 		}
-		table.AppendChild(h.Text("\n"))
 	}
+	return view
 }
 
-// adds an individual table cell to the given tr node
-func addTableCell(ctx context.Context, model *tableModel, tr *html.Node, colText string, rowIdx int, colIdx int) {
-	cell := model.cells[rowIdx][colIdx]
-	if cell == nil {
-		cell = emptyCellModel
-	}
-	if cell.skip {
-		return
+// cellView creates the view of an individual cell
+func (m *htmlModel) cellView(cell *cellModel, rowFormat models.RowFormat, value string, rowIdx int, colIdx int) cellView {
+	column := m.columns[colIdx]
+	hasContent := len(value) > 0
+	view := cellView{
+		Row:           rowIdx,
+		Column:        colIdx,
+		Align:         alignmentNames[column.Align],
+		VerticalAlign: alignmentNames[cell.valign],
+		Content:       m.parseValueWithHTML(value),
 	}
-	value := parseValueWithHtml(ctx, model.request, colText)
-	hasContent := len(colText) > 0
-	var node *html.Node
-	if model.rows[rowIdx].Heading && hasContent {
-		node = h.CreateNode("th", atom.Th, h.Attr("scope", "col"), value)
+	if rowFormat.Heading && hasContent {
+		view.Header = true
+		view.Scope = "col"
 		if cell.colspan > 1 {
-			h.ReplaceAttribute(node, "scope", "colgroup")
+			view.Scope = "colgroup"
 		}
-	} else if model.columns[colIdx].Heading && hasContent {
-		node = h.CreateNode("th", atom.Th, h.Attr("scope", "row"), value)
+	} else if column.Heading && hasContent {
+		view.Header = true
+		view.Scope = "row"
 		if cell.rowspan > 1 {
-			h.ReplaceAttribute(node, "scope", "rowgroup")
-		}
-		if model.request.KeepHeadersTogether {
-			h.AddAttribute(node, "class", "table__nowrap")
+			view.Scope = "rowgroup"
 		}
+		view.NoWrap = m.request.KeepHeadersTogether
 	} else {
-		node = h.CreateNode("td", atom.Td, value)
+		view.Numeric = isNumeric(value) || (m.numericColumns[colIdx] && isPlaceholder(value))
+	}
+	if view.Scope == "col" || view.Scope == "colgroup" {
+		view.Numeric = m.numericColumns[colIdx]
+		for c := colIdx + 1; c < colIdx+cell.colspan && c < len(m.numericColumns); c++ {
+			view.Numeric = view.Numeric && m.numericColumns[c]
+		}
 	}
 	if cell.colspan > 1 {
-		h.AddAttribute(node, "colspan", fmt.Sprintf("%d", cell.colspan))
+		view.Colspan = cell.colspan
 	}
 	if cell.rowspan > 1 {
-		h.AddAttribute(node, "rowspan", fmt.Sprintf("%d", cell.rowspan))
+		view.Rowspan = cell.rowspan
 	}
 	if len(cell.align) > 0 {
-		h.AppendAttribute(node, "class", mapAlignmentToClass(cell.align))
-	} else if len(model.columns[colIdx].Align) > 0 {
-		h.AppendAttribute(node, "class", mapAlignmentToClass(model.columns[colIdx].Align))
-	}
-	if len(cell.valign) > 0 {
-		h.AppendAttribute(node, "class", mapAlignmentToClass(cell.valign))
-	}
-	tr.AppendChild(node)
-}
-
-// mapAlignmentToClass converts a VerticalAlign or Align value into a css class
-func mapAlignmentToClass(align string) string {
-	return cssAlignmentMap[align]
-}
-
-// addFooter adds a footer to the given element, containing the source and footnotes
-func addFooter(ctx context.Context, request *models.RenderRequest, parent *html.Node) {
-	footer := h.CreateNode("footer", atom.Footer,
-		h.Attr("class", "figure__footer"),
-		"\n")
-	if len(request.Units) > 0 {
-		footer.AppendChild(h.CreateNode("p", atom.P,
-			h.Attr("class", "figure__units"),
-			parseValue(ctx, request, unitsText+request.Units)))
-		footer.AppendChild(h.Text("\n"))
+		view.Align = alignmentNames[cell.align]
 	}
-	if len(request.Source) > 0 {
-		footer.AppendChild(h.CreateNode("p", atom.P,
-			h.Attr("class", "figure__source"),
-			parseValue(ctx, request, sourceText+request.Source)))
-		footer.AppendChild(h.Text("\n"))
-	}
-	if len(request.Footnotes) > 0 {
-		footer.AppendChild(h.CreateNode("p", atom.P,
-			h.Attr("class", "figure__notes"),
-			notesText))
-		footer.AppendChild(h.Text("\n"))
-
-		ol := h.CreateNode("ol", atom.Ol,
-			h.Attr("class", "figure__footnotes"),
-			"\n")
-		addFooterItemsToList(ctx, request, ol)
-		footer.AppendChild(ol)
-		footer.AppendChild(h.Text("\n"))
-	}
-	parent.AppendChild(footer)
-	parent.AppendChild(h.Text("\n"))
-}
-
-// addFooterItemsToList adds one li node for each footnote to the given list node
-func addFooterItemsToList(ctx context.Context, request *models.RenderRequest, ol *html.Node) {
-	for i, note := range request.Footnotes {
-		li := h.CreateNode("li", atom.Li,
-			h.Attr("id", fmt.Sprintf("table-%s-note-%d", request.Filename, i+1)),
-			h.Attr("class", "figure__footnote-item"),
-			parseValue(ctx, request, note))
-		ol.AppendChild(li)
-		ol.AppendChild(h.Text("\n"))
+	if m.columnLabels != nil && rowIdx >= m.headRows {
+		view.Label = m.columnLabels[colIdx]
 	}
+	return view
 }
 
-// Parses the string to replace \n with <br /> and wrap [1] with a link to the footnote
-func parseValue(ctx context.Context, request *models.RenderRequest, value string) []*html.Node {
+// parseValue converts the string to html, replacing \n with <br /> and wrapping [1] with a link to the footnote.
+// Any other html in the value is escaped.
+func (m *htmlModel) parseValue(value string) template.HTML {
 	hasBr := newLine.MatchString(value)
-	hasFootnote := len(request.Footnotes) > 0 && footnoteLink.MatchString(value)
+	hasFootnote := len(m.request.Footnotes) > 0 && footnoteLink.MatchString(value)
 	if hasBr || hasFootnote {
-		return replaceValues(ctx, request, value, hasBr, hasFootnote)
+		return m.replaceValues(value, hasBr, hasFootnote)
 	}
-	return []*html.Node{{Type: html.TextNode, Data: value}}
+	return template.HTML(template.HTMLEscapeString(value))
 }
 
-// Parses the string to replace \n with <br /> and wrap [1] with a link to the footnote
-// keeping any HTML tags that are present
-func parseValueWithHtml(ctx context.Context, request *models.RenderRequest, value string) []*html.Node {
+// parseValueWithHTML converts the string to html, replacing \n with <br /> and wrapping [1] with a link to the footnote,
+// keeping the tags of the canonical inline markup and the text of any other elements
+func (m *htmlModel) parseValueWithHTML(value string) template.HTML {
+	original := value
+	value, err := h.SanitiseInline(value)
+	if err != nil {
+		log.Error(m.ctx, "unable to parse value", err, log.Data{"value": original})
+		return template.HTML(template.HTMLEscapeString(original))
+	}
 	hasBr := newLine.MatchString(value)
-	hasFootnote := len(request.Footnotes) > 0 && footnoteLink.MatchString(value)
+	hasFootnote := len(m.request.Footnotes) > 0 && footnoteLink.MatchString(value)
 	if hasBr || hasFootnote {
-		return replaceValues(ctx, request, value, hasBr, hasFootnote)
-	}
-	// Create Nodes
-	nodes, _ := html.ParseFragment(strings.NewReader(value), &html.Node{
-		Type:     html.ElementNode,
-		Data:     "body",
-		DataAtom: atom.Body})
-	return nodes
+		return m.replaceValues(value, hasBr, hasFootnote)
+	}
+	return m.normaliseHTML(value, value)
 }
 
-// replaceValues uses regexp to replace new lines and footnotes with <br/> and <a>.../<a> tags, then parses the result into an array of nodes
-func replaceValues(ctx context.Context, request *models.RenderRequest, value string, hasBr bool, hasFootnote bool) []*html.Node {
+// replaceValues replaces new lines and footnotes with <br/> and links to the footnote, then normalises the html
+func (m *htmlModel) replaceValues(value string, hasBr bool, hasFootnote bool) template.HTML {
 	original := value
 	if hasBr {
 		value = newLine.ReplaceAllLiteralString(value, "<br />")
 	}
 	if hasFootnote {
-		for i := range request.Footnotes {
-			n := i + 1
-			linkText := fmt.Sprintf("<a href=\"#table-%s-note-%d\" class=\"footnote__link\"><span class=\"visuallyhidden\">%s</span>%d</a>", request.Filename, n, footnoteHiddenText, n)
-			value = strings.Replace(value, fmt.Sprintf("[%d]", n), linkText, -1)
+		for i, link := range m.footnoteLinks {
+			value = strings.Replace(value, fmt.Sprintf("[%d]", i+1), link, -1)
 		}
 	}
-	nodes, err := html.ParseFragment(strings.NewReader(value), &html.Node{
-		Type:     html.ElementNode,
-		Data:     "body",
-		DataAtom: atom.Body,
-	})
+	return m.normaliseHTML(value, original)
+}
+
+// normaliseHTML parses the html fragment and renders it again, so that it is well formed.
+// If the html can't be parsed the original value is escaped instead.
+func (m *htmlModel) normaliseHTML(value string, original string) template.HTML {
+	nodes, err := h.ParseInline(value)
+	var b strings.Builder
+	for i := 0; err == nil && i < len(nodes); i++ {
+		err = html.Render(&b, nodes[i])
+	}
 	if err != nil {
-		log.Error(ctx, "unable to parse value", err, log.Data{"value": original})
-		return []*html.Node{{Type: html.TextNode, Data: original}}
+		log.Error(m.ctx, "unable to parse value", err, log.Data{"value": original})
+		return template.HTML(template.HTMLEscapeString(original))
 	}
-	return nodes
+	return template.HTML(b.String())
 }
 
 // Creates a tableModel containing calculations that are referenced more than once while rendering the table
 func createModel(ctx context.Context, request *models.RenderRequest) *tableModel {
+	ctx, span := tracing.StartSpan(ctx, "createModel")
+	defer span.End()
+
 	m := tableModel{request: request}
 	m.columns = indexColumnFormats(ctx, request)
 	m.rows = indexRowFormats(ctx, request)
 	m.cells = createCellModels(request)
+	for m.headRows < len(request.Data) && m.rowFormat(m.headRows).Heading {
+		m.headRows++
+	}
 	return &m
 }
 
@@ -335,18 +442,14 @@ func indexColumnFormats(ctx context.Context, request *models.RenderRequest) []mo
 	return columns
 }
 
-// indexes the RowFormats so that rows[i] gives the correct format for row i
-func indexRowFormats(ctx context.Context, request *models.RenderRequest) []models.RowFormat {
+// indexes the RowFormats so that rows[i] gives the format for row i, if it has one. Rows without a format are not
+// included, so that the size of the index doesn't grow with the number of rows in the table.
+func indexRowFormats(ctx context.Context, request *models.RenderRequest) map[int]models.RowFormat {
 	count := len(request.Data)
-	// create default RowFormats
-	rows := make([]models.RowFormat, count)
-	for i := range rows {
-		rows[i] = models.RowFormat{Row: i}
-	}
-	// replace with actual RowFormats where they exist
+	rows := make(map[int]models.RowFormat)
 	for _, format := range request.RowFormats {
 		if format.Row >= count || format.Row < 0 {
-			log.Info(ctx, "ColumnFormat specified for non-existent column", log.Data{"file_name": request.Filename, "row_format": format, "row_count": count})
+			log.Info(ctx, "RowFormat specified for non-existent row", log.Data{"file_name": request.Filename, "row_format": format, "row_count": count})
 		} else {
 			rows[format.Row] = format
 		}
@@ -354,6 +457,14 @@ func indexRowFormats(ctx context.Context, request *models.RenderRequest) []model
 	return rows
 }
 
+// rowFormat returns the format for row i, or a default format if the row has none
+func (m *tableModel) rowFormat(i int) models.RowFormat {
+	if format, exists := m.rows[i]; exists {
+		return format
+	}
+	return models.RowFormat{Row: i}
+}
+
 // creates a map with one cellModel for each cell that requires special handling
 func createCellModels(request *models.RenderRequest) map[int]map[int]*cellModel {
 	m := make(map[int]map[int]*cellModel)
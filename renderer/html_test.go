@@ -251,6 +251,19 @@ func TestRenderHTML_FootnoteLinks(t *testing.T) {
 	})
 }
 
+func TestRenderHTML_InlineMarkup(t *testing.T) {
+
+	Convey("A renderRequest with markup in its values should keep only the canonical inline markup", t, func() {
+		request := models.RenderRequest{Filename: "myId", Data: [][]string{{`<b>Total</b> x<sup>2</sup><span class="x">km</span><script>alert(1)</script>`, "<p>a</p><p>b</p>"}}}
+		_, raw := invokeRenderHTML(&request)
+
+		So(raw, ShouldContainSubstring, "<strong>Total</strong> x<sup>2</sup>km</td>")
+		So(raw, ShouldContainSubstring, "a<br/>b</td>")
+		So(raw, ShouldNotContainSubstring, "script")
+		So(raw, ShouldNotContainSubstring, "<span")
+	})
+}
+
 func TestRenderHTML_ColumnFormats(t *testing.T) {
 
 	Convey("A renderRequest with column formats should output colgroup", t, func() {
@@ -407,6 +420,18 @@ func TestRenderHTML_Rows(t *testing.T) {
 		So(class, ShouldContainSubstring, "align-top")
 	})
 
+	Convey("Rows of totals should have the correct class", t, func() {
+		rowFormats := []models.RowFormat{{Row: 1, Total: true}}
+		cells := [][]string{{"Cell 1", "Cell 2"}, {"Total", "Cell 2"}}
+		request := models.RenderRequest{Filename: "myId", RowFormats: rowFormats, Data: cells}
+		container, _ := invokeRenderHTML(&request)
+		table := FindNode(container, atom.Table)
+
+		rows := FindNodes(table, atom.Tr)
+		So(GetAttribute(rows[0], "class"), ShouldNotContainSubstring, "table__total-row")
+		So(GetAttribute(rows[1], "class"), ShouldContainSubstring, "table__total-row")
+	})
+
 }
 
 func TestRenderHTML_MergeCells(t *testing.T) {
@@ -0,0 +1,52 @@
+package renderer
+
+import (
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+)
+
+// columnLabelSeparator separates the headings of a column that has more than one heading row, e.g. "2017: Jan"
+const columnLabelSeparator = ": "
+
+// plainTitle returns the text of the table's title without any markup, or the filename if the table has no title
+func plainTitle(request *models.RenderRequest) string {
+	if title := plainText(request.Title); len(title) > 0 {
+		return title
+	}
+	return request.Filename
+}
+
+// plainText returns the text of an html value without any markup or references to footnotes, on a single line
+func plainText(value string) string {
+	return strings.Join(strings.Fields(h.InlineText(footnoteLink.ReplaceAllLiteralString(value, ""))), " ")
+}
+
+// findColumnLabels returns the text of the headings of each column: the cells of the heading rows at the top of the
+// table that span the column, from the top down. A merged heading labels every column it spans.
+func (m *tableModel) findColumnLabels() []string {
+	headings := make([][]string, len(m.columns))
+	for rowIdx := 0; rowIdx < m.headRows; rowIdx++ {
+		for colIdx, value := range m.request.Data[rowIdx] {
+			cell := m.cells[rowIdx][colIdx]
+			if cell == nil {
+				cell = emptyCellModel
+			}
+			text := plainText(strings.ReplaceAll(value, "\n", " "))
+			if cell.skip || len(text) == 0 {
+				continue
+			}
+			for c := colIdx; c < colIdx+max(cell.colspan, 1) && c < len(headings); c++ {
+				if n := len(headings[c]); n == 0 || headings[c][n-1] != text {
+					headings[c] = append(headings[c], text)
+				}
+			}
+		}
+	}
+	labels := make([]string, len(headings))
+	for i, text := range headings {
+		labels[i] = strings.Join(text, columnLabelSeparator)
+	}
+	return labels
+}
@@ -0,0 +1,106 @@
+package renderer_test
+
+import (
+	"testing"
+
+	. "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/html/atom"
+)
+
+// a table with two heading rows, the first of which has a merged heading
+func responsiveRequest(layout string) *models.RenderRequest {
+	return &models.RenderRequest{
+		Filename:      "myId",
+		Title:         "Prices",
+		Layout:        layout,
+		RowFormats:    []models.RowFormat{{Row: 0, Heading: true}, {Row: 1, Heading: true}},
+		ColumnFormats: []models.ColumnFormat{{Column: 0, Heading: true}},
+		CellFormats:   []models.CellFormat{{Row: 0, Column: 0, Rowspan: 2}, {Row: 0, Column: 1, Colspan: 2}},
+		Data: [][]string{
+			{"Year", "Index [1]", ""},
+			{"", "Value", "Change\n(%)"},
+			{"2016", "101.8", "1.5"},
+			{"2017", "103.2", "2.6"},
+		},
+		Footnotes: []string{"2015 = 100"},
+	}
+}
+
+func TestStackedLayout(t *testing.T) {
+
+	Convey("In the stacked layout, each cell of the body is labelled with its column headings", t, func() {
+		container, _ := invokeRenderHTML(responsiveRequest(models.LayoutStacked))
+
+		table := FindNode(container, atom.Table)
+		So(GetAttribute(table, "data-layout"), ShouldEqual, "stacked")
+		So(len(FindNodes(FindNode(table, atom.Thead), atom.Tr)), ShouldEqual, 2)
+
+		rows := FindNodes(FindNode(table, atom.Tbody), atom.Tr)
+		So(len(rows), ShouldEqual, 2)
+		cells := FindAllNodes(rows[0], atom.Th, atom.Td)
+		So(GetAttribute(cells[0], "data-label"), ShouldEqual, "Year")
+		So(GetAttribute(cells[1], "data-label"), ShouldEqual, "Index: Value")
+		So(GetAttribute(cells[2], "data-label"), ShouldEqual, "Index: Change (%)")
+
+		Convey("And the cells of the heading rows are not labelled", func() {
+			for _, cell := range FindNodes(FindNode(table, atom.Thead), atom.Th) {
+				So(GetAttribute(cell, "data-label"), ShouldBeEmpty)
+			}
+		})
+	})
+
+	Convey("A table without heading rows has no labels", t, func() {
+		request := &models.RenderRequest{Filename: "myId", Layout: models.LayoutStacked, Data: [][]string{{"a", "1"}}}
+		_, output := invokeRenderHTML(request)
+		So(output, ShouldNotContainSubstring, "data-label")
+	})
+}
+
+func TestScrollLayout(t *testing.T) {
+
+	Convey("In the scroll layout, the table is in a focusable region labelled by its caption", t, func() {
+		container, _ := invokeRenderHTML(responsiveRequest(models.LayoutScroll))
+
+		region := FindNode(container, atom.Div)
+		So(region, ShouldNotBeNil)
+		So(GetAttribute(region, "role"), ShouldEqual, "region")
+		So(GetAttribute(region, "tabindex"), ShouldEqual, "0")
+		So(GetAttribute(region, "aria-labelledby"), ShouldEqual, "table-myId-caption")
+		So(GetAttribute(FindNode(region, atom.Caption), "id"), ShouldEqual, "table-myId-caption")
+		So(GetAttribute(FindNode(region, atom.Table), "data-layout"), ShouldEqual, "scroll")
+	})
+
+	Convey("A table without a caption has a region labelled by its filename", t, func() {
+		request := &models.RenderRequest{Filename: "myId", Layout: models.LayoutScroll, Data: [][]string{{"a"}}}
+		container, _ := invokeRenderHTML(request)
+		region := FindNode(container, atom.Div)
+		So(GetAttribute(region, "aria-label"), ShouldEqual, "myId")
+		So(GetAttribute(region, "aria-labelledby"), ShouldBeEmpty)
+	})
+
+	Convey("Each theme supports the responsive layouts", t, func() {
+		for _, theme := range []string{"bare", "govuk"} {
+			request := responsiveRequest(models.LayoutScroll)
+			request.Theme = theme
+			output, err := renderer.RenderHTML(mockContext, request)
+			So(err, ShouldBeNil)
+			So(string(output), ShouldContainSubstring, `role="region" tabindex="0" aria-labelledby="table-myId-caption"`)
+			So(string(output), ShouldContainSubstring, `id="table-myId-caption"`)
+
+			request = responsiveRequest(models.LayoutStacked)
+			request.Theme = theme
+			output, err = renderer.RenderHTML(mockContext, request)
+			So(err, ShouldBeNil)
+			So(string(output), ShouldContainSubstring, `data-label="Index: Change (%)"`)
+		}
+	})
+
+	Convey("An unknown layout is ignored", t, func() {
+		_, output := invokeRenderHTML(responsiveRequest("carousel"))
+		So(output, ShouldNotContainSubstring, "data-layout")
+		So(output, ShouldNotContainSubstring, "<thead")
+	})
+}
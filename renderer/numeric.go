@@ -0,0 +1,129 @@
+package renderer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// currency symbols that may precede a number
+const currencySymbols = "£$€¥"
+
+// trailingFootnoteLinks matches references to footnotes at the end of a value, e.g. the " [1][2]" of "12 [1][2]"
+var trailingFootnoteLinks = regexp.MustCompile(`(\s*\[[0-9]+])+\s*$`)
+
+// placeholders are the values used in place of a number that is missing, suppressed or not applicable,
+// e.g. ".." or the shorthand of the GSS guidance on symbols in tables such as [x] or [c]
+var placeholders = map[string]bool{
+	"..": true,
+	":":  true,
+	"-":  true,
+	"–":  true,
+	"—":  true,
+	"*":  true,
+}
+
+// isNumeric returns true if the value is a number, optionally with a sign, currency symbol, thousands separators,
+// a percent sign and references to footnotes, e.g. "-£1,234.5 [1]" or "12%"
+func isNumeric(value string) bool {
+	value = trimFootnoteReferences(value)
+	value = strings.TrimPrefix(value, "-")
+	value = strings.TrimPrefix(value, "+")
+	value = strings.TrimPrefix(value, "−") // the unicode minus sign
+	if r, size := utf8.DecodeRuneInString(value); size > 0 && strings.ContainsRune(currencySymbols, r) {
+		value = value[size:]
+	}
+	value = strings.TrimSuffix(value, "%")
+
+	digits := 0
+	decimalPoint := false
+	for i, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == ',' && !decimalPoint && digits > 0 && i+1 < len(value):
+			// a thousands separator
+		case r == '.' && !decimalPoint && i+1 < len(value):
+			decimalPoint = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// numericValue returns the number in the value, and whether the value is a number as defined by isNumeric
+func numericValue(value string) (float64, bool) {
+	if !isNumeric(value) {
+		return 0, false
+	}
+	value = strings.Map(func(r rune) rune {
+		switch {
+		case r == '−':
+			return '-'
+		case r == ',' || r == '%' || r == '+' || strings.ContainsRune(currencySymbols, r):
+			return -1
+		}
+		return r
+	}, trimFootnoteReferences(value))
+	f, err := strconv.ParseFloat(value, 64)
+	return f, err == nil
+}
+
+// isPlaceholder returns true if the value stands in for a number, e.g. "..", "-" or "[x]"
+func isPlaceholder(value string) bool {
+	value = strings.TrimSpace(value)
+	if placeholders[value] {
+		return true
+	}
+	if len(value) < 3 || value[0] != '[' || value[len(value)-1] != ']' {
+		return false
+	}
+	for _, r := range value[1 : len(value)-1] {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// trimFootnoteReferences removes any references to footnotes (e.g. [1]) and spaces from the end of the value
+func trimFootnoteReferences(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.HasSuffix(value, "]") {
+		return value
+	}
+	return strings.TrimSpace(trailingFootnoteLinks.ReplaceAllLiteralString(value, ""))
+}
+
+// findNumericColumns returns whether each column of the table holds numbers: those whose cells (other than headings)
+// are all numbers or placeholders for numbers, and at least one is a number
+func (m *tableModel) findNumericColumns() []bool {
+	numbers := make([]int, len(m.columns))
+	text := make([]bool, len(m.columns))
+	for rowIdx, row := range m.request.Data {
+		if m.rowFormat(rowIdx).Heading {
+			continue
+		}
+		for colIdx, value := range row {
+			if m.columns[colIdx].Heading || text[colIdx] {
+				continue
+			}
+			if cell := m.cells[rowIdx][colIdx]; cell != nil && cell.skip {
+				continue
+			}
+			switch {
+			case isNumeric(value):
+				numbers[colIdx]++
+			case len(strings.TrimSpace(value)) > 0 && !isPlaceholder(value):
+				text[colIdx] = true
+			}
+		}
+	}
+	numeric := make([]bool, len(m.columns))
+	for i := range numeric {
+		numeric[i] = numbers[i] > 0 && !text[i]
+	}
+	return numeric
+}
@@ -0,0 +1,36 @@
+package renderer
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIsNumeric(t *testing.T) {
+	t.Parallel()
+
+	Convey("Numbers are recognised with signs, currency, separators, percentages and footnote references", t, func() {
+		for _, value := range []string{"0", "1234", "1,234", "-1.5", "+2", "−3", "£12.50", "-$1,000", "12%", ".5", " 42 ", "7 [1]", "8[1][2]"} {
+			So(isNumeric(value), ShouldBeTrue)
+		}
+	})
+
+	Convey("Text, placeholders and malformed numbers are not numbers", t, func() {
+		for _, value := range []string{"", "abc", "1a", "1.2.3", "1,", "1.", ",1", "..", "[x]", "-", "%", "£", "[1]", "<b>1</b>"} {
+			So(isNumeric(value), ShouldBeFalse)
+		}
+	})
+}
+
+func TestIsPlaceholder(t *testing.T) {
+	t.Parallel()
+
+	Convey("Shorthand for missing or suppressed numbers is recognised", t, func() {
+		for _, value := range []string{"..", ":", "-", "–", "[x]", "[c]", "[low]", " [z] "} {
+			So(isPlaceholder(value), ShouldBeTrue)
+		}
+		for _, value := range []string{"", "x", "[1]", "[X]", "[]", "n/a"} {
+			So(isPlaceholder(value), ShouldBeFalse)
+		}
+	})
+}
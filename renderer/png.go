@@ -0,0 +1,671 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
+	"github.com/ONSdigital/log.go/v2/log"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// the size of an image if the request doesn't specify one, and the limits of the size that can be requested
+const (
+	defaultImageWidth = 1200
+	minImageWidth     = 200
+	maxImageWidth     = 4000
+	maxImageScale     = 4
+	maxImagePixels    = 50_000_000 // the largest image, after scaling, that will be drawn
+)
+
+// the sizes of the text and spacing of an image, in pixels before scaling
+const (
+	imageFontSize       = 16
+	imageTitleSize      = 22
+	imageLineHeight     = 1.4 // as a multiple of the size of the font
+	imageMargin         = 24
+	imageCellPaddingX   = 8
+	imageCellPaddingY   = 6
+	imageMinColumnWidth = 40
+	imageSectionSpacing = 12 // between the caption, the table and the footer
+)
+
+// imageTheme is the colour scheme of an image
+type imageTheme struct {
+	background        color.RGBA
+	text              color.RGBA
+	muted             color.RGBA // the subtitle and footer
+	headingBackground color.RGBA
+	headingText       color.RGBA
+	border            color.RGBA
+	headingBorder     color.RGBA // the border below the heading rows
+}
+
+// the colour themes of images. The themes that share the name of an html theme have the same colours.
+var imageThemes = map[string]imageTheme{
+	"ons": {
+		background:        rgb(0xffffff),
+		text:              rgb(0x222222),
+		muted:             rgb(0x414042),
+		headingBackground: rgb(0xf5f5f6),
+		headingText:       rgb(0x222222),
+		border:            rgb(0xd9d9d9),
+		headingBorder:     rgb(0x206095),
+	},
+	"govuk": {
+		background:        rgb(0xffffff),
+		text:              rgb(0x0b0c0c),
+		muted:             rgb(0x505a5f),
+		headingBackground: rgb(0xffffff),
+		headingText:       rgb(0x0b0c0c),
+		border:            rgb(0xb1b4b6),
+		headingBorder:     rgb(0x0b0c0c),
+	},
+	"bare": {
+		background:        rgb(0xffffff),
+		text:              rgb(0x000000),
+		muted:             rgb(0x000000),
+		headingBackground: rgb(0xffffff),
+		headingText:       rgb(0x000000),
+		border:            rgb(0x808080),
+		headingBorder:     rgb(0x000000),
+	},
+	"dark": {
+		background:        rgb(0x222222),
+		text:              rgb(0xf5f5f6),
+		muted:             rgb(0xd9d9d9),
+		headingBackground: rgb(0x003c57),
+		headingText:       rgb(0xffffff),
+		border:            rgb(0x707070),
+		headingBorder:     rgb(0x27a0cc),
+	},
+}
+
+// the fonts of images, embedded in the binary so that the output doesn't depend on the fonts installed
+var (
+	regularFont = mustParseFont(goregular.TTF)
+	boldFont    = mustParseFont(gobold.TTF)
+)
+
+// imageModel holds the layout of an image of a table. All sizes are in pixels, after scaling.
+type imageModel struct {
+	table        *tableModel
+	theme        imageTheme
+	scale        float64
+	width        int
+	height       int
+	regular      font.Face
+	bold         font.Face
+	title        font.Face
+	lineHeight   int
+	titleHeight  int // the line height of the title
+	caption      []imageText
+	footer       []imageText
+	columnWidths []int
+	rowHeights   []int
+	cells        map[int]map[int][]string // the wrapped lines of text of each visible cell, by row and column
+}
+
+// imageText is a line of the caption or footer
+type imageText struct {
+	text   string
+	face   font.Face
+	colour color.RGBA
+	height int
+}
+
+// ImageThemeNames returns the names of the colour themes of png images, in alphabetical order
+func ImageThemeNames() []string {
+	names := make([]string, 0, len(imageThemes))
+	for name := range imageThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WritePNG draws the table as a png image, with its caption and footer, and writes it to w
+func WritePNG(ctx context.Context, w io.Writer, request *models.RenderRequest) error {
+	m, err := newImageModel(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	_, span := tracing.StartSpan(ctx, "write", tracing.Format.String("png"))
+	img := m.draw()
+	err = png.Encode(w, img)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		log.Error(ctx, "unable to write png", err, log.Data{"file_name": request.Filename})
+		return renderError("png", err)
+	}
+	return nil
+}
+
+// invalidImage returns an error reporting why the table can't be drawn as an image
+func invalidImage(message string, details map[string]interface{}) error {
+	return &models.Error{Code: models.CodeInvalidImage, Message: message, Details: details}
+}
+
+// newImageModel lays out the image of the table: the size of its columns and rows, and the lines of text in each cell
+func newImageModel(ctx context.Context, request *models.RenderRequest) (*imageModel, error) {
+	ctx, span := tracing.StartSpan(ctx, "createImageModel")
+	defer span.End()
+
+	spec := models.ImageSpec{}
+	if request.Image != nil {
+		spec = *request.Image
+	}
+	if spec.Width == 0 {
+		spec.Width = defaultImageWidth
+	}
+	if spec.Scale == 0 {
+		spec.Scale = 1
+	}
+	if spec.Width < minImageWidth || spec.Width > maxImageWidth {
+		return nil, invalidImage(fmt.Sprintf("The width of an image must be between %d and %d", minImageWidth, maxImageWidth),
+			map[string]interface{}{"width": spec.Width})
+	}
+	if spec.Scale < 1 || spec.Scale > maxImageScale {
+		return nil, invalidImage(fmt.Sprintf("The scale of an image must be between 1 and %d", maxImageScale),
+			map[string]interface{}{"scale": spec.Scale})
+	}
+	theme, ok := imageThemes[spec.Theme]
+	if len(spec.Theme) > 0 && !ok {
+		return nil, &models.Error{
+			Code:    models.CodeUnknownTheme,
+			Message: "Unknown image theme",
+			Details: map[string]interface{}{"theme": spec.Theme, "themes": ImageThemeNames()},
+		}
+	}
+	if !ok {
+		if theme, ok = imageThemes[request.Theme]; !ok {
+			theme = imageThemes[DefaultTheme]
+		}
+	}
+
+	m := &imageModel{
+		table:   createModel(ctx, request),
+		theme:   theme,
+		scale:   spec.Scale,
+		width:   int(math.Round(float64(spec.Width) * spec.Scale)),
+		regular: newFace(regularFont, imageFontSize*spec.Scale),
+		bold:    newFace(boldFont, imageFontSize*spec.Scale),
+		title:   newFace(boldFont, imageTitleSize*spec.Scale),
+	}
+	m.lineHeight = m.px(imageFontSize * imageLineHeight)
+	m.titleHeight = m.px(imageTitleSize * imageLineHeight)
+
+	tableWidth := m.width - 2*m.px(imageMargin)
+	m.layoutCaption(request, tableWidth)
+	m.layoutColumns(tableWidth)
+	m.layoutCells()
+	m.layoutFooter(request, tableWidth)
+
+	m.height = 2*m.px(imageMargin) + m.tableHeight()
+	for _, sections := range [][]imageText{m.caption, m.footer} {
+		if len(sections) > 0 {
+			m.height += m.px(imageSectionSpacing)
+		}
+		for _, line := range sections {
+			m.height += line.height
+		}
+	}
+	// compared by division, so that a huge height can't overflow
+	if m.width <= 0 || m.height > maxImagePixels/m.width {
+		return nil, invalidImage("The table is too large to draw as an image",
+			map[string]interface{}{"width": m.width, "height": m.height, "max_pixels": maxImagePixels})
+	}
+	return m, nil
+}
+
+// px converts a size before scaling to pixels
+func (m *imageModel) px(size float64) int {
+	return int(math.Round(size * m.scale))
+}
+
+// layoutCaption wraps the title and subtitle to the width of the table
+func (m *imageModel) layoutCaption(request *models.RenderRequest, width int) {
+	for _, line := range wrapLines(m.title, textLines(request.Title), width) {
+		m.caption = append(m.caption, imageText{text: line, face: m.title, colour: m.theme.text, height: m.titleHeight})
+	}
+	for _, line := range wrapLines(m.regular, textLines(request.Subtitle), width) {
+		m.caption = append(m.caption, imageText{text: line, face: m.regular, colour: m.theme.muted, height: m.lineHeight})
+	}
+}
+
+// layoutFooter wraps the units, source and footnotes to the width of the table
+func (m *imageModel) layoutFooter(request *models.RenderRequest, width int) {
+	add := func(face font.Face, lines []string) {
+		for _, line := range wrapLines(face, lines, width) {
+			m.footer = append(m.footer, imageText{text: line, face: face, colour: m.theme.muted, height: m.lineHeight})
+		}
+	}
+	if units := plainText(request.Units); len(units) > 0 {
+		add(m.regular, []string{"Units: " + units})
+	}
+	if source := plainText(request.Source); len(source) > 0 {
+		add(m.regular, []string{sourceText + source})
+	}
+	if len(request.Footnotes) > 0 {
+		add(m.bold, []string{"Notes"})
+		for i, note := range request.Footnotes {
+			add(m.regular, []string{strconv.Itoa(i+1) + ". " + strings.Join(textLines(note), " ")})
+		}
+	}
+}
+
+// layoutColumns divides the width of the table between the columns. Columns with a width in em, % or px are given that
+// width, as long as they fit, and the rest of the width is shared between the other columns in proportion to the
+// width of their content.
+func (m *imageModel) layoutColumns(width int) {
+	count := len(m.table.columns)
+	m.columnWidths = make([]int, count)
+	if count == 0 {
+		return
+	}
+	natural := make([]int, count)
+	minimum := make([]int, count)
+	padding := 2 * m.px(imageCellPaddingX)
+	for c := range minimum {
+		minimum[c] = minInt(m.px(imageMinColumnWidth), width/count)
+		natural[c] = minimum[c]
+	}
+	m.visibleCells(func(row int, col int, cell *cellModel, value string) {
+		if cell.colspan > 1 {
+			return
+		}
+		face := m.face(row, col)
+		for _, line := range textLines(value) {
+			natural[col] = max(natural[col], measure(face, line)+padding)
+			for _, word := range strings.Fields(line) {
+				minimum[col] = max(minimum[col], minInt(measure(face, word)+padding, width/count))
+			}
+		}
+	})
+
+	fixed := make([]int, count)
+	fixedWidth := 0
+	for c, column := range m.table.columns {
+		if size, ok := parseLength(column.Width, float64(m.px(imageFontSize)), m.scale, width); ok && size > 0 {
+			fixed[c] = size
+			fixedWidth += size
+		}
+	}
+	if fixedWidth >= width {
+		fixed, fixedWidth = make([]int, count), 0 // the widths don't fit, so ignore them all
+	}
+
+	// the columns without a fixed width share what's left in proportion to their content
+	var auto []int
+	sumNatural, sumMinimum := 0, 0
+	for c := range fixed {
+		if fixed[c] > 0 {
+			m.columnWidths[c] = fixed[c]
+			continue
+		}
+		auto = append(auto, c)
+		sumNatural += natural[c]
+		sumMinimum += minimum[c]
+	}
+	if len(auto) == 0 {
+		return
+	}
+	remaining := width - fixedWidth
+	used := 0
+	for _, c := range auto {
+		switch {
+		case sumNatural <= remaining:
+			m.columnWidths[c] = natural[c] * remaining / sumNatural
+		case sumMinimum >= remaining:
+			m.columnWidths[c] = minimum[c] * remaining / sumMinimum
+		default:
+			m.columnWidths[c] = minimum[c] + (natural[c]-minimum[c])*(remaining-sumMinimum)/(sumNatural-sumMinimum)
+		}
+		used += m.columnWidths[c]
+	}
+	m.columnWidths[auto[len(auto)-1]] += remaining - used // rounding
+}
+
+// layoutCells wraps the text of each cell to the width of its columns, and finds the height of each row
+func (m *imageModel) layoutCells() {
+	m.cells = make(map[int]map[int][]string)
+	m.rowHeights = make([]int, len(m.table.request.Data))
+	padding := 2 * m.px(imageCellPaddingY)
+	for r := range m.rowHeights {
+		m.rowHeights[r] = m.lineHeight + padding
+		if size, ok := parseLength(m.table.rowFormat(r).Height, float64(m.px(imageFontSize)), m.scale, 0); ok {
+			m.rowHeights[r] = max(m.rowHeights[r], size)
+		}
+	}
+
+	type merged struct{ row, rowspan, height int }
+	var rowspans []merged
+	m.visibleCells(func(row int, col int, cell *cellModel, value string) {
+		width := m.spanWidth(col, max(cell.colspan, 1)) - 2*m.px(imageCellPaddingX)
+		lines := wrapLines(m.face(row, col), textLines(value), width)
+		if m.cells[row] == nil {
+			m.cells[row] = make(map[int][]string)
+		}
+		m.cells[row][col] = lines
+		height := len(lines)*m.lineHeight + padding
+		if cell.rowspan > 1 {
+			rowspans = append(rowspans, merged{row, cell.rowspan, height})
+		} else {
+			m.rowHeights[row] = max(m.rowHeights[row], height)
+		}
+	})
+	// a merged cell that is taller than the rows it spans makes the last of them taller
+	for _, cell := range rowspans {
+		last := minInt(cell.row+cell.rowspan, len(m.rowHeights)) - 1
+		if extra := cell.height - m.spanHeight(cell.row, last-cell.row+1); extra > 0 {
+			m.rowHeights[last] += extra
+		}
+	}
+}
+
+// visibleCells calls f for each cell that isn't hidden by a merged cell
+func (m *imageModel) visibleCells(f func(row int, col int, cell *cellModel, value string)) {
+	for r, data := range m.table.request.Data {
+		for c, value := range data {
+			cell := m.table.cells[r][c]
+			if cell == nil {
+				cell = emptyCellModel
+			}
+			if !cell.skip {
+				f(r, c, cell, value)
+			}
+		}
+	}
+}
+
+// isHeading returns true if the cell is in a heading row or column
+func (m *imageModel) isHeading(row int, col int) bool {
+	return m.table.rowFormat(row).Heading || (col < len(m.table.columns) && m.table.columns[col].Heading)
+}
+
+// face returns the font of the text of a cell
+func (m *imageModel) face(row int, col int) font.Face {
+	if m.isHeading(row, col) {
+		return m.bold
+	}
+	return m.regular
+}
+
+// spanWidth returns the width of count columns from col
+func (m *imageModel) spanWidth(col int, count int) int {
+	width := 0
+	for c := col; c < col+count && c < len(m.columnWidths); c++ {
+		width += m.columnWidths[c]
+	}
+	return width
+}
+
+// spanHeight returns the height of count rows from row
+func (m *imageModel) spanHeight(row int, count int) int {
+	height := 0
+	for r := row; r < row+count && r < len(m.rowHeights); r++ {
+		height += m.rowHeights[r]
+	}
+	return height
+}
+
+// tableHeight returns the height of all the rows of the table
+func (m *imageModel) tableHeight() int {
+	return m.spanHeight(0, len(m.rowHeights))
+}
+
+// draw draws the image
+func (m *imageModel) draw() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, m.width, m.height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(m.theme.background), image.Point{}, draw.Src)
+
+	left := m.px(imageMargin)
+	y := m.px(imageMargin)
+	for _, line := range m.caption {
+		drawText(img, line.face, line.colour, left, y, line.height, line.text)
+		y += line.height
+	}
+	if len(m.caption) > 0 {
+		y += m.px(imageSectionSpacing)
+	}
+
+	y = m.drawTable(img, left, y)
+
+	if len(m.footer) > 0 {
+		y += m.px(imageSectionSpacing)
+	}
+	for _, line := range m.footer {
+		drawText(img, line.face, line.colour, left, y, line.height, line.text)
+		y += line.height
+	}
+	return img
+}
+
+// drawTable draws the cells of the table with their top left corner at left, top, and returns the bottom of the table
+func (m *imageModel) drawTable(img *image.RGBA, left int, top int) int {
+	rowTops := make([]int, len(m.rowHeights)+1)
+	rowTops[0] = top
+	for r, height := range m.rowHeights {
+		rowTops[r+1] = rowTops[r] + height
+	}
+	columnLefts := make([]int, len(m.columnWidths)+1)
+	columnLefts[0] = left
+	for c, width := range m.columnWidths {
+		columnLefts[c+1] = columnLefts[c] + width
+	}
+
+	border := max(1, m.px(1))
+	m.visibleCells(func(row int, col int, cell *cellModel, value string) {
+		lastRow := minInt(row+max(cell.rowspan, 1), len(m.rowHeights))
+		lastCol := minInt(col+max(cell.colspan, 1), len(m.columnWidths))
+		bounds := image.Rect(columnLefts[col], rowTops[row], columnLefts[lastCol], rowTops[lastRow])
+
+		colour := m.theme.text
+		if m.isHeading(row, col) {
+			colour = m.theme.headingText
+			draw.Draw(img, bounds, image.NewUniform(m.theme.headingBackground), image.Point{}, draw.Src)
+		}
+
+		// the border below the cell, which is thicker below the heading rows
+		borderColour, borderWidth := m.theme.border, border
+		if lastRow == m.table.headRows {
+			borderColour, borderWidth = m.theme.headingBorder, 2*border
+		}
+		draw.Draw(img, image.Rect(bounds.Min.X, bounds.Max.Y-borderWidth, bounds.Max.X, bounds.Max.Y),
+			image.NewUniform(borderColour), image.Point{}, draw.Src)
+
+		lines := m.cells[row][col]
+		face := m.face(row, col)
+		padX, padY := m.px(imageCellPaddingX), m.px(imageCellPaddingY)
+		textHeight := len(lines) * m.lineHeight
+		y := bounds.Min.Y + padY
+		switch m.verticalAlign(row, cell) {
+		case models.AlignMiddle:
+			y = bounds.Min.Y + (bounds.Dy()-textHeight)/2
+		case models.AlignBottom:
+			y = bounds.Max.Y - padY - textHeight
+		}
+		align := m.align(col, cell)
+		for _, line := range lines {
+			x := bounds.Min.X + padX
+			switch align {
+			case models.AlignRight:
+				x = bounds.Max.X - padX - measure(face, line)
+			case models.AlignCenter:
+				x = bounds.Min.X + (bounds.Dx()-measure(face, line))/2
+			}
+			drawText(img, face, colour, x, y, m.lineHeight, line)
+			y += m.lineHeight
+		}
+	})
+	return rowTops[len(rowTops)-1]
+}
+
+// align returns the horizontal alignment of a cell: that of the cell if it has one, else that of its column
+func (m *imageModel) align(col int, cell *cellModel) string {
+	if len(cell.align) > 0 {
+		return cell.align
+	}
+	return m.table.columns[col].Align
+}
+
+// verticalAlign returns the vertical alignment of a cell: that of the cell if it has one, else that of its row. The
+// default is the middle, as in html.
+func (m *imageModel) verticalAlign(row int, cell *cellModel) string {
+	if len(cell.valign) > 0 {
+		return cell.valign
+	}
+	if align := m.table.rowFormat(row).VerticalAlign; len(align) > 0 {
+		return align
+	}
+	return models.AlignMiddle
+}
+
+// textLines returns the lines of text of an html value, without any markup. A line ends at each \n or <br>.
+func textLines(value string) []string {
+	if len(value) == 0 {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(h.InlineText(value), "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	for len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// wrapLines breaks each line into as many lines as it needs to fit the width. A word that is too long to fit on a line
+// of its own is broken between characters.
+func wrapLines(face font.Face, lines []string, width int) []string {
+	var wrapped []string
+	for _, line := range lines {
+		current := ""
+		for _, word := range strings.Fields(line) {
+			candidate := word
+			if len(current) > 0 {
+				candidate = current + " " + word
+			}
+			if measure(face, candidate) <= width {
+				current = candidate
+				continue
+			}
+			if len(current) > 0 {
+				wrapped = append(wrapped, current)
+			}
+			current = word
+			for measure(face, current) > width {
+				n := fitRunes(face, current, width)
+				wrapped = append(wrapped, current[:n])
+				current = current[n:]
+			}
+		}
+		wrapped = append(wrapped, current)
+	}
+	return wrapped
+}
+
+// fitRunes returns the length in bytes of the longest prefix of the text that fits the width, which is at least one
+// character
+func fitRunes(face font.Face, text string, width int) int {
+	fit := 0
+	for i := range text {
+		if i > 0 && measure(face, text[:i]) > width {
+			break
+		}
+		fit = i
+	}
+	if fit == 0 {
+		for i := range text {
+			if i > 0 {
+				return i
+			}
+		}
+		return len(text)
+	}
+	return fit
+}
+
+// parseLength converts a width or height in em, % (of total) or px (before scaling) to pixels. A length is limited to
+// maxImagePixels, as no image could contain a longer one.
+func parseLength(value string, em float64, scale float64, total int) (int, bool) {
+	value = strings.TrimSpace(value)
+	var unit float64
+	switch {
+	case strings.HasSuffix(value, "em"):
+		value, unit = strings.TrimSuffix(value, "em"), em
+	case strings.HasSuffix(value, "%") && total > 0:
+		value, unit = strings.TrimSuffix(value, "%"), float64(total)/100
+	case strings.HasSuffix(value, "px"):
+		value, unit = strings.TrimSuffix(value, "px"), scale
+	default:
+		return 0, false
+	}
+	size, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || size < 0 {
+		return 0, false
+	}
+	return int(math.Min(math.Round(size*unit), maxImagePixels)), true
+}
+
+// measure returns the width of the text in pixels
+func measure(face font.Face, text string) int {
+	return font.MeasureString(face, text).Ceil()
+}
+
+// drawText draws a line of text, centred vertically in a line of the given height with its top at y
+func drawText(img draw.Image, face font.Face, colour color.RGBA, x int, y int, height int, text string) {
+	metrics := face.Metrics()
+	ascent, descent := metrics.Ascent.Ceil(), metrics.Descent.Ceil()
+	baseline := y + (height-ascent-descent)/2 + ascent
+	d := font.Drawer{Dst: img, Src: image.NewUniform(colour), Face: face, Dot: fixed.P(x, baseline)}
+	d.DrawString(text)
+}
+
+// newFace returns a face of the font at the given size in pixels
+func newFace(f *opentype.Font, size float64) font.Face {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic(err) // only possible if the options are invalid
+	}
+	return face
+}
+
+// mustParseFont parses a font that is embedded in the binary
+func mustParseFont(ttf []byte) *opentype.Font {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// rgb returns the colour with the given hex value, e.g. 0x206095
+func rgb(hex uint32) color.RGBA {
+	return color.RGBA{R: uint8(hex >> 16), G: uint8(hex >> 8), B: uint8(hex), A: 0xff}
+}
+
+// minInt returns the smaller of a and b
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
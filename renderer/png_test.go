@@ -0,0 +1,127 @@
+package renderer_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func imageRequest(spec *models.ImageSpec) *models.RenderRequest {
+	return &models.RenderRequest{
+		Filename:      "myId",
+		Title:         "Prices <b>by</b> year",
+		Subtitle:      "UK",
+		Source:        "ONS",
+		Image:         spec,
+		RowFormats:    []models.RowFormat{{Row: 0, Heading: true}},
+		ColumnFormats: []models.ColumnFormat{{Column: 0, Heading: true}},
+		CellFormats:   []models.CellFormat{{Row: 0, Column: 1, Colspan: 2}},
+		Data:          [][]string{{"Year", "Index [1]", ""}, {"2016", "101.8", "1.5"}, {"2017", "103.2", "2.6"}},
+		Footnotes:     []string{"2015 = 100"},
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+
+	Convey("A table is drawn as a png of the default width, in the ons colours", t, func() {
+		img, err := invokeWritePNG(imageRequest(nil))
+		So(err, ShouldBeNil)
+		So(img.Bounds().Dx(), ShouldEqual, 1200)
+		So(img.Bounds().Dy(), ShouldBeBetween, 200, 400)
+		So(color.RGBAModel.Convert(img.At(0, 0)), ShouldResemble, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+	})
+
+	Convey("The width, scale and colour theme can be chosen", t, func() {
+		small, err := invokeWritePNG(imageRequest(&models.ImageSpec{Width: 400}))
+		So(err, ShouldBeNil)
+		So(small.Bounds().Dx(), ShouldEqual, 400)
+
+		scaled, err := invokeWritePNG(imageRequest(&models.ImageSpec{Width: 400, Scale: 2, Theme: "dark"}))
+		So(err, ShouldBeNil)
+		So(scaled.Bounds().Dx(), ShouldEqual, 800)
+		So(scaled.Bounds().Dy(), ShouldBeBetween, 2*small.Bounds().Dy()-8, 2*small.Bounds().Dy()+8)
+		So(color.RGBAModel.Convert(scaled.At(0, 0)), ShouldResemble, color.RGBA{R: 0x22, G: 0x22, B: 0x22, A: 0xff})
+	})
+
+	Convey("The theme of the request chooses the colours if it is also an image theme", t, func() {
+		request := imageRequest(nil)
+		request.Theme = "dark"
+		img, err := invokeWritePNG(request)
+		So(err, ShouldBeNil)
+		So(color.RGBAModel.Convert(img.At(0, 0)), ShouldResemble, color.RGBA{R: 0x22, G: 0x22, B: 0x22, A: 0xff})
+	})
+
+	Convey("A long value wraps onto more lines, making the image taller", t, func() {
+		short, err := invokeWritePNG(imageRequest(&models.ImageSpec{Width: 300}))
+		So(err, ShouldBeNil)
+		request := imageRequest(&models.ImageSpec{Width: 300})
+		request.Data[1][1] = "a value that is much too long to fit on a single line of such a narrow image"
+		long, err := invokeWritePNG(request)
+		So(err, ShouldBeNil)
+		So(long.Bounds().Dy(), ShouldBeGreaterThan, short.Bounds().Dy())
+	})
+
+	Convey("An image that can't be drawn is an error", t, func() {
+		for _, spec := range []*models.ImageSpec{{Width: 100}, {Width: 5000}, {Scale: 0.5}, {Scale: 5}} {
+			_, err := invokeWritePNG(imageRequest(spec))
+			So(errorCode(err), ShouldEqual, models.CodeInvalidImage)
+		}
+		_, err := invokeWritePNG(imageRequest(&models.ImageSpec{Theme: "neon"}))
+		So(errorCode(err), ShouldEqual, models.CodeUnknownTheme)
+
+		request := imageRequest(&models.ImageSpec{Width: 4000, Scale: 4})
+		for i := 0; i < 2000; i++ {
+			request.Data = append(request.Data, []string{"2018", "1", "2"})
+		}
+		_, err = invokeWritePNG(request)
+		So(errorCode(err), ShouldEqual, models.CodeInvalidImage)
+
+		request = imageRequest(&models.ImageSpec{Width: 300})
+		request.RowFormats = []models.RowFormat{{Row: 0, Height: "2305843009213693952px"}}
+		_, err = invokeWritePNG(request)
+		So(errorCode(err), ShouldEqual, models.CodeInvalidImage)
+	})
+}
+
+func TestAltText(t *testing.T) {
+
+	Convey("The alt text summarises the title, size, headings and source of the table", t, func() {
+		request := imageRequest(nil)
+		So(renderer.AltText(mockContext, request), ShouldEqual,
+			"Table: Prices by year. UK. 2 rows and 3 columns. Columns: Year, Index. Source: ONS.")
+	})
+
+	Convey("Only the first headings are listed", t, func() {
+		request := &models.RenderRequest{
+			RowFormats: []models.RowFormat{{Row: 0, Heading: true}},
+			Data:       [][]string{{"a", "b", "c", "d", "e", "f", "g"}, {"1", "2", "3", "4", "5", "6", "7"}},
+			Units:      "£",
+		}
+		So(renderer.AltText(mockContext, request), ShouldEqual,
+			"Table. 1 row and 7 columns. Columns: a, b, c, d, e, and 2 more. Units: £.")
+	})
+}
+
+func invokeWritePNG(request *models.RenderRequest) (image.Image, error) {
+	var buf bytes.Buffer
+	if err := renderer.WritePNG(mockContext, &buf, request); err != nil {
+		return nil, err
+	}
+	return png.Decode(&buf)
+}
+
+// errorCode returns the code of a models.Error, or an empty string for any other error
+func errorCode(err error) string {
+	var modelErr *models.Error
+	if errors.As(err, &modelErr) {
+		return modelErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,442 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// the layout of a chart, in pixels
+const (
+	chartMargin       = 16
+	chartFontSize     = 12
+	chartTitleSize    = 16
+	chartLineHeight   = 18
+	chartCharWidth    = 6.5 // the approximate width of a character, used to lay out text
+	chartTickCount    = 6   // the approximate number of ticks on the value axis
+	chartSwatchSize   = 12
+	chartBarPadding   = 0.1 // the proportion of each category left empty on either side of its bars
+	chartPointRadius  = 3
+	chartStrokeWidth  = 2
+	chartGridColour   = "#d9d9d9"
+	chartAxisColour   = "#707070"
+	chartTextColour   = "#222222"
+	chartFontFamilies = "Arial, Helvetica, sans-serif"
+)
+
+// chartColours are the colours of the series, in order, from the ONS data visualisation palette
+var chartColours = []string{"#206095", "#27a0cc", "#003c57", "#118c7b", "#a8bd3a", "#871a5b", "#f66068", "#746cb1", "#22d0b6"}
+
+// chartTypeNames are the names of the chart types used in the description of a chart
+var chartTypeNames = map[string]string{
+	models.ChartLine:       "Line chart",
+	models.ChartBar:        "Bar chart",
+	models.ChartStackedBar: "Stacked bar chart",
+}
+
+// svgWriter accumulates the markup of an svg image
+type svgWriter struct {
+	bytes.Buffer
+}
+
+// chartLayout holds the positions of the parts of a chart
+type chartLayout struct {
+	plotLeft, plotRight, plotTop, plotBottom float64
+	low, high, step                          float64 // the range of the value axis, and the interval between its ticks
+	legendTop                                float64
+	legendRows                               [][]int // the series in each row of the legend
+}
+
+// WriteSVGChart writes an svg line, bar or stacked bar chart of the table to w. The output is the same for the same
+// request, so that it can be compared with an expected image.
+func WriteSVGChart(ctx context.Context, w io.Writer, request *models.RenderRequest) error {
+	chart, err := newChartModel(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	_, span := tracing.StartSpan(ctx, "write", tracing.Format.String("svg-chart"))
+	var svg svgWriter
+	chart.write(&svg, request.Filename)
+	_, err = svg.WriteTo(w)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		log.Error(ctx, "unable to write svg chart", err, log.Data{"file_name": request.Filename})
+		return renderError("svg-chart", err)
+	}
+	return nil
+}
+
+// write draws the chart. The id is used as a prefix for the ids of the title and description.
+func (c *chartModel) write(svg *svgWriter, id string) {
+	layout := c.layout()
+	titleID, descID := "chart-"+id+"-title", "chart-"+id+"-desc"
+
+	svg.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-labelledby="%s %s">`+"\n",
+		c.width, c.height, c.width, c.height, escapeXML(titleID), escapeXML(descID))
+	svg.printf(`<title id="%s">%s</title>`+"\n", escapeXML(titleID), escapeXML(c.titleText()))
+	svg.printf(`<desc id="%s">%s</desc>`+"\n", escapeXML(descID), escapeXML(c.description()))
+	svg.printf(`<rect width="100%%" height="100%%" fill="#ffffff"/>` + "\n")
+	svg.printf(`<g font-family="%s" font-size="%d" fill="%s">`+"\n", chartFontFamilies, chartFontSize, chartTextColour)
+
+	y := float64(chartMargin)
+	if len(c.title) > 0 {
+		y += chartTitleSize
+		svg.text(chartMargin, y, "start", fmt.Sprintf(` font-size="%d" font-weight="bold"`, chartTitleSize), c.title)
+		y += chartLineHeight - chartTitleSize
+	}
+	if len(c.subtitle) > 0 {
+		y += chartFontSize
+		svg.text(chartMargin, y, "start", "", c.subtitle)
+	}
+
+	c.writeValueAxis(svg, layout)
+	c.writeCategoryAxis(svg, layout)
+	if c.kind == models.ChartLine {
+		c.writeLines(svg, layout)
+	} else {
+		c.writeBars(svg, layout)
+	}
+	c.writeLegend(svg, layout)
+	if len(c.source) > 0 {
+		svg.text(chartMargin, float64(c.height-chartMargin), "start", "", sourceText+c.source)
+	}
+	svg.printf("</g>\n</svg>\n")
+}
+
+// layout works out where each part of the chart is drawn
+func (c *chartModel) layout() *chartLayout {
+	l := &chartLayout{}
+	l.low, l.high, l.step = niceScale(c.valueRange())
+
+	top := float64(chartMargin)
+	if len(c.title) > 0 {
+		top += chartLineHeight
+	}
+	if len(c.subtitle) > 0 {
+		top += chartLineHeight
+	}
+	l.plotTop = top + chartFontSize
+
+	// the legend wraps onto as many rows as it needs, above the source
+	l.plotLeft = chartMargin + float64(c.maxTickLabelLength(l))*chartCharWidth + 8
+	if len(c.units) > 0 {
+		l.plotLeft += chartLineHeight
+	}
+	l.plotRight = float64(c.width - chartMargin)
+	var row []int
+	x := 0.0
+	for i, series := range c.series {
+		width := legendItemWidth(series.name)
+		if len(row) > 0 && x+width > l.plotRight-chartMargin {
+			l.legendRows = append(l.legendRows, row)
+			row, x = nil, 0
+		}
+		row = append(row, i)
+		x += width
+	}
+	l.legendRows = append(l.legendRows, row)
+
+	bottom := float64(c.height - chartMargin)
+	if len(c.source) > 0 {
+		bottom -= chartLineHeight
+	}
+	l.legendTop = bottom - float64(len(l.legendRows)*chartLineHeight)
+	l.plotBottom = l.legendTop - chartLineHeight - 4 // the category labels
+	if len(c.categoryLabel) > 0 {
+		l.plotBottom -= chartLineHeight + 6
+	}
+	return l
+}
+
+// legendItemWidth is the space taken by the swatch and name of a series in the legend
+func legendItemWidth(name string) float64 {
+	return chartSwatchSize + 6 + float64(len([]rune(name)))*chartCharWidth + chartMargin
+}
+
+// maxTickLabelLength returns the length of the longest label of the value axis
+func (c *chartModel) maxTickLabelLength(l *chartLayout) int {
+	longest := 0
+	for _, v := range l.ticks() {
+		longest = max(longest, len(formatTick(v, l.step)))
+	}
+	return longest
+}
+
+// ticks returns the values of the ticks of the value axis
+func (l *chartLayout) ticks() []float64 {
+	var ticks []float64
+	for i := 0; ; i++ {
+		v := l.low + float64(i)*l.step
+		if v > l.high+l.step/2 {
+			return ticks
+		}
+		ticks = append(ticks, v)
+	}
+}
+
+// y returns the position of a value on the value axis
+func (l *chartLayout) y(value float64) float64 {
+	return l.plotBottom - (value-l.low)/(l.high-l.low)*(l.plotBottom-l.plotTop)
+}
+
+// band returns the left edge and width of the space for a category
+func (l *chartLayout) band(category int, categories int) (float64, float64) {
+	width := (l.plotRight - l.plotLeft) / float64(categories)
+	return l.plotLeft + float64(category)*width, width
+}
+
+// writeValueAxis draws the gridlines and labels of the value axis, and the units as the title of the axis
+func (c *chartModel) writeValueAxis(svg *svgWriter, l *chartLayout) {
+	svg.printf(`<g class="chart__value-axis">` + "\n")
+	for _, v := range l.ticks() {
+		y := l.y(v)
+		colour := chartGridColour
+		if math.Abs(v) < l.step/2 {
+			colour = chartAxisColour
+		}
+		svg.printf(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s"/>`+"\n", num(l.plotLeft), num(y), num(l.plotRight), num(y), colour)
+		svg.text(l.plotLeft-6, y+chartFontSize/3, "end", "", formatTick(v, l.step))
+	}
+	if len(c.units) > 0 {
+		x, y := float64(chartMargin+chartFontSize), (l.plotTop+l.plotBottom)/2
+		svg.text(x, y, "middle", fmt.Sprintf(` transform="rotate(-90 %s %s)"`, num(x), num(y)), c.units)
+	}
+	svg.printf("</g>\n")
+}
+
+// writeCategoryAxis draws the labels of the categories, leaving out labels where there isn't room for them all
+func (c *chartModel) writeCategoryAxis(svg *svgWriter, l *chartLayout) {
+	svg.printf(`<g class="chart__category-axis">` + "\n")
+	longest := 1
+	for _, category := range c.categories {
+		longest = max(longest, len([]rune(category)))
+	}
+	_, width := l.band(0, len(c.categories))
+	every := int(math.Ceil((float64(longest)*chartCharWidth + 8) / width))
+	for i, category := range c.categories {
+		if i%every != 0 {
+			continue
+		}
+		x, width := l.band(i, len(c.categories))
+		svg.text(x+width/2, l.plotBottom+chartLineHeight, "middle", "", category)
+	}
+	if len(c.categoryLabel) > 0 {
+		svg.text((l.plotLeft+l.plotRight)/2, l.plotBottom+2*chartLineHeight+4, "middle", ` font-weight="bold"`, c.categoryLabel)
+	}
+	svg.printf("</g>\n")
+}
+
+// writeBars draws a bar for each value, side by side or stacked. Missing values are left out.
+func (c *chartModel) writeBars(svg *svgWriter, l *chartLayout) {
+	svg.printf(`<g class="chart__bars">` + "\n")
+	for i, category := range c.categories {
+		x, width := l.band(i, len(c.categories))
+		x += width * chartBarPadding
+		width -= 2 * width * chartBarPadding
+		var negative, positive float64
+		for s, series := range c.series {
+			v := series.values[i]
+			if math.IsNaN(v) {
+				continue
+			}
+			from, to := 0.0, v
+			barX, barWidth := x, width
+			if c.kind == models.ChartStackedBar {
+				if v < 0 {
+					from, to = negative, negative+v
+					negative = to
+				} else {
+					from, to = positive, positive+v
+					positive = to
+				}
+			} else {
+				barWidth = width / float64(len(c.series))
+				barX = x + float64(s)*barWidth
+			}
+			top, bottom := l.y(math.Max(from, to)), l.y(math.Min(from, to))
+			svg.printf(`<rect x="%s" y="%s" width="%s" height="%s" fill="%s"><title>%s</title></rect>`+"\n",
+				num(barX), num(top), num(barWidth), num(bottom-top), colour(s), escapeXML(c.valueTitle(series.name, category, v)))
+		}
+	}
+	svg.printf("</g>\n")
+}
+
+// writeLines draws a line for each series, with a point at each value. Missing values are gaps in the line.
+func (c *chartModel) writeLines(svg *svgWriter, l *chartLayout) {
+	svg.printf(`<g class="chart__lines">` + "\n")
+	for s, series := range c.series {
+		var path strings.Builder
+		move := true
+		for i, v := range series.values {
+			if math.IsNaN(v) {
+				move = true
+				continue
+			}
+			x, width := l.band(i, len(c.categories))
+			command := "L"
+			if move {
+				command = "M"
+			}
+			if path.Len() > 0 {
+				path.WriteString(" ")
+			}
+			fmt.Fprintf(&path, "%s%s %s", command, num(x+width/2), num(l.y(v)))
+			move = false
+		}
+		svg.printf(`<path d="%s" fill="none" stroke="%s" stroke-width="%d"/>`+"\n", path.String(), colour(s), chartStrokeWidth)
+		for i, v := range series.values {
+			if math.IsNaN(v) {
+				continue
+			}
+			x, width := l.band(i, len(c.categories))
+			svg.printf(`<circle cx="%s" cy="%s" r="%d" fill="%s"><title>%s</title></circle>`+"\n",
+				num(x+width/2), num(l.y(v)), chartPointRadius, colour(s), escapeXML(c.valueTitle(series.name, c.categories[i], v)))
+		}
+	}
+	svg.printf("</g>\n")
+}
+
+// writeLegend draws the colour and name of each series
+func (c *chartModel) writeLegend(svg *svgWriter, l *chartLayout) {
+	svg.printf(`<g class="chart__legend">` + "\n")
+	for r, row := range l.legendRows {
+		x, y := float64(chartMargin), l.legendTop+float64(r*chartLineHeight)
+		for _, s := range row {
+			svg.printf(`<rect x="%s" y="%s" width="%d" height="%d" fill="%s"/>`+"\n", num(x), num(y+2), chartSwatchSize, chartSwatchSize, colour(s))
+			svg.text(x+chartSwatchSize+6, y+chartFontSize, "start", "", c.series[s].name)
+			x += legendItemWidth(c.series[s].name)
+		}
+	}
+	svg.printf("</g>\n")
+}
+
+// titleText returns the title of the chart, for its title element
+func (c *chartModel) titleText() string {
+	if len(c.title) > 0 {
+		return c.title
+	}
+	return chartTypeNames[c.kind]
+}
+
+// description summarises the chart for its desc element, e.g. "Bar chart of Sales and Costs for 12 categories, from Jan to Dec."
+func (c *chartModel) description() string {
+	var b strings.Builder
+	if len(c.subtitle) > 0 {
+		b.WriteString(c.subtitle)
+		b.WriteString(". ")
+	}
+	names := make([]string, len(c.series))
+	for i, series := range c.series {
+		names[i] = series.name
+	}
+	fmt.Fprintf(&b, "%s of %s for %d categories", chartTypeNames[c.kind], joinNames(names), len(c.categories))
+	if len(c.categories) > 1 && len(c.categories[0]) > 0 {
+		fmt.Fprintf(&b, ", from %s to %s", c.categories[0], c.categories[len(c.categories)-1])
+	}
+	if len(c.units) > 0 {
+		fmt.Fprintf(&b, ", in %s", c.units)
+	}
+	b.WriteString(".")
+	return b.String()
+}
+
+// valueTitle describes a single value of the chart, e.g. "Sales, Jan: 12.5"
+func (c *chartModel) valueTitle(series string, category string, value float64) string {
+	if len(category) == 0 {
+		return fmt.Sprintf("%s: %s", series, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+	return fmt.Sprintf("%s, %s: %s", series, category, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// joinNames joins the names into a list, e.g. "a, b and c"
+func joinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// niceScale returns the range and interval of ticks of an axis that includes low and high, with round numbers for the
+// ticks
+func niceScale(low float64, high float64) (float64, float64, float64) {
+	if low == high {
+		low, high = low-1, high+1
+	}
+	step := niceNumber((high - low) / (chartTickCount - 1))
+	return math.Floor(low/step) * step, math.Ceil(high/step) * step, step
+}
+
+// niceNumber returns a round number (1, 2 or 5 times a power of 10) close to x
+func niceNumber(x float64) float64 {
+	exponent := math.Floor(math.Log10(x))
+	fraction := x / math.Pow(10, exponent)
+	switch {
+	case fraction < 1.5:
+		fraction = 1
+	case fraction < 3:
+		fraction = 2
+	case fraction < 7:
+		fraction = 5
+	default:
+		fraction = 10
+	}
+	return fraction * math.Pow(10, exponent)
+}
+
+// formatTick formats a value of the axis with as many decimal places as the interval between ticks needs, and
+// thousands separators
+func formatTick(value float64, step float64) string {
+	if math.Abs(value) < step/2 {
+		value = 0 // rather than a rounding error such as -0.0000001
+	}
+	decimals := max(0, int(-math.Floor(math.Log10(step))))
+	text := strconv.FormatFloat(value, 'f', decimals, 64)
+	sign, integer, fraction := "", text, ""
+	if strings.HasPrefix(integer, "-") {
+		sign, integer = "-", integer[1:]
+	}
+	if i := strings.IndexByte(integer, '.'); i >= 0 {
+		integer, fraction = integer[:i], integer[i:]
+	}
+	for i := len(integer) - 3; i > 0; i -= 3 {
+		integer = integer[:i] + "," + integer[i:]
+	}
+	return sign + integer + fraction
+}
+
+// colour returns the colour of the nth series
+func colour(n int) string {
+	return chartColours[n%len(chartColours)]
+}
+
+// num formats a coordinate with at most 2 decimal places
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100+0, 'f', -1, 64) // +0 so that -0 is formatted as 0
+}
+
+// escapeXML escapes the text for use in xml content or attributes
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// printf appends formatted markup
+func (svg *svgWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(svg, format, args...)
+}
+
+// text appends a text element, with the given anchor (start, middle or end) and any additional attributes
+func (svg *svgWriter) text(x float64, y float64, anchor string, attributes string, content string) {
+	svg.printf(`<text x="%s" y="%s" text-anchor="%s"%s>%s</text>`+"\n", num(x), num(y), anchor, attributes, escapeXML(content))
+}
@@ -0,0 +1,38 @@
+package renderer
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNiceScale(t *testing.T) {
+
+	Convey("The axis is extended to round numbers", t, func() {
+		low, high, step := niceScale(-35, 1020)
+		So(step, ShouldEqual, 200)
+		So(low, ShouldEqual, -200)
+		So(high, ShouldEqual, 1200)
+
+		low, high, step = niceScale(0.12, 0.9)
+		So(step, ShouldEqual, 0.2)
+		So(low, ShouldEqual, 0)
+		So(high, ShouldAlmostEqual, 1)
+	})
+
+	Convey("A single value has an axis around it", t, func() {
+		low, high, _ := niceScale(5, 5)
+		So(low, ShouldBeLessThan, 5)
+		So(high, ShouldBeGreaterThan, 5)
+	})
+}
+
+func TestFormatTick(t *testing.T) {
+
+	Convey("Ticks have thousands separators and the decimal places of the interval", t, func() {
+		So(formatTick(1200, 200), ShouldEqual, "1,200")
+		So(formatTick(-1234567, 500000), ShouldEqual, "-1,234,567")
+		So(formatTick(0.4, 0.2), ShouldEqual, "0.4")
+		So(formatTick(-0.0000001, 0.2), ShouldEqual, "0.0")
+	})
+}
@@ -0,0 +1,200 @@
+package renderer
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+)
+
+// DefaultTheme is the theme used when a request doesn't specify one
+const DefaultTheme = "ons"
+
+// themeExtension is the extension of the files that define themes
+const themeExtension = ".tmpl"
+
+//go:embed themes/*.tmpl
+var builtInThemeFiles embed.FS
+
+// Theme is a named set of templates that control the markup of an html table: the class names, the elements that wrap
+// the table and the layout of the footer. The templates are executed with a tableView, starting with "figure".
+// Every theme is based on the default theme, so a theme need only define the templates it changes.
+type Theme struct {
+	Name     string
+	source   string // the content of the theme's file
+	template *template.Template
+}
+
+var (
+	// themeBase is the default theme's templates, which every theme is a copy of. It is never executed, as an html
+	// template can't be copied once it has been.
+	themeBase   = template.Must(template.New(DefaultTheme).Funcs(themeFuncs).ParseFS(builtInThemeFiles, "themes/"+DefaultTheme+themeExtension))
+	themesMutex sync.RWMutex
+	themes      = mustLoadBuiltInThemes()
+	themesHash  = hashThemes(themes)
+)
+
+// themeFuncs are the functions available to the templates of a theme
+var themeFuncs = template.FuncMap{
+	// classes joins the non-empty class names with spaces
+	"classes": func(names ...string) string {
+		nonEmpty := names[:0:0]
+		for _, name := range names {
+			if len(name) > 0 {
+				nonEmpty = append(nonEmpty, name)
+			}
+		}
+		return strings.Join(nonEmpty, " ")
+	},
+	// when returns value if condition is true, otherwise an empty string
+	"when": func(condition bool, value string) string {
+		if condition {
+			return value
+		}
+		return ""
+	},
+	// prefix returns the value with the prefix, or an empty string if the value is empty, e.g. prefix "align-" .Align
+	"prefix": func(prefix string, value string) string {
+		if len(value) == 0 {
+			return ""
+		}
+		return prefix + value
+	},
+	// pick returns the value paired with key in a list of key value pairs, or an empty string if there isn't one
+	"pick": func(key string, pairs ...string) string {
+		for i := 0; i+1 < len(pairs); i += 2 {
+			if pairs[i] == key {
+				return pairs[i+1]
+			}
+		}
+		return ""
+	},
+}
+
+// mustLoadBuiltInThemes parses the themes embedded in the binary
+func mustLoadBuiltInThemes() map[string]*Theme {
+	loaded := make(map[string]*Theme)
+	names, err := builtInThemeFiles.ReadDir("themes")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range names {
+		name := strings.TrimSuffix(entry.Name(), themeExtension)
+		content, err := builtInThemeFiles.ReadFile("themes/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		theme, err := newTheme(name, string(content))
+		if err != nil {
+			panic(err)
+		}
+		loaded[name] = theme
+	}
+	return loaded
+}
+
+// newTheme creates a theme by parsing the content over a copy of the default theme
+func newTheme(name string, content string) (*Theme, error) {
+	t, err := themeBase.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = t.Parse(content); err != nil {
+		return nil, err
+	}
+	return &Theme{Name: name, source: content, template: t}, nil
+}
+
+// hashThemes returns a hash of the names and content of the themes
+func hashThemes(themes map[string]*Theme) string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	for _, name := range names {
+		hash.Write([]byte(name + "\x00" + themes[name].source + "\x00"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// LoadThemes adds a theme for each .tmpl file in dir, named after the file, e.g. dir/intranet.tmpl is the theme "intranet".
+// A theme may not replace one of the built-in themes.
+func LoadThemes(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+themeExtension))
+	if err != nil {
+		return err
+	}
+
+	themesMutex.Lock()
+	defer themesMutex.Unlock()
+	defer func() { themesHash = hashThemes(themes) }()
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), themeExtension)
+		if existing, ok := themes[name]; ok && existing.builtIn() {
+			return fmt.Errorf("theme %s in %s has the same name as a built-in theme", name, dir)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		theme, err := newTheme(name, string(content))
+		if err != nil {
+			return fmt.Errorf("invalid theme %s: %w", name, err)
+		}
+		themes[name] = theme
+	}
+	return nil
+}
+
+// builtIn returns true if the theme is embedded in the binary
+func (t *Theme) builtIn() bool {
+	_, err := builtInThemeFiles.Open("themes/" + t.Name + themeExtension)
+	return err == nil
+}
+
+// FindTheme returns the theme with the given name, or the default theme if name is empty
+func FindTheme(name string) (*Theme, error) {
+	if len(name) == 0 {
+		name = DefaultTheme
+	}
+	themesMutex.RLock()
+	defer themesMutex.RUnlock()
+	if theme, ok := themes[name]; ok {
+		return theme, nil
+	}
+	return nil, &models.Error{
+		Code:    models.CodeUnknownTheme,
+		Message: "Unknown theme",
+		Details: map[string]interface{}{"theme": name},
+	}
+}
+
+// ThemesHash returns a hash of every available theme, which changes whenever a theme is loaded or changed
+func ThemesHash() string {
+	themesMutex.RLock()
+	defer themesMutex.RUnlock()
+	return themesHash
+}
+
+// ThemeNames returns the names of all the available themes, in alphabetical order
+func ThemeNames() []string {
+	themesMutex.RLock()
+	defer themesMutex.RUnlock()
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
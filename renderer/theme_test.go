@@ -0,0 +1,76 @@
+package renderer_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/renderer"
+	"github.com/ONSdigital/dp-table-renderer/testdata"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestThemes(t *testing.T) {
+
+	Convey("Each built-in theme renders the example table", t, func() {
+		for _, name := range []string{"bare", "govuk", "ons"} {
+			request, err := models.CreateRenderRequest(mockContext, bytes.NewReader(testdata.LoadExampleRequest(t)))
+			So(err, ShouldBeNil)
+			request.Theme = name
+
+			response, err := renderer.RenderHTML(mockContext, request)
+			So(err, ShouldBeNil)
+			So(string(response), ShouldContainSubstring, `id="table-`+request.Filename+`"`)
+			So(string(response), ShouldContainSubstring, "<caption")
+			So(string(response), ShouldContainSubstring, request.Footnotes[0])
+		}
+	})
+
+	Convey("The default theme is used when a request doesn't specify one", t, func() {
+		theme, err := renderer.FindTheme("")
+		So(err, ShouldBeNil)
+		So(theme.Name, ShouldEqual, renderer.DefaultTheme)
+	})
+
+	Convey("An unknown theme is an error", t, func() {
+		request := &models.RenderRequest{Filename: "myId", Data: [][]string{{"a"}}, Theme: "unknown"}
+		_, err := renderer.RenderHTML(mockContext, request)
+		So(err, ShouldNotBeNil)
+
+		var modelError *models.Error
+		So(errors.As(err, &modelError), ShouldBeTrue)
+		So(modelError.Code, ShouldEqual, models.CodeUnknownTheme)
+		So(modelError.Details["theme"], ShouldEqual, "unknown")
+	})
+
+	Convey("A theme loaded from a directory replaces the templates it defines", t, func() {
+		dir := t.TempDir()
+		content := `{{define "footer"}}<p class="custom-source">{{.Source}}</p>{{end}}`
+		So(os.WriteFile(filepath.Join(dir, "custom.tmpl"), []byte(content), 0o600), ShouldBeNil)
+		hash := renderer.ThemesHash()
+		So(renderer.LoadThemes(dir), ShouldBeNil)
+		So(renderer.ThemeNames(), ShouldContain, "custom")
+		So(renderer.ThemesHash(), ShouldNotEqual, hash)
+
+		request := &models.RenderRequest{Filename: "myId", Source: "my source", Data: [][]string{{"a"}}, Theme: "custom"}
+		response, err := renderer.RenderHTML(mockContext, request)
+		So(err, ShouldBeNil)
+		So(string(response), ShouldContainSubstring, `<table class="table">`)
+		So(string(response), ShouldContainSubstring, `<p class="custom-source">my source</p>`)
+	})
+
+	Convey("A theme may not replace a built-in theme", t, func() {
+		dir := t.TempDir()
+		So(os.WriteFile(filepath.Join(dir, "govuk.tmpl"), []byte(`{{define "footer"}}{{end}}`), 0o600), ShouldBeNil)
+		So(renderer.LoadThemes(dir), ShouldNotBeNil)
+	})
+
+	Convey("A theme that isn't a valid template is an error", t, func() {
+		dir := t.TempDir()
+		So(os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte(`{{define "footer"}}`), 0o600), ShouldBeNil)
+		So(renderer.LoadThemes(dir), ShouldNotBeNil)
+	})
+}
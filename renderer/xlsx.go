@@ -3,15 +3,15 @@ package renderer
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"bytes"
 	"regexp"
 	"strconv"
 
-	"encoding/json"
-
-	"github.com/360EntSecGroup-Skylar/excelize"
+	h "github.com/ONSdigital/dp-table-renderer/htmlutil"
 	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
@@ -44,92 +44,112 @@ var (
 
 // xlsxCellStyle holds those cell formatting properties we want to define
 type xlsxCellStyle struct {
-	NumberFormat       int           `json:"number_format,omitempty"`
-	CustomNumberFormat string        `json:"custom_number_format,omitempty"`
-	Alignment          xlsxAlignment `json:"alignment,omitempty"`
-	Font               xlsxFont      `json:"font,omitempty"`
+	NumberFormat       int
+	CustomNumberFormat string
+	Alignment          xlsxAlignment
+	Font               xlsxFont
 }
 type xlsxAlignment struct {
-	Horizontal string `json:"horizontal,omitempty"`
-	Vertical   string `json:"vertical,omitempty"`
-	WrapText   bool   `json:"wrap_text,omitempty"`
+	Horizontal string
+	Vertical   string
+	WrapText   bool
 }
 type xlsxFont struct {
-	Bold bool `json:"bold,omitempty"`
+	Bold bool
 }
 
 type spreadsheetModel struct {
-	xlsx         *excelize.File
-	styleMap     map[string]int
+	writer       *xlsxWriter
 	cellStyles   map[xlsxCellStyle]int
 	currentRow   int
 	firstDataRow int
-	sheet        string
 	tableModel   *tableModel
 	request      *models.RenderRequest
 }
 
 // RenderXLSX returns an xlsx representation of the table generated from the given request
 func RenderXLSX(ctx context.Context, request *models.RenderRequest) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteXLSX(ctx, &buf, request); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteXLSX writes an xlsx representation of the table generated from the given request to w.
+// Each row is written as it is generated, so the whole spreadsheet is never held in memory.
+func WriteXLSX(ctx context.Context, w io.Writer, request *models.RenderRequest) (err error) {
+	tableModel := createModel(ctx, request)
 
-	xlsx := excelize.NewFile()
+	ctx, span := tracing.StartSpan(ctx, "write", tracing.Format.String("xlsx"))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	writer, err := newXLSXWriter(w, "Sheet1")
+	if err != nil {
+		log.Error(ctx, "unable to start xlsx", err, log.Data{"file_name": request.Filename})
+		return renderError("xlsx", err)
+	}
 
 	model := &spreadsheetModel{
 		request:    request,
-		tableModel: createModel(ctx, request),
+		tableModel: tableModel,
 		cellStyles: make(map[xlsxCellStyle]int),
-		xlsx:       xlsx,
+		writer:     writer,
 		currentRow: 0,
-		sheet:      "Sheet1",
 	}
 
-	insertTitle(ctx, model)
-	insertData(ctx, model)
-	insertUnits(model)
-	insertSource(model)
-	insertFootnotes(model)
-
-	mergeCells(model)
+	steps := []func(context.Context, *spreadsheetModel) error{insertTitle, insertData, insertUnits, insertSource, insertFootnotes}
+	for _, step := range steps {
+		if err = step(ctx, model); err != nil {
+			log.Error(ctx, "unable to write xlsx", err, log.Data{"file_name": request.Filename, "row": model.currentRow})
+			return renderError("xlsx", err)
+		}
+	}
 
-	var buf bytes.Buffer
-	xlsx.Write(&buf)
-	return buf.Bytes(), nil
+	if err = writer.close(mergedRanges(model)); err != nil {
+		log.Error(ctx, "unable to write xlsx", err, log.Data{"file_name": request.Filename})
+		return renderError("xlsx", err)
+	}
+	return nil
 }
 
 // insertTitle inserts title and subtitle in the spreadsheet
-func insertTitle(ctx context.Context, model *spreadsheetModel) {
-	xlsx := model.xlsx
+func insertTitle(ctx context.Context, model *spreadsheetModel) error {
 	request := model.request
+	style := getStyleRef(model, titleFormat)
 
-	axisRef := getAxisRef(model.currentRow, 0)
-	xlsx.SetCellStr(model.sheet, axisRef, request.Title)
-	xlsx.SetCellStyle(model.sheet, axisRef, axisRef, getStyleRef(ctx, model, titleFormat))
+	err := model.writer.writeRow(model.currentRow, []xlsxCell{{col: 0, value: request.Title, style: style}})
+	if err != nil {
+		return err
+	}
 	model.currentRow++
 
-	axisRef = getAxisRef(model.currentRow, 0)
-	xlsx.SetCellStr(model.sheet, axisRef, request.Subtitle)
-	xlsx.SetCellStyle(model.sheet, axisRef, axisRef, getStyleRef(ctx, model, titleFormat))
+	err = model.writer.writeRow(model.currentRow, []xlsxCell{{col: 0, value: request.Subtitle, style: style}})
 	model.currentRow++
+	return err
 }
 
 // insertData inserts each cell of the table in the spreadsheet, unless hidden by a merged cell
-func insertData(ctx context.Context, model *spreadsheetModel) {
-	xlsx := model.xlsx
+func insertData(ctx context.Context, model *spreadsheetModel) error {
 	tableModel := model.tableModel
 	model.firstDataRow = model.currentRow + 1
 
+	var cells []xlsxCell
 	for r, row := range model.request.Data {
 		model.currentRow++
+		cells = cells[:0]
 		for c := range row {
 			if cellIsVisible(tableModel, r, c) {
 				value, style := getCellValueAndStyle(ctx, model, r, c)
-				axisRef := getAxisRef(model.currentRow, c)
-				xlsx.SetCellValue(model.sheet, axisRef, value)
-				xlsx.SetCellStyle(model.sheet, axisRef, axisRef, style)
+				cells = append(cells, xlsxCell{col: c, value: value, style: style})
 			}
 		}
+		if err := model.writer.writeRow(model.currentRow, cells); err != nil {
+			return err
+		}
 	}
 	model.currentRow++
+	return nil
 }
 
 // cellIsVisible returns true if the cell is visible (not hidden by a merged cell)
@@ -138,44 +158,45 @@ func cellIsVisible(tableModel *tableModel, r int, c int) bool {
 }
 
 // insertSource inserts the source in the spreadsheet
-func insertSource(model *spreadsheetModel) {
-	xlsx := model.xlsx
+func insertSource(ctx context.Context, model *spreadsheetModel) error {
 	if len(model.request.Source) > 0 {
 		model.currentRow++
-		xlsx.SetCellStr(model.sheet, getAxisRef(model.currentRow, 0), sourceText)
-		xlsx.SetCellStr(model.sheet, getAxisRef(model.currentRow, 1), model.request.Source)
+		return model.writer.writeRow(model.currentRow, []xlsxCell{{col: 0, value: sourceText}, {col: 1, value: model.request.Source}})
 	}
+	return nil
 }
 
 // insertUnits inserts the units in the spreadsheet
-func insertUnits(model *spreadsheetModel) {
-	xlsx := model.xlsx
+func insertUnits(ctx context.Context, model *spreadsheetModel) error {
 	if len(model.request.Units) > 0 {
 		model.currentRow++
-		xlsx.SetCellStr(model.sheet, getAxisRef(model.currentRow, 0), unitsText)
-		xlsx.SetCellStr(model.sheet, getAxisRef(model.currentRow, 1), model.request.Units)
+		return model.writer.writeRow(model.currentRow, []xlsxCell{{col: 0, value: unitsText}, {col: 1, value: model.request.Units}})
 	}
+	return nil
 }
 
 // insertFootnotes inserts footnotes in the spreadsheet
-func insertFootnotes(model *spreadsheetModel) {
-	xlsx := model.xlsx
+func insertFootnotes(ctx context.Context, model *spreadsheetModel) error {
 	request := model.request
 
 	if len(request.Footnotes) > 0 {
 		model.currentRow++
-		xlsx.SetCellStr(model.sheet, getAxisRef(model.currentRow, 0), notesText)
+		if err := model.writer.writeRow(model.currentRow, []xlsxCell{{col: 0, value: notesText}}); err != nil {
+			return err
+		}
 		for i, note := range request.Footnotes {
 			model.currentRow++
-			xlsx.SetCellStr(model.sheet, getAxisRef(model.currentRow, 0), fmt.Sprintf("%d.", i+1))
-			xlsx.SetCellStr(model.sheet, getAxisRef(model.currentRow, 1), note)
+			if err := model.writer.writeRow(model.currentRow, []xlsxCell{{col: 0, value: fmt.Sprintf("%d.", i+1)}, {col: 1, value: note}}); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
-// mergeCells applies the merge operation to the appropriate cells
-func mergeCells(model *spreadsheetModel) {
-	xlsx := model.xlsx
+// mergedRanges returns the range (e.g. 'A4:B5') of each group of merged cells
+func mergedRanges(model *spreadsheetModel) []string {
+	var merges []string
 	for _, format := range model.request.CellFormats {
 		if format.Rowspan > 1 || format.Colspan > 1 {
 			topRow := format.Row + model.firstDataRow
@@ -189,9 +210,10 @@ func mergeCells(model *spreadsheetModel) {
 				colspan--
 			}
 			bottomRight := getAxisRef(topRow+rowspan, format.Column+colspan)
-			xlsx.MergeCell(model.sheet, topLeft, bottomRight)
+			merges = append(merges, topLeft+":"+bottomRight)
 		}
 	}
+	return merges
 }
 
 // getAxisRef returns the spreadsheet reference for the given cell coordinates, e.g. 'A1' for [0,0]
@@ -210,7 +232,7 @@ func getAxisRef(row int, col int) string {
 
 // getCellValueAndStyle converts the cell value to the appropriate type [string|int|float] and creates the correct cell style for formatting and alignment
 func getCellValueAndStyle(ctx context.Context, model *spreadsheetModel, row int, col int) (interface{}, int) {
-	value := model.request.Data[row][col]
+	value := h.PlainText(model.request.Data[row][col])
 	cellContent, cellStyle, err := parseValueAndFormat(value)
 	if err != nil {
 		log.Error(ctx, "unable to parse value", err, log.Data{"value": value})
@@ -223,7 +245,7 @@ func getCellValueAndStyle(ctx context.Context, model *spreadsheetModel, row int,
 		cellStyle.Font.Bold = true
 		cellStyle.Alignment.WrapText = true
 	}
-	return cellContent, getStyleRef(ctx, model, cellStyle)
+	return cellContent, getStyleRef(model, cellStyle)
 }
 
 // parseValueAndFormat parses the value string into an integer or float if possible, and creates a style with an appropriate number format according to the type and number of decimal places
@@ -254,7 +276,7 @@ func parseValueAndFormat(value string) (interface{}, *xlsxCellStyle, error) {
 
 // getCellAlignmentAndHeading returns the alignment, vertical alignment and whether the cell is a heading
 func getCellAlignmentAndHeading(model *spreadsheetModel, row int, col int) (string, string, bool) {
-	rowFormat := model.tableModel.rows[row]
+	rowFormat := model.tableModel.rowFormat(row)
 	colFormat := model.tableModel.columns[col]
 	cellFormat := model.tableModel.cells[row][col]
 	align := colFormat.Align
@@ -272,20 +294,11 @@ func getCellAlignmentAndHeading(model *spreadsheetModel, row int, col int) (stri
 }
 
 // getStyleRef finds an existing style with the required properties, creating one if none can be found, and returning the index of that style
-func getStyleRef(ctx context.Context, model *spreadsheetModel, format *xlsxCellStyle) int {
+func getStyleRef(model *spreadsheetModel, format *xlsxCellStyle) int {
 	if i, exists := model.cellStyles[*format]; exists {
 		return i
 	}
-	bytes, err := json.Marshal(*format)
-	if err != nil {
-		log.Error(ctx, "unable to marshal an xlsxCellStyle for file", err, log.Data{"file_name": model.request.Filename})
-		return 0
-	}
-	style, err := model.xlsx.NewStyle(string(bytes))
-	if err != nil {
-		log.Error(ctx, "unable to create a new style for the spreadsheet", err, log.Data{"value": string(bytes)})
-		return 0
-	}
+	style := model.writer.addStyle(*format)
 	model.cellStyles[*format] = style
 	return style
 }
@@ -2,9 +2,9 @@ package renderer
 
 import (
 	"context"
+	"io/ioutil"
 	"testing"
 
-	"github.com/360EntSecGroup-Skylar/excelize"
 	"github.com/ONSdigital/dp-table-renderer/models"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -35,13 +35,14 @@ func TestCellStyles(t *testing.T) {
 			{Row: 1, Column: 1, VerticalAlign: "Bottom", Align: "Left"}}
 		request := &models.RenderRequest{Filename: "filename", Data: data, RowFormats: rowFormats, ColumnFormats: colFormats, CellFormats: cellFormats}
 
+		writer, err := newXLSXWriter(ioutil.Discard, "Sheet1")
+		So(err, ShouldBeNil)
 		model := &spreadsheetModel{
 			request:    request,
 			tableModel: createModel(mockContext, request),
 			cellStyles: make(map[xlsxCellStyle]int),
-			xlsx:       excelize.NewFile(),
+			writer:     writer,
 			currentRow: 0,
-			sheet:      "Sheet1",
 		}
 
 		style := invokeGetCellValueAndStyle(model, 0, 0)
@@ -192,6 +192,20 @@ func TestRenderXLSX(t *testing.T) {
 		So(rows[rowOffset+1][2], ShouldEqual, data[1][2])
 	})
 
+	Convey("Values containing < or > that isn't markup should be kept in the spreadsheet", t, func() {
+		request := models.RenderRequest{Filename: "filename", Data: [][]string{{"<LOD", "a<b and c>d", "<strong>&lt;5</strong>"}}}
+
+		resultBytes, e := renderer.RenderXLSX(mockContext, &request)
+		So(e, ShouldBeNil)
+
+		xlsx, e := excelize.OpenReader(bytes.NewReader(resultBytes))
+		So(e, ShouldBeNil)
+		rows := xlsx.GetRows(xlsx.GetSheetMap()[1])
+
+		rowOffset := getDataRowOffset(&request)
+		So(rows[rowOffset], ShouldResemble, []string{"<LOD", "a<b and c>d", "<5"})
+	})
+
 }
 
 func getDataRowOffset(request *models.RenderRequest) int {
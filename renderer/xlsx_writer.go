@@ -0,0 +1,205 @@
+package renderer
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// the parts of the workbook that don't depend on the content of the table
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/></Types>`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheetData>`
+
+	// the first custom number format must have an id above the range reserved for built in formats
+	firstCustomNumberFormat = 164
+)
+
+// xlsxCell is a single cell in a row written by the xlsxWriter
+type xlsxCell struct {
+	col   int
+	value interface{} // a string, int or float64
+	style int         // a style reference returned from addStyle
+}
+
+// xlsxWriter writes a workbook with a single worksheet directly to an io.Writer. Each row is written to the worksheet
+// as soon as it is added, so memory use doesn't grow with the size of the table. Styles are collected as the rows
+// are written, and the stylesheet is written when the workbook is closed.
+type xlsxWriter struct {
+	zip           *zip.Writer
+	sheet         *bufio.Writer
+	styles        []xlsxCellStyle
+	numberFormats map[string]int // the ids of custom number formats, keyed by format code
+	formatCodes   []string       // the custom number format codes, in order of id
+}
+
+// newXLSXWriter starts writing a workbook to w, returning a writer that is ready to accept rows
+func newXLSXWriter(w io.Writer, sheetName string) (*xlsxWriter, error) {
+	x := &xlsxWriter{
+		zip:           zip.NewWriter(w),
+		numberFormats: make(map[string]int),
+	}
+	var sheetNameBuf strings.Builder
+	if err := xml.EscapeText(&sheetNameBuf, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, sheetNameBuf.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		if err := x.writePart(part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+	sheet, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	x.sheet = bufio.NewWriter(sheet)
+	_, err = x.sheet.WriteString(xlsxSheetStart)
+	return x, err
+}
+
+// writePart writes a complete part of the workbook
+func (x *xlsxWriter) writePart(name string, content string) error {
+	w, err := x.zip.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, content)
+	return err
+}
+
+// addStyle registers a new cell style, returning the reference to use for cells with that style
+func (x *xlsxWriter) addStyle(style xlsxCellStyle) int {
+	if len(style.CustomNumberFormat) > 0 {
+		if _, exists := x.numberFormats[style.CustomNumberFormat]; !exists {
+			x.numberFormats[style.CustomNumberFormat] = firstCustomNumberFormat + len(x.formatCodes)
+			x.formatCodes = append(x.formatCodes, style.CustomNumberFormat)
+		}
+	}
+	x.styles = append(x.styles, style)
+	// style 0 is the default style
+	return len(x.styles)
+}
+
+// writeRow writes the cells of a row to the worksheet. Rows must be written in ascending order, and the cells
+// in a row in ascending column order.
+func (x *xlsxWriter) writeRow(row int, cells []xlsxCell) error {
+	w := x.sheet
+	fmt.Fprintf(w, `<row r="%d">`, row+1)
+	for _, cell := range cells {
+		fmt.Fprintf(w, `<c r="%s"`, getAxisRef(row, cell.col))
+		if cell.style > 0 {
+			fmt.Fprintf(w, ` s="%d"`, cell.style)
+		}
+		switch v := cell.value.(type) {
+		case int:
+			fmt.Fprintf(w, `><v>%d</v></c>`, v)
+		case float64:
+			fmt.Fprintf(w, `><v>%s</v></c>`, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			w.WriteString(` t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(w, []byte(fmt.Sprint(v))); err != nil {
+				return err
+			}
+			w.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := w.WriteString(`</row>`)
+	return err
+}
+
+// close finishes the worksheet, adding the given merged ranges (e.g. 'A1:B2'), then writes the stylesheet
+func (x *xlsxWriter) close(merges []string) error {
+	w := x.sheet
+	w.WriteString(`</sheetData>`)
+	if len(merges) > 0 {
+		fmt.Fprintf(w, `<mergeCells count="%d">`, len(merges))
+		for _, ref := range merges {
+			fmt.Fprintf(w, `<mergeCell ref="%s"/>`, ref)
+		}
+		w.WriteString(`</mergeCells>`)
+	}
+	w.WriteString(`</worksheet>`)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := x.writePart("xl/styles.xml", x.stylesheet()); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// stylesheet generates the xml for all the styles that have been added
+func (x *xlsxWriter) stylesheet() string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(x.formatCodes) > 0 {
+		fmt.Fprintf(&b, `<numFmts count="%d">`, len(x.formatCodes))
+		for _, code := range x.formatCodes {
+			fmt.Fprintf(&b, `<numFmt numFmtId="%d" formatCode="%s"/>`, x.numberFormats[code], code)
+		}
+		b.WriteString(`</numFmts>`)
+	}
+	b.WriteString(`<fonts count="2"><font><sz val="11"/><name val="Calibri"/><family val="2"/></font><font><b/><sz val="11"/><name val="Calibri"/><family val="2"/></font></fonts>`)
+	b.WriteString(`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>`)
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&b, `<cellXfs count="%d"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>`, len(x.styles)+1)
+	for _, style := range x.styles {
+		numberFormat := style.NumberFormat
+		if len(style.CustomNumberFormat) > 0 {
+			numberFormat = x.numberFormats[style.CustomNumberFormat]
+		}
+		font := 0
+		if style.Font.Bold {
+			font = 1
+		}
+		fmt.Fprintf(&b, `<xf numFmtId="%d" fontId="%d" fillId="0" borderId="0" xfId="0"`, numberFormat, font)
+		if numberFormat > 0 {
+			b.WriteString(` applyNumberFormat="1"`)
+		}
+		if font > 0 {
+			b.WriteString(` applyFont="1"`)
+		}
+		alignment := style.Alignment
+		if alignment == (xlsxAlignment{}) {
+			b.WriteString(`/>`)
+			continue
+		}
+		b.WriteString(` applyAlignment="1"><alignment`)
+		if len(alignment.Horizontal) > 0 {
+			fmt.Fprintf(&b, ` horizontal="%s"`, alignment.Horizontal)
+		}
+		if len(alignment.Vertical) > 0 {
+			fmt.Fprintf(&b, ` vertical="%s"`, alignment.Vertical)
+		}
+		if alignment.WrapText {
+			b.WriteString(` wrapText="1"`)
+		}
+		b.WriteString(`/></xf>`)
+	}
+	b.WriteString(`</cellXfs><cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles></styleSheet>`)
+	return b.String()
+}
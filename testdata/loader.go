@@ -3,6 +3,7 @@ package testdata
 import (
 	"io/ioutil"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -16,8 +17,19 @@ func LoadExampleHandsonTable(t *testing.T) []byte {
 	return loadTestdata(t, "exampleHandsonTable.json")
 }
 
+// LoadExampleDocx reads the Word document containing a table of two rows from example.docx
+func LoadExampleDocx(t *testing.T) []byte {
+	return loadTestdata(t, "example.docx")
+}
+
+// LoadExampleOds reads the OpenDocument spreadsheet whose sheet contains two rows from example.ods
+func LoadExampleOds(t *testing.T) []byte {
+	return loadTestdata(t, "example.ods")
+}
+
 func loadTestdata(t *testing.T, name string) []byte {
-	path := filepath.Join("../testdata", name) // relative path
+	_, loader, _, _ := runtime.Caller(0) // this file, so the path does not depend on the package under test
+	path := filepath.Join(filepath.Dir(loader), name)
 	bytes, err := ioutil.ReadFile(path)
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/ONSdigital/dp-table-renderer"
+
+// Attributes added to spans
+const (
+	Format      = attribute.Key("table.format")
+	Filename    = attribute.Key("table.filename")
+	Rows        = attribute.Key("table.rows")
+	Columns     = attribute.Key("table.columns")
+	Merges      = attribute.Key("table.merges")
+	OutputBytes = attribute.Key("table.output_bytes")
+)
+
+// StartSpan starts a span as a child of the span in ctx, using the same TracerProvider as the parent.
+// If ctx doesn't contain a span, the global TracerProvider is used - a no-op unless OpenTelemetry has been set up.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	provider := otel.GetTracerProvider()
+	if parent := trace.SpanFromContext(ctx); parent.SpanContext().IsValid() {
+		provider = parent.TracerProvider()
+	}
+	return provider.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records the error, if there is one, and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TableAttributes returns the attributes describing the size of the table in a request
+func TableAttributes(request *models.RenderRequest) []attribute.KeyValue {
+	size := request.Size()
+	return []attribute.KeyValue{
+		Rows.Int(size.Rows),
+		Columns.Int(size.Columns),
+		Merges.Int(size.Merges),
+	}
+}
+
+// RecordError records the error on the span in ctx, marking the span as failed
+func RecordError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
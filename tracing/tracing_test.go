@@ -0,0 +1,77 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-table-renderer/models"
+	"github.com/ONSdigital/dp-table-renderer/tracing"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpans(t *testing.T) {
+	Convey("Given a parent span from a tracer provider with an in-memory exporter", t, func() {
+		exporter := tracetest.NewInMemoryExporter()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+
+		Convey("A span is created as a child of the parent, using the same provider", func() {
+			_, span := tracing.StartSpan(ctx, "child", tracing.Format.String("csv"))
+			tracing.EndSpan(span, nil)
+			parent.End()
+
+			spans := exporter.GetSpans()
+			So(spans, ShouldHaveLength, 2)
+			So(spans[0].Name, ShouldEqual, "child")
+			So(spans[0].Parent.SpanID(), ShouldEqual, parent.SpanContext().SpanID())
+			So(spans[0].Attributes, ShouldContain, attribute.String("table.format", "csv"))
+			So(spans[0].Status.Code, ShouldEqual, codes.Unset)
+		})
+
+		Convey("An error is recorded when the span is ended", func() {
+			_, span := tracing.StartSpan(ctx, "child")
+			tracing.EndSpan(span, errors.New("failed"))
+
+			spans := exporter.GetSpans()
+			So(spans, ShouldHaveLength, 1)
+			So(spans[0].Status.Code, ShouldEqual, codes.Error)
+			So(spans[0].Status.Description, ShouldEqual, "failed")
+			So(spans[0].Events, ShouldHaveLength, 1)
+			So(spans[0].Events[0].Name, ShouldEqual, "exception")
+		})
+
+		Convey("An error can be recorded on the span in a context", func() {
+			tracing.RecordError(ctx, errors.New("failed"))
+			parent.End()
+
+			spans := exporter.GetSpans()
+			So(spans, ShouldHaveLength, 1)
+			So(spans[0].Status.Code, ShouldEqual, codes.Error)
+		})
+	})
+
+	Convey("Without a parent span, the global no-op provider is used", t, func() {
+		_, span := tracing.StartSpan(context.Background(), "orphan")
+		So(span.IsRecording(), ShouldBeFalse)
+		tracing.EndSpan(span, nil)
+	})
+}
+
+func TestTableAttributes(t *testing.T) {
+	Convey("The size of the table is described by the attributes", t, func() {
+		request := &models.RenderRequest{
+			Data:        [][]string{{"a", "b"}, {"c", "d"}, {"e", "f"}},
+			CellFormats: []models.CellFormat{{Row: 0, Column: 0, Colspan: 2}},
+		}
+		So(tracing.TableAttributes(request), ShouldResemble, []attribute.KeyValue{
+			attribute.Int("table.rows", 3),
+			attribute.Int("table.columns", 2),
+			attribute.Int("table.merges", 1),
+		})
+	})
+}